@@ -25,10 +25,33 @@ const (
 	// InstanceRoleCredentialTTL duration of expiration we retrieve new instance role credentials
 	InstanceRoleCredentialTTL     = 15 * time.Minute
 	RoleSessionNameMaxLength  int = 64
+	// GameSessionIDMaxLength max length of a game session ID, which is the game session ARN,
+	// checked by every message and request carrying a GameSessionId
+	GameSessionIDMaxLength = 1024
 	// ReconnectOnReadWriteFailureNumber Number of consecutive read/write failures before reconnect is called
 	ReconnectOnReadWriteFailureNumber int = 2
 	// MaxReadWriteRetry The max number of retries after consecutive read/write failures, including the reconnect described above
 	MaxReadWriteRetry int = 5
+	// WebsocketPingIntervalDefault interval between keepalive pings sent to GameLift
+	WebsocketPingIntervalDefault = 30 * time.Second
+	// WebsocketPongTimeoutDefault time to wait for a pong (or any other message) after a ping before the connection is considered dead
+	WebsocketPongTimeoutDefault = 10 * time.Second
+	// WebsocketReadLimitDefault max size in bytes of an incoming message, zero means no limit
+	WebsocketReadLimitDefault = 0
+	// WebsocketLargeMessageSizeDefault size in bytes starting from which messages are logged as large
+	WebsocketLargeMessageSizeDefault = 1024 * 1024
+	// PlayerSessionCacheMaxEntriesDefault max number of cached DescribePlayerSessions results
+	PlayerSessionCacheMaxEntriesDefault = 1024
+	// RequestRetryMaxAttemptsDefault max number of attempts of an idempotent request, including the first one
+	RequestRetryMaxAttemptsDefault = 3
+	// RequestRetryBaseDelayDefault max delay before the first retry of an idempotent request, doubled for every next retry
+	RequestRetryBaseDelayDefault = 500 * time.Millisecond
+	// RequestRetryMaxDelayDefault max delay before a retry of an idempotent request
+	RequestRetryMaxDelayDefault = 5 * time.Second
+	// DispatcherWorkersDefault max number of unordered messages, such as unknown messages, handled at the same time
+	DispatcherWorkersDefault = 16
+	// DispatcherQueueSizeDefault number of lifecycle messages of one game session waiting for the previous ones above which the queue is full
+	DispatcherQueueSizeDefault = 16
 )
 
 const (
@@ -57,6 +80,22 @@ const (
 	HealthcheckMaxJitter = "HEALTHCHECK_MAX_JITTER"
 	HealthcheckInterval  = "HEALTHCHECK_INTERVAL"
 	HealthcheckTimeout   = "HEALTHCHECK_TIMEOUT"
+
+	WebsocketPingInterval     = "WEBSOCKET_PING_INTERVAL"
+	WebsocketPongTimeout      = "WEBSOCKET_PONG_TIMEOUT"
+	WebsocketReadLimit        = "WEBSOCKET_READ_LIMIT"
+	WebsocketLargeMessageSize = "WEBSOCKET_LARGE_MESSAGE_SIZE"
+
+	PlayerSessionCacheTTL        = "PLAYER_SESSION_CACHE_TTL"
+	PlayerSessionCacheMaxEntries = "PLAYER_SESSION_CACHE_MAX_ENTRIES"
+
+	RequestTimeouts         = "REQUEST_TIMEOUTS"
+	RequestRetryMaxAttempts = "REQUEST_RETRY_MAX_ATTEMPTS"
+	RequestRetryBaseDelay   = "REQUEST_RETRY_BASE_DELAY"
+	RequestRetryMaxDelay    = "REQUEST_RETRY_MAX_DELAY"
+
+	DispatcherWorkers   = "DISPATCHER_WORKERS"
+	DispatcherQueueSize = "DISPATCHER_QUEUE_SIZE"
 )
 
 const (
@@ -71,4 +110,19 @@ const (
 	EnvironmentKeyAccessKey    string = "GAMELIFT_ACCESS_KEY"
 	EnvironmentKeySecretKey    string = "GAMELIFT_SECRET_KEY"
 	EnvironmentKeySessionToken string = "GAMELIFT_SESSION_TOKEN"
+
+	//nolint:gosec // false positive
+	EnvironmentKeyAuthTokenFile string = "GAMELIFT_SDK_AUTH_TOKEN_FILE"
+
+	EnvironmentKeyProxyURL       string = "GAMELIFT_SDK_PROXY_URL"
+	EnvironmentKeyRootCAFile     string = "GAMELIFT_SDK_ROOT_CA_FILE"
+	EnvironmentKeyClientCertFile string = "GAMELIFT_SDK_CLIENT_CERT_FILE"
+	EnvironmentKeyClientKeyFile  string = "GAMELIFT_SDK_CLIENT_KEY_FILE"
+	EnvironmentKeyMinTLSVersion  string = "GAMELIFT_SDK_MIN_TLS_VERSION"
+
+	EnvironmentKeyWebsocketCompression string = "GAMELIFT_SDK_WEBSOCKET_COMPRESSION"
+
+	EnvironmentKeyConfigFile string = "GAMELIFT_SDK_CONFIG_FILE"
+
+	EnvironmentKeyRecordFile string = "GAMELIFT_SDK_RECORD_FILE"
 )
@@ -0,0 +1,135 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Sentinels of the GameLiftError types, use errors.Is to check the type of an error:
+//
+//	if errors.Is(err, common.ErrProcessNotReady) { ... }
+var (
+	ErrAlreadyInitialized                   error = NewGameLiftError(AlreadyInitialized, "", "")
+	ErrFleetMismatch                        error = NewGameLiftError(FleetMismatch, "", "")
+	ErrGameLiftClientNotInitialized         error = NewGameLiftError(GameLiftClientNotInitialized, "", "")
+	ErrGameLiftServerNotInitialized         error = NewGameLiftError(GameLiftServerNotInitialized, "", "")
+	ErrGameSessionEndedFailed               error = NewGameLiftError(GameSessionEndedFailed, "", "")
+	ErrGameSessionNotReady                  error = NewGameLiftError(GameSessionNotReady, "", "")
+	ErrGameSessionReadyFailed               error = NewGameLiftError(GameSessionReadyFailed, "", "")
+	ErrGamesessionIDNotSet                  error = NewGameLiftError(GamesessionIDNotSet, "", "")
+	ErrInitializationMismatch               error = NewGameLiftError(InitializationMismatch, "", "")
+	ErrNotInitialized                       error = NewGameLiftError(NotInitialized, "", "")
+	ErrNoTargetAliasIDSet                   error = NewGameLiftError(NoTargetAliasIDSet, "", "")
+	ErrNoTargetFleetSet                     error = NewGameLiftError(NoTargetFleetSet, "", "")
+	ErrProcessEndingFailed                  error = NewGameLiftError(ProcessEndingFailed, "", "")
+	ErrProcessNotActive                     error = NewGameLiftError(ProcessNotActive, "", "")
+	ErrProcessNotReady                      error = NewGameLiftError(ProcessNotReady, "", "")
+	ErrProcessReadyFailed                   error = NewGameLiftError(ProcessReadyFailed, "", "")
+	ErrSdkVersionDetectionFailed            error = NewGameLiftError(SdkVersionDetectionFailed, "", "")
+	ErrServiceCallFailed                    error = NewGameLiftError(ServiceCallFailed, "", "")
+	ErrUnexpectedPlayerSession              error = NewGameLiftError(UnexpectedPlayerSession, "", "")
+	ErrLocalConnectionFailed                error = NewGameLiftError(LocalConnectionFailed, "", "")
+	ErrNetworkNotInitialized                error = NewGameLiftError(NetworkNotInitialized, "", "")
+	ErrTerminationTimeNotSet                error = NewGameLiftError(TerminationTimeNotSet, "", "")
+	ErrBadRequestException                  error = NewGameLiftError(BadRequestException, "", "")
+	ErrInternalServiceException             error = NewGameLiftError(InternalServiceException, "", "")
+	ErrWebsocketConnectFailure              error = NewGameLiftError(WebsocketConnectFailure, "", "")
+	ErrWebsocketRetriableSendMessageFailure error = NewGameLiftError(WebsocketRetriableSendMessageFailure, "", "")
+	ErrWebsocketSendMessageFailure          error = NewGameLiftError(WebsocketSendMessageFailure, "", "")
+	ErrWebsocketClosingError                error = NewGameLiftError(WebsocketClosingError, "", "")
+	ErrCredentialsFetchFailed               error = NewGameLiftError(CredentialsFetchFailed, "", "")
+	ErrMetadataFetchFailed                  error = NewGameLiftError(MetadataFetchFailed, "", "")
+	ErrRequestSigningFailed                 error = NewGameLiftError(RequestSigningFailed, "", "")
+)
+
+// ErrResponseTimeout - the cause of the errors of requests whose response wasn't received within the time limit.
+var ErrResponseTimeout = errors.New("response not received within time limit")
+
+// retryableErrorTypes - the types of errors caused by transient connection or service failures.
+var retryableErrorTypes = map[GameLiftErrorType]bool{
+	InternalServiceException:             true,
+	LocalConnectionFailed:                true,
+	WebsocketConnectFailure:              true,
+	WebsocketRetriableSendMessageFailure: true,
+	WebsocketSendMessageFailure:          true,
+	MetadataFetchFailed:                  true,
+}
+
+// authErrorTypes - the types of errors caused by missing or invalid credentials.
+var authErrorTypes = map[GameLiftErrorType]bool{
+	CredentialsFetchFailed: true,
+	RequestSigningFailed:   true,
+}
+
+// IsTimeout - reports whether the error was caused by a timeout: a response not received within the time limit,
+// a deadline exceeded, a network timeout or a 408/504 response of GameLift.
+func IsTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrResponseTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var timeoutErr interface{ Timeout() bool }
+	if errors.As(err, &timeoutErr) && timeoutErr.Timeout() {
+		return true
+	}
+	statusCode := statusCodeOf(err)
+	return statusCode == http.StatusRequestTimeout || statusCode == http.StatusGatewayTimeout
+}
+
+// IsAuth - reports whether the error was caused by missing or invalid credentials or auth token:
+// a credentials fetch or signing failure or a 401/403 response of GameLift.
+func IsAuth(err error) bool {
+	if err == nil {
+		return false
+	}
+	statusCode := statusCodeOf(err)
+	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+		return true
+	}
+	return hasErrorType(err, authErrorTypes)
+}
+
+// IsRetryable - reports whether the call that returned the error may succeed if it is retried:
+// timeouts, throttling, 5xx responses of GameLift and connection failures. Auth errors and bad requests are permanent.
+func IsRetryable(err error) bool {
+	if err == nil || IsAuth(err) {
+		return false
+	}
+	if IsTimeout(err) {
+		return true
+	}
+	if statusCode := statusCodeOf(err); statusCode != 0 {
+		return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+	}
+	return hasErrorType(err, retryableErrorTypes)
+}
+
+// statusCodeOf - returns the status code of the first GameLiftError with one in the chain, zero if there is none.
+func statusCodeOf(err error) int {
+	for err != nil {
+		if gameLiftErr, ok := err.(*GameLiftError); ok && gameLiftErr.StatusCode != 0 {
+			return gameLiftErr.StatusCode
+		}
+		err = errors.Unwrap(err)
+	}
+	return 0
+}
+
+// hasErrorType - reports whether any GameLiftError in the chain has one of the types.
+func hasErrorType(err error, types map[GameLiftErrorType]bool) bool {
+	for err != nil {
+		if gameLiftErr, ok := err.(*GameLiftError); ok && types[gameLiftErr.ErrorType] {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 )
 
+// GameLiftErrorType - the type of a GameLiftError. Use errors.Is with the Err* sentinels to check for a type.
 type GameLiftErrorType int
 
 const (
@@ -69,8 +70,57 @@ const (
 	WebsocketSendMessageFailure
 	// WebsocketClosingError - An error may occur when try close a websocket.
 	WebsocketClosingError
+	// CredentialsFetchFailed - The AWS credentials or the auth token used to connect to GameLift could not be retrieved.
+	CredentialsFetchFailed
+	// MetadataFetchFailed - The metadata of the container task could not be retrieved.
+	MetadataFetchFailed
+	// RequestSigningFailed - The connection request to GameLift could not be signed with SigV4.
+	RequestSigningFailed
 )
 
+// errorTypeNames - stable names of the error types returned by GameLiftErrorType.String.
+var errorTypeNames = map[GameLiftErrorType]string{
+	AlreadyInitialized:                   "AlreadyInitialized",
+	FleetMismatch:                        "FleetMismatch",
+	GameLiftClientNotInitialized:         "GameLiftClientNotInitialized",
+	GameLiftServerNotInitialized:         "GameLiftServerNotInitialized",
+	GameSessionEndedFailed:               "GameSessionEndedFailed",
+	GameSessionNotReady:                  "GameSessionNotReady",
+	GameSessionReadyFailed:               "GameSessionReadyFailed",
+	GamesessionIDNotSet:                  "GamesessionIDNotSet",
+	InitializationMismatch:               "InitializationMismatch",
+	NotInitialized:                       "NotInitialized",
+	NoTargetAliasIDSet:                   "NoTargetAliasIDSet",
+	NoTargetFleetSet:                     "NoTargetFleetSet",
+	ProcessEndingFailed:                  "ProcessEndingFailed",
+	ProcessNotActive:                     "ProcessNotActive",
+	ProcessNotReady:                      "ProcessNotReady",
+	ProcessReadyFailed:                   "ProcessReadyFailed",
+	SdkVersionDetectionFailed:            "SdkVersionDetectionFailed",
+	ServiceCallFailed:                    "ServiceCallFailed",
+	UnexpectedPlayerSession:              "UnexpectedPlayerSession",
+	LocalConnectionFailed:                "LocalConnectionFailed",
+	NetworkNotInitialized:                "NetworkNotInitialized",
+	TerminationTimeNotSet:                "TerminationTimeNotSet",
+	BadRequestException:                  "BadRequestException",
+	InternalServiceException:             "InternalServiceException",
+	WebsocketConnectFailure:              "WebsocketConnectFailure",
+	WebsocketRetriableSendMessageFailure: "WebsocketRetriableSendMessageFailure",
+	WebsocketSendMessageFailure:          "WebsocketSendMessageFailure",
+	WebsocketClosingError:                "WebsocketClosingError",
+	CredentialsFetchFailed:               "CredentialsFetchFailed",
+	MetadataFetchFailed:                  "MetadataFetchFailed",
+	RequestSigningFailed:                 "RequestSigningFailed",
+}
+
+// String - returns the stable name of the error type, e.g. "ProcessNotReady", suitable for logs and metrics.
+func (t GameLiftErrorType) String() string {
+	if name, ok := errorTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("GameLiftErrorType(%d)", int(t))
+}
+
 type errorDescription struct {
 	name    string
 	message string
@@ -196,12 +246,27 @@ var errorMessages = map[GameLiftErrorType]errorDescription{
 		name:    "WebSocket close error",
 		message: "An error has occurred in closing the connection",
 	},
+	CredentialsFetchFailed: {
+		name:    "Credentials fetch failed.",
+		message: "The AWS credentials or the auth token used to connect to GameLift could not be retrieved.",
+	},
+	MetadataFetchFailed: {
+		name:    "Metadata fetch failed.",
+		message: "The container task metadata could not be retrieved.",
+	},
+	RequestSigningFailed: {
+		name:    "Request signing failed.",
+		message: "The connection request to GameLift could not be signed with SigV4.",
+	},
 }
 
 // GameLiftError -  represents an errors in GameLift SDK.
 type GameLiftError struct {
 	ErrorType GameLiftErrorType
+	// StatusCode - the HTTP status code of the GameLift response, zero if the error didn't come from a response.
+	StatusCode int
 	errorDescription
+	cause error
 }
 
 // NewGameLiftError - creates a new GameLiftError.
@@ -220,9 +285,59 @@ func NewGameLiftError(errorType GameLiftErrorType, name, message string) error {
 	}
 }
 
+// NewGameLiftErrorWithCause - creates a new GameLiftError wrapping the cause, e.g. a websocket, json or http error.
+// The message defaults to the message of the cause.
+//
+// Example:
+//
+//	err := common.NewGameLiftErrorWithCause(common.ServiceCallFailed, "Failed serialize data", "", err)
+func NewGameLiftErrorWithCause(errorType GameLiftErrorType, name, message string, cause error) error {
+	if message == "" && cause != nil {
+		message = cause.Error()
+	}
+	return &GameLiftError{
+		ErrorType: errorType,
+		errorDescription: errorDescription{
+			name:    name,
+			message: message,
+		},
+		cause: cause,
+	}
+}
+
 // NewGameLiftErrorFromStatusCode - convert statusCode and errorMessage to the GameLiftError.
+// The status code and the error message of the service are preserved in StatusCode and Message.
 func NewGameLiftErrorFromStatusCode(statusCode int, errorMessage string) error {
-	return NewGameLiftError(getErrorTypeForStatusCode(statusCode), "", errorMessage)
+	return &GameLiftError{
+		ErrorType:  getErrorTypeForStatusCode(statusCode),
+		StatusCode: statusCode,
+		errorDescription: errorDescription{
+			message: errorMessage,
+		},
+	}
+}
+
+// Name - returns the name of the error, or the default name of its type.
+func (e *GameLiftError) Name() string {
+	return e.getNameOrDefaultForErrorType()
+}
+
+// Message - returns the message of the error, e.g. the error message of the service,
+// or the default message of its type.
+func (e *GameLiftError) Message() string {
+	return e.getMessageOrDefaultForErrorType()
+}
+
+// Unwrap - returns the underlying cause of the error, nil if there is none.
+func (e *GameLiftError) Unwrap() error {
+	return e.cause
+}
+
+// Is - reports whether target is a GameLiftError of the same type, so errors.Is(err, common.ErrProcessNotReady)
+// matches any error of the ProcessNotReady type.
+func (e *GameLiftError) Is(target error) bool {
+	t, ok := target.(*GameLiftError)
+	return ok && t.ErrorType == e.ErrorType
 }
 
 func (e *GameLiftError) Error() string {
@@ -6,6 +6,10 @@
 package common
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"testing"
 )
 
@@ -46,3 +50,114 @@ func TestNewGameLiftError(t *testing.T) {
 
 	}
 }
+
+func TestGameLiftErrorType_String(t *testing.T) {
+	for errType := range errorMessages {
+		name, ok := errorTypeNames[errType]
+		if !ok || errType.String() != name {
+			t.Fatalf("missing stable name of the error type %d", errType)
+		}
+	}
+	if ProcessNotReady.String() != "ProcessNotReady" {
+		t.Fatalf("unexpected name %s", ProcessNotReady)
+	}
+	if GameLiftErrorType(1000).String() != "GameLiftErrorType(1000)" {
+		t.Fatalf("unexpected name of an unknown type %s", GameLiftErrorType(1000))
+	}
+}
+
+// GIVEN error wrapping a cause WHEN errors.Is and errors.As are used THEN the type and the cause are matched
+func TestGameLiftError_IsAndUnwrap(t *testing.T) {
+	// GIVEN
+	cause := errors.New("test cause")
+	err := fmt.Errorf("wrapped: %w", NewGameLiftErrorWithCause(ProcessNotReady, "", "", cause))
+
+	// THEN
+	if !errors.Is(err, ErrProcessNotReady) {
+		t.Fatal("expected ErrProcessNotReady")
+	}
+	if errors.Is(err, ErrProcessNotActive) {
+		t.Fatal("unexpected ErrProcessNotActive")
+	}
+	if !errors.Is(err, cause) {
+		t.Fatal("expected the cause")
+	}
+	var gameLiftErr *GameLiftError
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.Message() != "test cause" || gameLiftErr.Name() != "Process not ready." {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+// GIVEN error response of the service WHEN the error is created THEN the status code and the message are preserved
+func TestNewGameLiftErrorFromStatusCode(t *testing.T) {
+	// WHEN
+	err := NewGameLiftErrorFromStatusCode(http.StatusConflict, "test service message")
+
+	// THEN
+	var gameLiftErr *GameLiftError
+	if !errors.As(err, &gameLiftErr) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if gameLiftErr.ErrorType != BadRequestException || gameLiftErr.StatusCode != http.StatusConflict ||
+		gameLiftErr.Message() != "test service message" {
+		t.Fatalf("unexpected error %+v", gameLiftErr)
+	}
+}
+
+type testNetError struct{ timeout bool }
+
+func (e testNetError) Error() string   { return "test net error" }
+func (e testNetError) Timeout() bool   { return e.timeout }
+func (e testNetError) Temporary() bool { return false }
+
+func TestErrorClassification(t *testing.T) {
+	tests := []struct {
+		name                       string
+		err                        error
+		retryable, timeout, isAuth bool
+	}{
+		{name: "nil", err: nil},
+		{
+			name:      "response timeout",
+			err:       NewGameLiftErrorWithCause(ProcessNotReady, "", "", NewGameLiftErrorWithCause(ServiceCallFailed, "", "", ErrResponseTimeout)),
+			retryable: true,
+			timeout:   true,
+		},
+		{
+			name:      "deadline exceeded",
+			err:       fmt.Errorf("test: %w", context.DeadlineExceeded),
+			retryable: true,
+			timeout:   true,
+		},
+		{
+			name:      "network timeout",
+			err:       NewGameLiftErrorWithCause(WebsocketConnectFailure, "", "", testNetError{timeout: true}),
+			retryable: true,
+			timeout:   true,
+		},
+		{name: "connect failure", err: NewGameLiftErrorWithCause(WebsocketConnectFailure, "", "", testNetError{}), retryable: true},
+		{name: "5xx", err: NewGameLiftErrorFromStatusCode(http.StatusInternalServerError, ""), retryable: true},
+		{name: "504", err: NewGameLiftErrorFromStatusCode(http.StatusGatewayTimeout, ""), retryable: true, timeout: true},
+		{name: "429", err: NewGameLiftErrorFromStatusCode(http.StatusTooManyRequests, ""), retryable: true},
+		{name: "400", err: NewGameLiftErrorFromStatusCode(http.StatusBadRequest, "")},
+		{name: "403", err: NewGameLiftErrorFromStatusCode(http.StatusForbidden, ""), isAuth: true},
+		{name: "credentials", err: NewGameLiftError(CredentialsFetchFailed, "", ""), isAuth: true},
+		{name: "signing", err: NewGameLiftError(RequestSigningFailed, "", ""), isAuth: true},
+		{name: "bad request", err: NewGameLiftError(BadRequestException, "", "")},
+		{name: "not active", err: NewGameLiftError(ProcessNotActive, "", "")},
+		{name: "other error", err: errors.New("test error")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.retryable {
+				t.Errorf("IsRetryable(%v) = %t, want %t", tt.err, got, tt.retryable)
+			}
+			if got := IsTimeout(tt.err); got != tt.timeout {
+				t.Errorf("IsTimeout(%v) = %t, want %t", tt.err, got, tt.timeout)
+			}
+			if got := IsAuth(tt.err); got != tt.isAuth {
+				t.Errorf("IsAuth(%v) = %t, want %t", tt.err, got, tt.isAuth)
+			}
+		})
+	}
+}
@@ -3,11 +3,17 @@ module aws/amazon-gamelift-go-sdk
 go 1.18
 
 require (
+	github.com/aws/aws-sdk-go-v2 v1.21.2
 	github.com/golang/mock v1.6.0
 	github.com/google/uuid v1.5.0
 	github.com/gorilla/websocket v1.5.1
 	github.com/sethvargo/go-retry v0.2.4
 	go.uber.org/goleak v1.2.0
+	golang.org/x/net v0.33.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
-require golang.org/x/net v0.33.0 // indirect
+require (
+	github.com/aws/smithy-go v1.15.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+)
@@ -6,17 +6,20 @@
 package model
 
 import (
+	"bytes"
 	"encoding/json"
-	"strconv"
+	"errors"
+	"fmt"
+	"sort"
 	"strings"
 )
 
-// attributeType - unexported (private) data type, has a several predefined values see below.
-type attributeType int
+// AttributeType - the data type of an AttributeValue, has a several predefined values see below.
+type AttributeType int
 
 // Possible values for attribute types
 const (
-	None attributeType = iota
+	None AttributeType = iota
 	String
 	Double
 	StringList
@@ -25,30 +28,42 @@ const (
 
 var attributeTypesStr = []string{"NONE", "STRING", "DOUBLE", "STRING_LIST", "STRING_DOUBLE_MAP"}
 
-func (a *attributeType) String() string {
-	n := int(*a)
-	if n >= len(attributeTypesStr) {
+// ParseAttributeType - returns the type with the name, such as "STRING", ignoring the case,
+// or an error if the name is not one of the String values.
+func ParseAttributeType(s string) (AttributeType, error) {
+	for i := range attributeTypesStr {
+		if strings.EqualFold(attributeTypesStr[i], s) {
+			return AttributeType(i), nil
+		}
+	}
+	return None, fmt.Errorf("unknown attribute type %q", s)
+}
+
+// AttributeTypeValues - returns the types an AttributeValue can have, all except None.
+func AttributeTypeValues() []AttributeType {
+	return []AttributeType{String, Double, StringList, StringDoubleMap}
+}
+
+func (a AttributeType) String() string {
+	n := int(a)
+	if n < 0 || n >= len(attributeTypesStr) {
 		n = 0
 	}
 	return attributeTypesStr[n]
 }
 
-func (a *attributeType) MarshalJSON() ([]byte, error) {
-	return []byte(strconv.Quote(a.String())), nil
+// MarshalText - implements encoding.TextMarshaler, so the type is encoded by name in JSON.
+func (a AttributeType) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
 }
 
-func (a *attributeType) UnmarshalJSON(data []byte) error {
-	origin, err := strconv.Unquote(string(data))
+// UnmarshalText - implements encoding.TextUnmarshaler. Unknown names are decoded as None.
+func (a *AttributeType) UnmarshalText(data []byte) error {
+	attrType, err := ParseAttributeType(string(data))
 	if err != nil {
-		return err
-	}
-	for i := range attributeTypesStr {
-		if strings.EqualFold(attributeTypesStr[i], origin) {
-			*a = attributeType(i)
-			return nil
-		}
+		attrType = None
 	}
-	*a = None
+	*a = attrType
 	return nil
 }
 
@@ -61,7 +76,7 @@ func (a *attributeType) UnmarshalJSON(data []byte) error {
 // Each AttributeValue object can use only one of the available properties.
 type AttributeValue struct {
 	// The attribute data type.
-	AttrType *attributeType `json:"AttrType"`
+	AttrType *AttributeType `json:"AttrType"`
 	// For number values, expressed as double.
 	N float64 `json:"N,omitempty"`
 	// For single string values. Maximum string length is 100 characters.
@@ -74,31 +89,49 @@ type AttributeValue struct {
 	// For a map of up to 10 data type:value pairs. Maximum length for each string value is 100 characters.
 	// Key Length Constraints: Minimum length of 1. Maximum length of 1024.
 	SDM map[string]float64 `json:"SDM,omitempty"`
+
+	// The FlexMatch attribute as received, if it couldn't be decoded.
+	raw json.RawMessage
 }
 
 type AttributeValueN struct {
-	AttrType *attributeType `json:"AttrType"`
+	AttrType *AttributeType `json:"AttrType"`
 	N        float64        `json:"N"`
 }
 
 func (a AttributeValue) MarshalJSON() ([]byte, error) {
 	type localAttributeValue AttributeValue
-	switch *a.AttrType {
-	case Double:
+	if attrType, _ := a.LookupAttrType(); attrType == Double {
 		attributeValueN := AttributeValueN{a.AttrType, a.N}
 		return json.Marshal(attributeValueN)
 	}
 	return json.Marshal(localAttributeValue(a))
 }
 
-// GetAttrType - return current attributeType.
-//
-//nolint:revive // Return an unexposed type is enough to use a predefined values [ String, Double, StringList, StringDoubleMap ]
-func (a AttributeValue) GetAttrType() attributeType {
-	return *a.AttrType
+// GetAttrType - return current AttributeType, None if it is not set.
+func (a AttributeValue) GetAttrType() AttributeType {
+	attrType, _ := a.LookupAttrType()
+	return attrType
+}
+
+// LookupAttrType - return current AttributeType and true, or None and false if it is not set.
+func (a AttributeValue) LookupAttrType() (AttributeType, bool) {
+	if a.AttrType == nil {
+		return None, false
+	}
+	return *a.AttrType, true
+}
+
+// Raw - returns the attribute of the matchmaker data as received, such as
+// {"attributeType":"STRING","valueAttribute":10}, if it couldn't be decoded because its type is unknown or
+// its value doesn't match its type. Such attributes are None attributes. Returns nil for the other attributes.
+func (a AttributeValue) Raw() json.RawMessage {
+	return a.raw
 }
 
-// MakeAttributeValue - create an AttributeValue by argument arg
+// MakeAttributeValue - create an AttributeValue by argument arg.
+// Entries of []interface{} which are not strings and values of map[string]interface{} which are not float64
+// are dropped, and unsupported types make a None attribute. Use NewAttributeValue to detect them.
 //
 //nolint:gosimple,gocritic // No need to check type assertion for arg in switch-case by type
 func MakeAttributeValue(arg any) AttributeValue {
@@ -157,3 +190,224 @@ func MakeAttributeValue(arg any) AttributeValue {
 	attrType := None
 	return AttributeValue{AttrType: &attrType}
 }
+
+var (
+	// ErrAttributeTypeMismatch - the AttributeValue has another type than the one requested.
+	ErrAttributeTypeMismatch = errors.New("attribute type mismatch")
+	// ErrUnsupportedAttributeValue - the value can't be represented by an AttributeValue.
+	ErrUnsupportedAttributeValue = errors.New("unsupported attribute value")
+)
+
+// NewStringAttr - create a String AttributeValue.
+func NewStringAttr(s string) AttributeValue {
+	attrType := String
+	return AttributeValue{AttrType: &attrType, S: s}
+}
+
+// NewDoubleAttr - create a Double AttributeValue.
+func NewDoubleAttr(n float64) AttributeValue {
+	attrType := Double
+	return AttributeValue{AttrType: &attrType, N: n}
+}
+
+// NewStringListAttr - create a StringList AttributeValue with a copy of values.
+func NewStringListAttr(values ...string) AttributeValue {
+	attrType := StringList
+	return AttributeValue{AttrType: &attrType, SL: copyStringList(values)}
+}
+
+// NewStringDoubleMapAttr - create a StringDoubleMap AttributeValue with a copy of values.
+func NewStringDoubleMapAttr(values map[string]float64) AttributeValue {
+	attrType := StringDoubleMap
+	return AttributeValue{AttrType: &attrType, SDM: copyStringDoubleMap(values)}
+}
+
+// NewAttributeValue - create an AttributeValue by argument arg, like MakeAttributeValue,
+// but returns ErrUnsupportedAttributeValue instead of dropping the values it can't represent.
+// Supported types are string, float64, int, json.Number, []string, map[string]float64,
+// and []interface{} of strings or map[string]interface{} of numbers as decoded by encoding/json.
+func NewAttributeValue(arg any) (AttributeValue, error) {
+	switch v := arg.(type) {
+	case string:
+		return NewStringAttr(v), nil
+	case float64, int, json.Number:
+		n, err := toDouble(v)
+		if err != nil {
+			return AttributeValue{}, err
+		}
+		return NewDoubleAttr(n), nil
+	case []string:
+		return NewStringListAttr(v...), nil
+	case map[string]float64:
+		return NewStringDoubleMapAttr(v), nil
+	case []any:
+		values := make([]string, 0, len(v))
+		for i := range v {
+			str, ok := v[i].(string)
+			if !ok {
+				return AttributeValue{}, fmt.Errorf("%w: item %d of the string list is %T", ErrUnsupportedAttributeValue, i, v[i])
+			}
+			values = append(values, str)
+		}
+		return NewStringListAttr(values...), nil
+	case map[string]any:
+		values := make(map[string]float64, len(v))
+		for key := range v {
+			n, err := toDouble(v[key])
+			if err != nil {
+				return AttributeValue{}, fmt.Errorf("value %q of the string double map: %w", key, err)
+			}
+			values[key] = n
+		}
+		attrType := StringDoubleMap
+		return AttributeValue{AttrType: &attrType, SDM: values}, nil
+	}
+	return AttributeValue{}, fmt.Errorf("%w: %T", ErrUnsupportedAttributeValue, arg)
+}
+
+// AsString - returns the value of a String attribute, or ErrAttributeTypeMismatch.
+func (a AttributeValue) AsString() (string, error) {
+	if err := a.checkAttrType(String); err != nil {
+		return "", err
+	}
+	return a.S, nil
+}
+
+// AsDouble - returns the value of a Double attribute, or ErrAttributeTypeMismatch.
+func (a AttributeValue) AsDouble() (float64, error) {
+	if err := a.checkAttrType(Double); err != nil {
+		return 0, err
+	}
+	return a.N, nil
+}
+
+// AsStringList - returns a copy of the value of a StringList attribute, or ErrAttributeTypeMismatch.
+func (a AttributeValue) AsStringList() ([]string, error) {
+	if err := a.checkAttrType(StringList); err != nil {
+		return nil, err
+	}
+	return copyStringList(a.SL), nil
+}
+
+// AsStringDoubleMap - returns a copy of the value of a StringDoubleMap attribute, or ErrAttributeTypeMismatch.
+func (a AttributeValue) AsStringDoubleMap() (map[string]float64, error) {
+	if err := a.checkAttrType(StringDoubleMap); err != nil {
+		return nil, err
+	}
+	return copyStringDoubleMap(a.SDM), nil
+}
+
+func (a AttributeValue) checkAttrType(expected AttributeType) error {
+	if attrType := a.GetAttrType(); attrType != expected {
+		return fmt.Errorf("%w: the attribute is %s, not %s", ErrAttributeTypeMismatch, attrType, expected)
+	}
+	return nil
+}
+
+// Equal - returns true if both attributes have the same type and value.
+// Only the field of the type, or Raw of None attributes, is compared, and nil and empty lists or maps are equal.
+func (a AttributeValue) Equal(b AttributeValue) bool {
+	attrType := a.GetAttrType()
+	if attrType != b.GetAttrType() {
+		return false
+	}
+	switch attrType {
+	case None:
+		return bytes.Equal(a.raw, b.raw)
+	case String:
+		return a.S == b.S
+	case Double:
+		return a.N == b.N
+	case StringList:
+		if len(a.SL) != len(b.SL) {
+			return false
+		}
+		for i := range a.SL {
+			if a.SL[i] != b.SL[i] {
+				return false
+			}
+		}
+	case StringDoubleMap:
+		if len(a.SDM) != len(b.SDM) {
+			return false
+		}
+		for key, n := range a.SDM {
+			if other, ok := b.SDM[key]; !ok || other != n {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// AttributeDiff - the sorted keys of the attributes which differ between two sets of player attributes.
+type AttributeDiff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Empty - returns true if there are no differences.
+func (d AttributeDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffAttributes - returns the attributes added, removed and changed, see AttributeValue.Equal, from before to after.
+func DiffAttributes(before, after map[string]AttributeValue) AttributeDiff {
+	var diff AttributeDiff
+	for key, value := range before {
+		if afterValue, ok := after[key]; !ok {
+			diff.Removed = append(diff.Removed, key)
+		} else if !value.Equal(afterValue) {
+			diff.Changed = append(diff.Changed, key)
+		}
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			diff.Added = append(diff.Added, key)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff
+}
+
+// EqualAttributes - returns true if both sets of player attributes have the same keys and equal values.
+func EqualAttributes(a, b map[string]AttributeValue) bool {
+	return DiffAttributes(a, b).Empty()
+}
+
+func toDouble(arg any) (float64, error) {
+	switch v := arg.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case json.Number:
+		n, err := v.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", ErrUnsupportedAttributeValue, err)
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("%w: %T is not a number", ErrUnsupportedAttributeValue, arg)
+}
+
+func copyStringList(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(values)), values...)
+}
+
+func copyStringDoubleMap(values map[string]float64) map[string]float64 {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]float64, len(values))
+	for key, n := range values {
+		result[key] = n
+	}
+	return result
+}
@@ -8,13 +8,17 @@ package model
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"math"
+	"math/rand"
+	"reflect"
 	"strings"
 	"testing"
+	"testing/quick"
 )
 
 func TestAttributeType_MarshalJSON(t *testing.T) {
-	cases := map[attributeType][]byte{
+	cases := map[AttributeType][]byte{
 		String:          []byte("\"STRING\""),
 		Double:          []byte("\"DOUBLE\""),
 		StringList:      []byte("\"STRING_LIST\""),
@@ -25,7 +29,7 @@ func TestAttributeType_MarshalJSON(t *testing.T) {
 	for key := range cases {
 		res, err := json.Marshal(&key)
 		if err != nil {
-			t.Errorf("json marshal attributeType error: %s", err.Error())
+			t.Errorf("json marshal AttributeType error: %s", err.Error())
 			return
 		}
 		if !bytes.Equal(res, cases[key]) {
@@ -36,7 +40,7 @@ func TestAttributeType_MarshalJSON(t *testing.T) {
 }
 
 func TestAttributeType_UnmarshalJSON(t *testing.T) {
-	cases := map[attributeType][]byte{
+	cases := map[AttributeType][]byte{
 		String:          []byte("\"STRING\""),
 		Double:          []byte("\"DOUBLE\""),
 		StringList:      []byte("\"STRING_LIST\""),
@@ -45,10 +49,10 @@ func TestAttributeType_UnmarshalJSON(t *testing.T) {
 	}
 
 	for key, v := range cases {
-		var val attributeType
+		var val AttributeType
 		err := json.Unmarshal(v, &val)
 		if err != nil {
-			t.Errorf("json unmarshal attributeType error: %s", err.Error())
+			t.Errorf("json unmarshal AttributeType error: %s", err.Error())
 			return
 		}
 		if key != val {
@@ -59,7 +63,7 @@ func TestAttributeType_UnmarshalJSON(t *testing.T) {
 }
 
 func TestMakeAttributeValue(t *testing.T) {
-	cases := map[attributeType]any{
+	cases := map[AttributeType]any{
 		String:          "Testing purpose",
 		Double:          math.Pi,
 		StringList:      []string{"Testing", "purpose"},
@@ -77,15 +81,15 @@ func TestMakeAttributeValue(t *testing.T) {
 }
 
 func TestMarshalAttributeValue(t *testing.T) {
-	var allCases []map[attributeType]any
-	cases1 := map[attributeType]any{
+	var allCases []map[AttributeType]any
+	cases1 := map[AttributeType]any{
 		String:          "",
 		Double:          0.0,
 		StringList:      []string{},
 		StringDoubleMap: map[string]float64{},
 		None:            struct{ Val string }{Val: "Unsupported type"},
 	}
-	cases2 := map[attributeType]any{
+	cases2 := map[AttributeType]any{
 		String:          "Testing purpose",
 		Double:          10.3,
 		StringList:      []string{"Testing", "purpose"},
@@ -98,7 +102,7 @@ func TestMarshalAttributeValue(t *testing.T) {
 	}
 }
 
-func checkmarshaledData(cases map[attributeType]any, t *testing.T) {
+func checkmarshaledData(cases map[AttributeType]any, t *testing.T) {
 	for key, val := range cases {
 		attrVal := MakeAttributeValue(val)
 		if attrVal.GetAttrType() != key {
@@ -144,3 +148,257 @@ func getNumFieldsInMarshaledData(marshaledString string) int {
 	}
 	return count
 }
+
+func TestParseAttributeType(t *testing.T) {
+	for _, attrType := range append(AttributeTypeValues(), None) {
+		parsed, err := ParseAttributeType(attrType.String())
+		if err != nil {
+			t.Fatalf("parse %s: %s", attrType, err)
+		}
+		if parsed != attrType {
+			t.Errorf("expect %s but get %s", attrType, parsed)
+		}
+	}
+	if parsed, err := ParseAttributeType("string_list"); err != nil || parsed != StringList {
+		t.Errorf("expect %s but get %s, %v", StringList, parsed, err)
+	}
+	if _, err := ParseAttributeType("NUMBER"); err == nil {
+		t.Error("expect an error for an unknown attribute type")
+	}
+}
+
+func TestAttributeValue_LookupAttrType(t *testing.T) {
+	var attrVal AttributeValue
+	if attrType, ok := attrVal.LookupAttrType(); ok || attrType != None {
+		t.Errorf("expect %s and false but get %s and %t", None, attrType, ok)
+	}
+	marshaled, err := json.Marshal(attrVal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(marshaled) != `{"AttrType":null}` {
+		t.Errorf("unexpected %s", marshaled)
+	}
+
+	attrVal = MakeAttributeValue(1.5)
+	if attrType, ok := attrVal.LookupAttrType(); !ok || attrType != Double {
+		t.Errorf("expect %s and true but get %s and %t", Double, attrType, ok)
+	}
+}
+
+func TestNewAttributeValue(t *testing.T) {
+	cases := []struct {
+		name     string
+		arg      any
+		expected AttributeValue
+	}{
+		{name: "string", arg: "deathmatch", expected: NewStringAttr("deathmatch")},
+		{name: "float64", arg: 1.5, expected: NewDoubleAttr(1.5)},
+		{name: "int", arg: 3, expected: NewDoubleAttr(3)},
+		{name: "json number", arg: json.Number("2.25"), expected: NewDoubleAttr(2.25)},
+		{name: "string list", arg: []string{"a", "b"}, expected: NewStringListAttr("a", "b")},
+		{name: "decoded string list", arg: []any{"a", "b"}, expected: NewStringListAttr("a", "b")},
+		{name: "string double map", arg: map[string]float64{"a": 1}, expected: NewStringDoubleMapAttr(map[string]float64{"a": 1})},
+		{name: "decoded string double map", arg: map[string]any{"a": 1.0}, expected: NewStringDoubleMapAttr(map[string]float64{"a": 1})},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			attr, err := NewAttributeValue(tc.arg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !attr.Equal(tc.expected) {
+				t.Errorf("expect %+v but get %+v", tc.expected, attr)
+			}
+		})
+	}
+}
+
+func TestNewAttributeValue_Unsupported(t *testing.T) {
+	cases := map[string]any{
+		"nil":                   nil,
+		"bool":                  true,
+		"mixed list":            []any{"a", 1.0},
+		"map of strings":        map[string]any{"a": "b"},
+		"invalid json number":   json.Number("x"),
+		"unsupported structure": struct{ Val string }{Val: "Unsupported type"},
+	}
+	for name, arg := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewAttributeValue(arg); !errors.Is(err, ErrUnsupportedAttributeValue) {
+				t.Errorf("expect ErrUnsupportedAttributeValue but get %v", err)
+			}
+		})
+	}
+}
+
+func TestNewStringListAttr_Copies(t *testing.T) {
+	values := []string{"a", "b"}
+	attr := NewStringListAttr(values...)
+	values[0] = "changed"
+
+	list, err := attr.AsStringList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	list[1] = "changed"
+	if !reflect.DeepEqual(attr.SL, []string{"a", "b"}) {
+		t.Errorf("the attribute was changed through its arguments or getter: %v", attr.SL)
+	}
+}
+
+func TestAttributeValue_TypedGetters(t *testing.T) {
+	attr := NewDoubleAttr(2.5)
+	if n, err := attr.AsDouble(); err != nil || n != 2.5 {
+		t.Errorf("expect 2.5 but get %v, %v", n, err)
+	}
+	if _, err := attr.AsString(); !errors.Is(err, ErrAttributeTypeMismatch) {
+		t.Errorf("expect ErrAttributeTypeMismatch but get %v", err)
+	}
+	if _, err := attr.AsStringList(); !errors.Is(err, ErrAttributeTypeMismatch) {
+		t.Errorf("expect ErrAttributeTypeMismatch but get %v", err)
+	}
+	if _, err := (AttributeValue{}).AsStringDoubleMap(); !errors.Is(err, ErrAttributeTypeMismatch) {
+		t.Errorf("expect ErrAttributeTypeMismatch but get %v", err)
+	}
+}
+
+func TestDiffAttributes(t *testing.T) {
+	before := map[string]AttributeValue{
+		"gameMode": NewStringAttr("deathmatch"),
+		"skill":    NewDoubleAttr(10),
+		"maps":     NewStringListAttr("desert"),
+		"emptyMap": NewStringDoubleMapAttr(nil),
+	}
+	after := map[string]AttributeValue{
+		"gameMode": NewStringAttr("deathmatch"),
+		"skill":    NewStringAttr("10"),
+		"emptyMap": NewStringDoubleMapAttr(map[string]float64{}),
+		"level":    NewDoubleAttr(3),
+	}
+
+	diff := DiffAttributes(before, after)
+
+	expected := AttributeDiff{Added: []string{"level"}, Removed: []string{"maps"}, Changed: []string{"skill"}}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("expect %+v but get %+v", expected, diff)
+	}
+	if EqualAttributes(before, after) || !EqualAttributes(before, before) {
+		t.Error("unexpected result of EqualAttributes")
+	}
+}
+
+func TestUnmarshalFlexMatchAttribute_Mismatch(t *testing.T) {
+	cases := []string{
+		`{"attributeType":"DOUBLE","valueAttribute":"10"}`,
+		`{"attributeType":"STRING_LIST","valueAttribute":["a",1]}`,
+		`{"attributeType":"STRING_DOUBLE_MAP","valueAttribute":["a"]}`,
+	}
+	for _, data := range cases {
+		if _, err := UnmarshalFlexMatchAttribute([]byte(data)); !errors.Is(err, ErrUnsupportedAttributeValue) {
+			t.Errorf("expect ErrUnsupportedAttributeValue for %s but get %v", data, err)
+		}
+	}
+	if _, err := UnmarshalFlexMatchAttribute([]byte(`{"attributeType":"NUMBER","valueAttribute":1}`)); err == nil {
+		t.Error("expect an error for an unknown attribute type")
+	}
+}
+
+// randomAttributeValue - generates the AttributeValue of the property-based tests.
+type randomAttributeValue struct {
+	AttributeValue
+}
+
+func (randomAttributeValue) Generate(r *rand.Rand, size int) reflect.Value {
+	randomString := func() string {
+		value, _ := quick.Value(reflect.TypeOf(""), r)
+		return value.String()
+	}
+	var attr AttributeValue
+	switch AttributeType(r.Intn(len(attributeTypesStr))) {
+	case None:
+		attrType := None
+		attr = AttributeValue{AttrType: &attrType}
+	case String:
+		attr = NewStringAttr(randomString())
+	case Double:
+		attr = NewDoubleAttr(r.NormFloat64() * math.Pow10(r.Intn(20)))
+	case StringList:
+		list := make([]string, r.Intn(size+1))
+		for i := range list {
+			list[i] = randomString()
+		}
+		attr = NewStringListAttr(list...)
+	case StringDoubleMap:
+		sdm := make(map[string]float64)
+		for i := r.Intn(size + 1); i > 0; i-- {
+			sdm[randomString()] = r.Float64()
+		}
+		attr = NewStringDoubleMapAttr(sdm)
+	}
+	return reflect.ValueOf(randomAttributeValue{attr})
+}
+
+// GIVEN any AttributeValue WHEN it is encoded and decoded as JSON THEN the decoded attribute is equal
+func TestAttributeValue_JSONRoundTrip(t *testing.T) {
+	roundTrip := func(attr randomAttributeValue) bool {
+		data, err := json.Marshal(attr.AttributeValue)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		var decoded AttributeValue
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Log(err)
+			return false
+		}
+		return decoded.Equal(attr.AttributeValue)
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// GIVEN any AttributeValue WHEN it is converted to and from the FlexMatch attribute JSON THEN the decoded attribute is equal
+func TestAttributeValue_FlexMatchRoundTrip(t *testing.T) {
+	roundTrip := func(attr randomAttributeValue) bool {
+		data, err := MarshalFlexMatchAttribute(attr.AttributeValue)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		decoded, err := UnmarshalFlexMatchAttribute(data)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		return decoded.Equal(attr.AttributeValue)
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// GIVEN any player attributes WHEN the matchmaker data is encoded and decoded THEN the attributes are equal
+func TestMatchmakerData_AttributesRoundTrip(t *testing.T) {
+	roundTrip := func(attrs map[string]randomAttributeValue) bool {
+		player := Player{PlayerID: "player-1", Team: "red", PlayerAttributes: make(map[string]AttributeValue, len(attrs))}
+		for key := range attrs {
+			player.PlayerAttributes[key] = attrs[key].AttributeValue
+		}
+		data, err := (&MatchmakerData{Players: []Player{player}}).MarshalJSON()
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		var decoded MatchmakerData
+		if err := decoded.UnmarshalJSON(data); err != nil {
+			t.Log(err)
+			return false
+		}
+		return len(decoded.Players) == 1 && EqualAttributes(decoded.Players[0].PlayerAttributes, player.PlayerAttributes)
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -45,17 +45,27 @@ type GameSession struct {
 	GameProperties map[string]string `json:"GameProperties"`
 	// Current status of the game session. A game session must have an ACTIVE status to have player sessions.
 	// Valid Values: ACTIVE | ACTIVATING | TERMINATED | TERMINATING | ERROR
-	Status *gameSessionStatus `json:"Status,omitempty"`
+	Status *GameSessionStatus `json:"Status,omitempty"`
 	// Provides additional information about game session status. INTERRUPTED indicates that the game session
 	// was hosted on a spot instance that was reclaimed, causing the active game session to be terminated.
 	StatusReason string `json:"StatusReason"`
 }
 
-func (g GameSession) WithStatus(status gameSessionStatus) GameSession {
+func (g GameSession) WithStatus(status GameSessionStatus) GameSession {
 	g.Status = &status
 	return g
 }
 
-func (g GameSession) GetStatus() gameSessionStatus {
-	return *g.Status
+// GetStatus - returns the status of the game session, GameNotSet if it is not set.
+func (g GameSession) GetStatus() GameSessionStatus {
+	status, _ := g.LookupStatus()
+	return status
+}
+
+// LookupStatus - returns the status of the game session and true, or GameNotSet and false if it is not set.
+func (g GameSession) LookupStatus() (GameSessionStatus, bool) {
+	if g.Status == nil {
+		return GameNotSet, false
+	}
+	return *g.Status, true
 }
@@ -5,14 +5,14 @@
 
 package model
 
-import "strconv"
+import "fmt"
 
-// gameSessionStatus - unexported (private) data type, has a several predefined values see below
-type gameSessionStatus int
+// GameSessionStatus - the status of a game session, has a several predefined values see below.
+type GameSessionStatus int
 
 // Possible game session statuses
 const (
-	GameNotSet gameSessionStatus = iota
+	GameNotSet GameSessionStatus = iota
 	GameActive
 	GameActivating
 	GameTerminated
@@ -21,33 +21,42 @@ const (
 
 var gameSessionStatusStrs = []string{"NOT_SET", "ACTIVE", "ACTIVATING", "TERMINATED", "TERMINATING"}
 
-func (g *gameSessionStatus) toGameSessionStatus(s string) {
+// ParseGameSessionStatus - returns the status with the name, such as "ACTIVE",
+// or an error if the name is not one of the String values.
+func ParseGameSessionStatus(s string) (GameSessionStatus, error) {
 	for i := range gameSessionStatusStrs {
 		if gameSessionStatusStrs[i] == s {
-			*g = gameSessionStatus(i)
-			return
+			return GameSessionStatus(i), nil
 		}
 	}
-	*g = GameNotSet
+	return GameNotSet, fmt.Errorf("unknown game session status %q", s)
 }
 
-func (g *gameSessionStatus) String() string {
-	n := int(*g)
-	if n >= len(gameSessionStatusStrs) {
+// GameSessionStatusValues - returns the statuses a game session can have, all except GameNotSet.
+func GameSessionStatusValues() []GameSessionStatus {
+	return []GameSessionStatus{GameActive, GameActivating, GameTerminated, GameTerminating}
+}
+
+func (g GameSessionStatus) String() string {
+	n := int(g)
+	if n < 0 || n >= len(gameSessionStatusStrs) {
 		n = 0
 	}
 	return gameSessionStatusStrs[n]
 }
 
-func (g *gameSessionStatus) MarshalJSON() ([]byte, error) {
-	return []byte(strconv.Quote(g.String())), nil
+// MarshalText - implements encoding.TextMarshaler, so the status is encoded by name in JSON.
+func (g GameSessionStatus) MarshalText() ([]byte, error) {
+	return []byte(g.String()), nil
 }
 
-func (g *gameSessionStatus) UnmarshalJSON(data []byte) error {
-	origin, err := strconv.Unquote(string(data))
+// UnmarshalText - implements encoding.TextUnmarshaler. Unknown names are decoded as GameNotSet,
+// so new statuses of GameLift don't break the decoding of the messages.
+func (g *GameSessionStatus) UnmarshalText(data []byte) error {
+	status, err := ParseGameSessionStatus(string(data))
 	if err != nil {
-		return err
+		status = GameNotSet
 	}
-	g.toGameSessionStatus(origin)
+	*g = status
 	return nil
 }
@@ -12,7 +12,7 @@ import (
 )
 
 func TestGameSessionStatus_MarshalJSON(t *testing.T) {
-	cases := map[gameSessionStatus]string{
+	cases := map[GameSessionStatus]string{
 		GameActive:      "\"ACTIVE\"",
 		GameActivating:  "\"ACTIVATING\"",
 		GameTerminated:  "\"TERMINATED\"",
@@ -23,7 +23,7 @@ func TestGameSessionStatus_MarshalJSON(t *testing.T) {
 	for key := range cases {
 		val, err := json.Marshal(&key)
 		if err != nil {
-			t.Errorf("json marshal gameSessionStatus error: %s", err.Error())
+			t.Errorf("json marshal GameSessionStatus error: %s", err.Error())
 			return
 		}
 		if !strings.EqualFold(cases[key], string(val)) {
@@ -34,7 +34,7 @@ func TestGameSessionStatus_MarshalJSON(t *testing.T) {
 }
 
 func TestGameSessionStatus_UnmarshalJSON(t *testing.T) {
-	cases := map[gameSessionStatus]string{
+	cases := map[GameSessionStatus]string{
 		GameActive:      "\"ACTIVE\"",
 		GameActivating:  "\"ACTIVATING\"",
 		GameTerminated:  "\"TERMINATED\"",
@@ -43,12 +43,55 @@ func TestGameSessionStatus_UnmarshalJSON(t *testing.T) {
 	}
 
 	for key, v := range cases {
-		var val gameSessionStatus
+		var val GameSessionStatus
 		if err := json.Unmarshal([]byte(v), &val); err != nil {
-			t.Errorf("json unmarshal gameSessionStatus error: %s", err.Error())
+			t.Errorf("json unmarshal GameSessionStatus error: %s", err.Error())
 		}
 		if val != key {
 			t.Errorf("failed ")
 		}
 	}
 }
+
+func TestParseGameSessionStatus(t *testing.T) {
+	for _, status := range append(GameSessionStatusValues(), GameNotSet) {
+		parsed, err := ParseGameSessionStatus(status.String())
+		if err != nil {
+			t.Fatalf("parse %s: %s", status, err)
+		}
+		if parsed != status {
+			t.Errorf("expect %s but get %s", status, parsed)
+		}
+	}
+	if _, err := ParseGameSessionStatus("active"); err == nil {
+		t.Error("expect an error for a lower case status")
+	}
+	if _, err := ParseGameSessionStatus("ERROR"); err == nil {
+		t.Error("expect an error for an unknown status")
+	}
+}
+
+func TestGameSessionStatus_UnmarshalText_Unknown(t *testing.T) {
+	status := GameActive
+	if err := json.Unmarshal([]byte(`"UNKNOWN"`), &status); err != nil {
+		t.Fatal(err)
+	}
+	if status != GameNotSet {
+		t.Errorf("expect %s but get %s", GameNotSet, status)
+	}
+}
+
+func TestGameSession_LookupStatus(t *testing.T) {
+	var gameSession GameSession
+	if status, ok := gameSession.LookupStatus(); ok || status != GameNotSet {
+		t.Errorf("expect %s and false but get %s and %t", GameNotSet, status, ok)
+	}
+	if status := gameSession.GetStatus(); status != GameNotSet {
+		t.Errorf("expect %s but get %s", GameNotSet, status)
+	}
+
+	gameSession = gameSession.WithStatus(GameActive)
+	if status, ok := gameSession.LookupStatus(); !ok || status != GameActive {
+		t.Errorf("expect %s and true but get %s and %t", GameActive, status, ok)
+	}
+}
@@ -12,7 +12,7 @@ import (
 
 func TestGameSession_WithStatus(t *testing.T) {
 	var gameSession GameSession
-	statuses := []gameSessionStatus{
+	statuses := []GameSessionStatus{
 		GameActive,
 		GameNotSet,
 		GameActivating,
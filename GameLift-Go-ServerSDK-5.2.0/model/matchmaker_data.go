@@ -6,9 +6,18 @@
 package model
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 )
 
+// MatchmakerData - the matchmaker data of a game session created by FlexMatch, see GameSession.MatchmakerData.
+// The fields which are not known by the SDK are kept, so MarshalJSON returns the same data as decoded by UnmarshalJSON.
+// Attributes of an unknown type, or whose value doesn't match their type, are decoded as None attributes
+// which keep the attribute as received, see AttributeValue.Raw.
 type MatchmakerData struct {
 	// The unique identifiers for this group of profiles that match.
 	// Length Constraints: Minimum length of 1. Maximum length of 255.
@@ -21,11 +30,22 @@ type MatchmakerData struct {
 	// Amazon GameLift will generate one in the form of a UUID.
 	// Length Constraints: Maximum length of 128.
 	AutoBackfillTicketID string
+	// The method used to backfill game sessions created with this matchmaking configuration,
+	// AUTOMATIC or MANUAL.
+	AutoBackfillMode string
+
+	// The names of the decoded teams in order, including the teams without players, see Teams.
+	teamNames []string
+	// The unknown fields of the matchmaker data and of its teams by team name.
+	extra     map[string]json.RawMessage
+	teamExtra map[string]map[string]json.RawMessage
 }
 
 type attributeMatchMakerData struct {
 	AttributeType  string      `json:"attributeType"`
 	ValueAttribute interface{} `json:"valueAttribute"`
+	// The attribute as received, encoded instead of the fields if set.
+	raw json.RawMessage
 }
 
 type playerMatchmakerData struct {
@@ -39,6 +59,11 @@ type playerMatchmakerData struct {
 	// Type: String to AttributeValue object map
 	// Key Length Constraints: Minimum length of 1. Maximum length of 1024.
 	AttributeValue map[string]attributeMatchMakerData `json:"attributes"`
+	// A set of values, expressed in milliseconds, that indicates the amount of latency
+	// that a player experiences when connected to @aws; Regions.
+	LatencyInMS map[string]int `json:"latencyInMs,omitempty"`
+	// The fields which are not known by the SDK.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type teamMatchmakerData struct {
@@ -47,6 +72,8 @@ type teamMatchmakerData struct {
 	Name string `json:"name"`
 	// A set of data representing players matchmaker data.
 	Players []playerMatchmakerData `json:"players"`
+	// The fields which are not known by the SDK.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type matchmakerDataOriginal struct {
@@ -61,6 +88,168 @@ type matchmakerDataOriginal struct {
 	// Amazon GameLift will generate one in the form of a UUID.
 	// Length Constraints: Maximum length of 128.
 	AutoBackfillTicketID string `json:"autoBackfillTicketId"`
+	// The method used to backfill game sessions created with this matchmaking configuration.
+	AutoBackfillMode string `json:"autoBackfillMode,omitempty"`
+	// The fields which are not known by the SDK.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+var (
+	matchmakerDataFields = jsonFieldNames(reflect.TypeOf(matchmakerDataOriginal{}))
+	teamFields           = jsonFieldNames(reflect.TypeOf(teamMatchmakerData{}))
+	playerFields         = jsonFieldNames(reflect.TypeOf(playerMatchmakerData{}))
+)
+
+func (mo matchmakerDataOriginal) MarshalJSON() ([]byte, error) {
+	type localMatchmakerData matchmakerDataOriginal
+	data, err := json.Marshal(localMatchmakerData(mo))
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownFields(data, mo.Extra)
+}
+
+func (mo *matchmakerDataOriginal) UnmarshalJSON(data []byte) error {
+	type localMatchmakerData matchmakerDataOriginal
+	var local localMatchmakerData
+	if err := json.Unmarshal(data, &local); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, matchmakerDataFields)
+	if err != nil {
+		return err
+	}
+	local.Extra = extra
+	*mo = matchmakerDataOriginal(local)
+	return nil
+}
+
+func (a attributeMatchMakerData) MarshalJSON() ([]byte, error) {
+	if a.raw != nil {
+		return a.raw, nil
+	}
+	type localAttribute attributeMatchMakerData
+	return json.Marshal(localAttribute(a))
+}
+
+func (a *attributeMatchMakerData) UnmarshalJSON(data []byte) error {
+	type localAttribute attributeMatchMakerData
+	var local localAttribute
+	if err := json.Unmarshal(data, &local); err != nil {
+		return err
+	}
+	local.raw = append(json.RawMessage(nil), data...)
+	*a = attributeMatchMakerData(local)
+	return nil
+}
+
+func (t teamMatchmakerData) MarshalJSON() ([]byte, error) {
+	type localTeam teamMatchmakerData
+	data, err := json.Marshal(localTeam(t))
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownFields(data, t.Extra)
+}
+
+func (t *teamMatchmakerData) UnmarshalJSON(data []byte) error {
+	type localTeam teamMatchmakerData
+	var local localTeam
+	if err := json.Unmarshal(data, &local); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, teamFields)
+	if err != nil {
+		return err
+	}
+	local.Extra = extra
+	*t = teamMatchmakerData(local)
+	return nil
+}
+
+func (p playerMatchmakerData) MarshalJSON() ([]byte, error) {
+	type localPlayer playerMatchmakerData
+	data, err := json.Marshal(localPlayer(p))
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownFields(data, p.Extra)
+}
+
+func (p *playerMatchmakerData) UnmarshalJSON(data []byte) error {
+	type localPlayer playerMatchmakerData
+	var local localPlayer
+	if err := json.Unmarshal(data, &local); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, playerFields)
+	if err != nil {
+		return err
+	}
+	local.Extra = extra
+	*p = playerMatchmakerData(local)
+	return nil
+}
+
+// jsonFieldNames - returns the lower case JSON names of the fields of the struct type.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "-" && name != "" {
+			names[strings.ToLower(name)] = true
+		}
+	}
+	return names
+}
+
+// unknownFields - returns the members of the JSON object whose names are not in known, or nil if there are none.
+// Names are compared ignoring the case, like encoding/json matches the fields.
+func unknownFields(data []byte, known map[string]bool) (map[string]json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	var extra map[string]json.RawMessage
+	for name, value := range members {
+		if known[strings.ToLower(name)] {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[name] = value
+	}
+	return extra, nil
+}
+
+// withUnknownFields - appends the fields, sorted by name, to the encoded JSON object.
+func withUnknownFields(data []byte, fields map[string]json.RawMessage) ([]byte, error) {
+	if len(fields) == 0 {
+		return data, nil
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimSuffix(bytes.TrimSpace(data), []byte("}")))
+	for i, name := range names {
+		if i > 0 || !bytes.HasSuffix(bytes.TrimSpace(buf.Bytes()), []byte("{")) {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(fields[name])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
 }
 
 func (m *MatchmakerData) MarshalJSON() ([]byte, error) {
@@ -85,48 +274,89 @@ func (m *MatchmakerData) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalFlexMatchAttribute - encodes the attribute as in the matchmaker data of a game session,
+// such as {"attributeType":"STRING","valueAttribute":"deathmatch"}.
+func MarshalFlexMatchAttribute(a AttributeValue) ([]byte, error) {
+	return json.Marshal(fromAttributesValue(a))
+}
+
+// UnmarshalFlexMatchAttribute - decodes an attribute of the matchmaker data of a game session,
+// see MarshalFlexMatchAttribute. Unlike MatchmakerData.UnmarshalJSON, returns an error if the attribute type is unknown
+// or the value doesn't match it.
+func UnmarshalFlexMatchAttribute(data []byte) (AttributeValue, error) {
+	var attr attributeMatchMakerData
+	if err := json.Unmarshal(data, &attr); err != nil {
+		return AttributeValue{}, err
+	}
+	return toAttributeValue(attr)
+}
+
 func fromAttributesValue(a AttributeValue) attributeMatchMakerData {
-	switch *a.AttrType {
+	attrType := a.GetAttrType()
+	if attrType == None && a.raw != nil {
+		return attributeMatchMakerData{raw: a.raw}
+	}
+	attr := attributeMatchMakerData{AttributeType: attrType.String()}
+	switch attrType {
 	case String:
-		return attributeMatchMakerData{
-			AttributeType:  "STRING",
-			ValueAttribute: a.S,
-		}
+		attr.ValueAttribute = a.S
 	case Double:
-		return attributeMatchMakerData{
-			AttributeType:  "DOUBLE",
-			ValueAttribute: a.N,
-		}
+		attr.ValueAttribute = a.N
 	case StringList:
-		return attributeMatchMakerData{
-			AttributeType:  "STRING_LIST",
-			ValueAttribute: a.SL,
-		}
+		// FlexMatch expects a list, even if it is empty
+		attr.ValueAttribute = append([]string{}, a.SL...)
 	case StringDoubleMap:
-		return attributeMatchMakerData{
-			AttributeType:  "STRING_DOUBLE_MAP",
-			ValueAttribute: a.SDM,
+		sdm := copyStringDoubleMap(a.SDM)
+		if sdm == nil {
+			sdm = map[string]float64{}
 		}
+		attr.ValueAttribute = sdm
+	}
+	return attr
+}
+
+// toAttributeValue - decodes the value by the attribute type, a null value is the empty value of the type.
+func toAttributeValue(a attributeMatchMakerData) (AttributeValue, error) {
+	attrType, err := ParseAttributeType(a.AttributeType)
+	if err != nil {
+		return AttributeValue{}, err
+	}
+	if attrType == None || a.ValueAttribute == nil {
+		return AttributeValue{AttrType: &attrType}, nil
+	}
+	attr, err := NewAttributeValue(a.ValueAttribute)
+	if err != nil {
+		return AttributeValue{}, err
 	}
-	return attributeMatchMakerData{
-		AttributeType: "NONE",
+	if attr.GetAttrType() != attrType {
+		return AttributeValue{}, fmt.Errorf("%w: the value of a %s attribute is %T",
+			ErrUnsupportedAttributeValue, attrType, a.ValueAttribute)
+	}
+	return attr, nil
+}
+
+// toLenientAttributeValue - decodes the attribute like toAttributeValue, but returns a None attribute
+// keeping the raw attribute instead of an error, so one bad attribute doesn't fail the whole matchmaker data.
+func toLenientAttributeValue(a attributeMatchMakerData) AttributeValue {
+	attr, err := toAttributeValue(a)
+	if err != nil {
+		attrType := None
+		return AttributeValue{AttrType: &attrType, raw: a.raw}
 	}
+	return attr
 }
 
 func (mo *matchmakerDataOriginal) fromMatchmakerData(m *MatchmakerData) {
 	mo.MatchID = m.MatchID
 	mo.MatchmakingConfigurationArn = m.MatchmakingConfigurationArn
 	mo.AutoBackfillTicketID = m.AutoBackfillTicketID
-	teams := make(map[string][]Player)
-	for i := range m.Players {
-		if _, ok := teams[m.Players[i].Team]; !ok {
-			teams[m.Players[i].Team] = make([]Player, 0, 1)
-		}
-		teams[m.Players[i].Team] = append(teams[m.Players[i].Team], m.Players[i])
-	}
-	for name, players := range teams {
-		var pMatchmakerData []playerMatchmakerData
-		for _, singlePlayer := range players {
+	mo.AutoBackfillMode = m.AutoBackfillMode
+	mo.Extra = m.extra
+	// Teams starts with the decoded teams, so the teams without players and their unknown fields are kept in order
+	for _, team := range m.Teams() {
+		// FlexMatch expects a list, even if the team has no players
+		pMatchmakerData := make([]playerMatchmakerData, 0, len(team.Players))
+		for _, singlePlayer := range team.Players {
 			var attributes = make(map[string]attributeMatchMakerData)
 			for k, v := range singlePlayer.PlayerAttributes {
 				attributes[k] = fromAttributesValue(v)
@@ -134,11 +364,14 @@ func (mo *matchmakerDataOriginal) fromMatchmakerData(m *MatchmakerData) {
 			pMatchmakerData = append(pMatchmakerData, playerMatchmakerData{
 				PlayerID:       singlePlayer.PlayerID,
 				AttributeValue: attributes,
+				LatencyInMS:    singlePlayer.LatencyInMS,
+				Extra:          singlePlayer.extra,
 			})
 		}
 		mo.Teams = append(mo.Teams, teamMatchmakerData{
-			Name:    name,
+			Name:    team.Name,
 			Players: pMatchmakerData,
+			Extra:   m.teamExtra[team.Name],
 		})
 	}
 }
@@ -148,17 +381,28 @@ func (mo *matchmakerDataOriginal) toMatchmakerData() MatchmakerData {
 		MatchID:                     mo.MatchID,
 		MatchmakingConfigurationArn: mo.MatchmakingConfigurationArn,
 		AutoBackfillTicketID:        mo.AutoBackfillTicketID,
+		AutoBackfillMode:            mo.AutoBackfillMode,
+		extra:                       mo.Extra,
 	}
 	for _, team := range mo.Teams {
+		data.teamNames = append(data.teamNames, team.Name)
+		if team.Extra != nil {
+			if data.teamExtra == nil {
+				data.teamExtra = make(map[string]map[string]json.RawMessage)
+			}
+			data.teamExtra[team.Name] = team.Extra
+		}
 		for _, player := range team.Players {
 			var playerAttributes = make(map[string]AttributeValue)
 			for k, v := range player.AttributeValue {
-				playerAttributes[k] = MakeAttributeValue(v.ValueAttribute)
+				playerAttributes[k] = toLenientAttributeValue(v)
 			}
 			data.Players = append(data.Players, Player{
 				Team:             team.Name,
 				PlayerID:         player.PlayerID,
 				PlayerAttributes: playerAttributes,
+				LatencyInMS:      player.LatencyInMS,
+				extra:            player.Extra,
 			})
 		}
 	}
@@ -12,7 +12,7 @@ import (
 	"testing"
 )
 
-var attrValue = []attributeType{None, String, Double, StringList, StringDoubleMap}
+var attrValue = []AttributeType{None, String, Double, StringList, StringDoubleMap}
 
 var matchMakerData = MatchmakerData{
 	MatchID:                     "0123456789",
@@ -95,6 +95,7 @@ var matchMakerData = MatchmakerData{
 			},
 		},
 	},
+	teamNames: []string{"attacker", "defender"},
 }
 
 var mDataOriginal = matchmakerDataOriginal{
@@ -286,3 +287,142 @@ func TestMatchmakerData_UnmarshalJSON(t *testing.T) {
 		t.Fatalf("\nexpect  %v \nbut get %v", matchMakerData, unmarshalJsonOutput)
 	}
 }
+
+const flexMatchMatchmakerData = `{
+	"matchId": "1234",
+	"matchmakingConfigurationArn": "arn:aws:gamelift:us-west-2:111122223333:matchmakingconfiguration/MyConfig",
+	"teams": [
+		{
+			"name": "red",
+			"players": [
+				{
+					"playerId": "player-1",
+					"attributes": {"skill": {"attributeType": "DOUBLE", "valueAttribute": 23}},
+					"latencyInMs": {"us-west-2": 20, "us-east-1": 75},
+					"partyId": "party-1"
+				}
+			],
+			"teamRating": {"mean": 1200.5}
+		},
+		{
+			"name": "blue",
+			"players": [{"playerId": "player-2", "attributes": {}}]
+		}
+	],
+	"autoBackfillMode": "AUTOMATIC",
+	"autoBackfillTicketId": "ticket-1",
+	"matchRegion": "us-west-2"
+}`
+
+// assertLosslessRoundTrip - decodes and encodes the matchmaker data, checks that the encoded data is the same
+// as the original and returns the decoded data.
+func assertLosslessRoundTrip(t *testing.T, data string) MatchmakerData {
+	t.Helper()
+	var matchmakerData MatchmakerData
+	if err := matchmakerData.UnmarshalJSON([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	marshaledData, err := matchmakerData.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected, got any
+	if err := json.Unmarshal([]byte(data), &expected); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(marshaledData, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("\nexpect  %s \nbut get %s", data, marshaledData)
+	}
+	return matchmakerData
+}
+
+// GIVEN matchmaker data with latencies and fields unknown by the SDK WHEN it is decoded and encoded again
+// THEN the encoded data is the same as the original
+func TestMatchmakerData_LosslessRoundTrip(t *testing.T) {
+	matchmakerData := assertLosslessRoundTrip(t, flexMatchMatchmakerData)
+
+	player, ok := matchmakerData.FindPlayer("player-1")
+	if !ok {
+		t.Fatal("player-1 not found")
+	}
+	if expected := map[string]int{"us-west-2": 20, "us-east-1": 75}; !reflect.DeepEqual(player.LatencyInMS, expected) {
+		t.Errorf("expect latencies %v but get %v", expected, player.LatencyInMS)
+	}
+	if matchmakerData.AutoBackfillMode != "AUTOMATIC" {
+		t.Errorf("unexpected AutoBackfillMode %q", matchmakerData.AutoBackfillMode)
+	}
+}
+
+// GIVEN matchmaker data with a team without players and with fields unknown by the SDK
+// WHEN it is decoded and encoded again THEN the team and its fields are kept in order
+func TestMatchmakerData_LosslessRoundTrip_EmptyTeam(t *testing.T) {
+	const data = `{"matchId":"1234","matchmakingConfigurationArn":"arn","teams":[` +
+		`{"name":"red","players":[{"playerId":"player-1","attributes":{}}]},` +
+		`{"name":"blue","players":[],"teamRating":{"mean":0}},` +
+		`{"name":"green","players":[{"playerId":"player-2","attributes":{}}]}],"autoBackfillTicketId":""}`
+
+	assertLosslessRoundTrip(t, data)
+}
+
+// GIVEN matchmaker data with an attribute of an unknown type and one whose value doesn't match its type
+// WHEN it is decoded THEN they are None attributes keeping the raw attribute and are encoded back as received
+func TestMatchmakerData_UnmarshalJSON_KeepsUndecodableAttributes(t *testing.T) {
+	const data = `{"matchId":"1234","matchmakingConfigurationArn":"arn","teams":[{"name":"red","players":[{"playerId":"player-1",` +
+		`"attributes":{"skill":{"attributeType":"DOUBLE","valueAttribute":23},` +
+		`"rank":{"attributeType":"NUMBER","valueAttribute":1},` +
+		`"mode":{"attributeType":"DOUBLE","valueAttribute":"ranked"}}}]}],"autoBackfillTicketId":""}`
+
+	var matchmakerData MatchmakerData
+	if err := matchmakerData.UnmarshalJSON([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	attributes := matchmakerData.Players[0].PlayerAttributes
+	if skill, err := attributes["skill"].AsDouble(); err != nil || skill != 23 || attributes["skill"].Raw() != nil {
+		t.Errorf("unexpected skill %+v", attributes["skill"])
+	}
+	for key, expected := range map[string]string{
+		"rank": `{"attributeType":"NUMBER","valueAttribute":1}`,
+		"mode": `{"attributeType":"DOUBLE","valueAttribute":"ranked"}`,
+	} {
+		if attributes[key].GetAttrType() != None || string(attributes[key].Raw()) != expected {
+			t.Errorf("expect None attribute %s but get %+v", expected, attributes[key])
+		}
+	}
+	marshaledData, err := matchmakerData.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected, got any
+	if err := json.Unmarshal([]byte(data), &expected); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(marshaledData, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("\nexpect  %s \nbut get %s", data, marshaledData)
+	}
+}
+
+func TestWithUnknownFields(t *testing.T) {
+	fields := map[string]json.RawMessage{"b": json.RawMessage(`2`), "a": json.RawMessage(`"1"`)}
+	cases := map[string]string{
+		`{}`:      `{"a":"1","b":2}`,
+		`{"x":0}`: `{"x":0,"a":"1","b":2}`,
+	}
+	for data, expected := range cases {
+		got, err := withUnknownFields([]byte(data), fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != expected {
+			t.Errorf("expect %s but get %s", expected, got)
+		}
+	}
+}
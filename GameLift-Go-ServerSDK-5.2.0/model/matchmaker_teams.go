@@ -0,0 +1,106 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+// Team - the players of MatchmakerData assigned to a team of the match.
+type Team struct {
+	// Name of the team, as defined in the matchmaking rule set.
+	Name string
+	// The players of the team, in the order of the matchmaker data.
+	Players []Player
+}
+
+// Teams - returns the players grouped by team, in the order the teams appear in the matchmaker data.
+// The decoded teams without players are kept, the teams of the players added to Players follow them.
+func (m *MatchmakerData) Teams() []Team {
+	var teams []Team
+	index := make(map[string]int)
+	for _, name := range m.teamNames {
+		if _, ok := index[name]; !ok {
+			index[name] = len(teams)
+			teams = append(teams, Team{Name: name})
+		}
+	}
+	for i := range m.Players {
+		name := m.Players[i].Team
+		n, ok := index[name]
+		if !ok {
+			n = len(teams)
+			index[name] = n
+			teams = append(teams, Team{Name: name})
+		}
+		teams[n].Players = append(teams[n].Players, m.Players[i])
+	}
+	return teams
+}
+
+// FindPlayer - returns the player with the ID and true, or false if the player is not in the match.
+func (m *MatchmakerData) FindPlayer(playerID string) (Player, bool) {
+	for i := range m.Players {
+		if m.Players[i].PlayerID == playerID {
+			return m.Players[i], true
+		}
+	}
+	return Player{}, false
+}
+
+// TeamCounts - returns the number of players of each team.
+func (m *MatchmakerData) TeamCounts() map[string]int {
+	counts := make(map[string]int)
+	for i := range m.Players {
+		counts[m.Players[i].Team]++
+	}
+	return counts
+}
+
+// OpenSlots - returns the number of players missing in each team of teamSizes, the team name to its size
+// in the matchmaking rule set, after removing the departed players. Teams without players have all their slots open.
+//
+//	slots := matchmakerData.OpenSlots(map[string]int{"red": 4, "blue": 4}, departedPlayerID)
+func (m *MatchmakerData) OpenSlots(teamSizes map[string]int, departedPlayerIDs ...string) map[string]int {
+	departed := toSet(departedPlayerIDs)
+	slots := make(map[string]int, len(teamSizes))
+	for name, size := range teamSizes {
+		slots[name] = size
+	}
+	for i := range m.Players {
+		if _, ok := slots[m.Players[i].Team]; ok && !departed[m.Players[i].PlayerID] {
+			slots[m.Players[i].Team]--
+		}
+	}
+	for name, n := range slots {
+		if n < 0 {
+			slots[name] = 0
+		}
+	}
+	return slots
+}
+
+// BackfillPlayers - returns the players of the match except the departed ones, in order,
+// as the Players of a request.StartMatchBackfillRequest.
+//
+//	req, err := request.NewStartMatchBackfillBuilder(matchmakingConfigurationArn).
+//		WithGameSessionArn(gameSessionID).
+//		WithPlayers(matchmakerData.BackfillPlayers(departedPlayerID)...).
+//		Build()
+func (m *MatchmakerData) BackfillPlayers(departedPlayerIDs ...string) []Player {
+	departed := toSet(departedPlayerIDs)
+	players := make([]Player, 0, len(m.Players))
+	for i := range m.Players {
+		if !departed[m.Players[i].PlayerID] {
+			players = append(players, m.Players[i])
+		}
+	}
+	return players
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+var teamsMatchmakerData = MatchmakerData{
+	Players: []Player{
+		{PlayerID: "player-1", Team: "red"},
+		{PlayerID: "player-2", Team: "blue"},
+		{PlayerID: "player-3", Team: "red"},
+		{PlayerID: "player-4", Team: "blue"},
+		{PlayerID: "player-5", Team: "red"},
+	},
+}
+
+func playerIDs(players []Player) []string {
+	ids := make([]string, 0, len(players))
+	for i := range players {
+		ids = append(ids, players[i].PlayerID)
+	}
+	return ids
+}
+
+func TestMatchmakerData_Teams(t *testing.T) {
+	teams := teamsMatchmakerData.Teams()
+
+	if len(teams) != 2 || teams[0].Name != "red" || teams[1].Name != "blue" {
+		t.Fatalf("unexpected teams %+v", teams)
+	}
+	if ids := playerIDs(teams[0].Players); !reflect.DeepEqual(ids, []string{"player-1", "player-3", "player-5"}) {
+		t.Errorf("unexpected players of red %v", ids)
+	}
+	if ids := playerIDs(teams[1].Players); !reflect.DeepEqual(ids, []string{"player-2", "player-4"}) {
+		t.Errorf("unexpected players of blue %v", ids)
+	}
+}
+
+// GIVEN matchmaker data with a team without players WHEN it is decoded THEN the team is kept in order
+func TestMatchmakerData_Teams_EmptyTeam(t *testing.T) {
+	var data MatchmakerData
+	err := data.UnmarshalJSON([]byte(`{"matchId":"match-1","teams":[` +
+		`{"name":"red","players":[{"playerId":"player-1","attributes":{}}]},` +
+		`{"name":"blue","players":[]},` +
+		`{"name":"green","players":[{"playerId":"player-2","attributes":{}}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	teams := data.Teams()
+
+	names := make([]string, 0, len(teams))
+	for i := range teams {
+		names = append(names, teams[i].Name)
+	}
+	if !reflect.DeepEqual(names, []string{"red", "blue", "green"}) {
+		t.Fatalf("unexpected teams %v", names)
+	}
+	if len(teams[1].Players) != 0 {
+		t.Errorf("unexpected players of blue %+v", teams[1].Players)
+	}
+}
+
+func TestMatchmakerData_FindPlayer(t *testing.T) {
+	if player, ok := teamsMatchmakerData.FindPlayer("player-4"); !ok || player.Team != "blue" {
+		t.Errorf("expect player-4 of blue but get %+v, %t", player, ok)
+	}
+	if _, ok := teamsMatchmakerData.FindPlayer("unknown"); ok {
+		t.Error("expect an unknown player not to be found")
+	}
+}
+
+func TestMatchmakerData_TeamCounts(t *testing.T) {
+	expected := map[string]int{"red": 3, "blue": 2}
+	if counts := teamsMatchmakerData.TeamCounts(); !reflect.DeepEqual(counts, expected) {
+		t.Errorf("expect %v but get %v", expected, counts)
+	}
+}
+
+func TestMatchmakerData_OpenSlots(t *testing.T) {
+	cases := []struct {
+		name      string
+		teamSizes map[string]int
+		departed  []string
+		expected  map[string]int
+	}{
+		{
+			name:      "full teams",
+			teamSizes: map[string]int{"red": 3, "blue": 2},
+			expected:  map[string]int{"red": 0, "blue": 0},
+		},
+		{
+			name:      "departed players",
+			teamSizes: map[string]int{"red": 3, "blue": 2},
+			departed:  []string{"player-1", "player-2", "player-4"},
+			expected:  map[string]int{"red": 1, "blue": 2},
+		},
+		{
+			name:      "team without players",
+			teamSizes: map[string]int{"red": 4, "green": 2},
+			expected:  map[string]int{"red": 1, "green": 2},
+		},
+		{
+			name:      "team over its size",
+			teamSizes: map[string]int{"red": 2},
+			expected:  map[string]int{"red": 0},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if slots := teamsMatchmakerData.OpenSlots(tc.teamSizes, tc.departed...); !reflect.DeepEqual(slots, tc.expected) {
+				t.Errorf("expect %v but get %v", tc.expected, slots)
+			}
+		})
+	}
+}
+
+func TestMatchmakerData_BackfillPlayers(t *testing.T) {
+	players := teamsMatchmakerData.BackfillPlayers("player-3", "unknown")
+
+	expected := []string{"player-1", "player-2", "player-4", "player-5"}
+	if ids := playerIDs(players); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expect %v but get %v", expected, ids)
+	}
+	if len(teamsMatchmakerData.Players) != 5 {
+		t.Error("the players of the matchmaker data were changed")
+	}
+}
+
+// GIVEN matchmaker data WHEN it is encoded THEN the teams keep the order of the players
+func TestMatchmakerData_MarshalJSON_TeamOrder(t *testing.T) {
+	var origin matchmakerDataOriginal
+	origin.fromMatchmakerData(&teamsMatchmakerData)
+
+	if len(origin.Teams) != 2 || origin.Teams[0].Name != "red" || origin.Teams[1].Name != "blue" {
+		t.Fatalf("unexpected teams %+v", origin.Teams)
+	}
+	decoded := origin.toMatchmakerData()
+	expected := []string{"player-1", "player-3", "player-5", "player-2", "player-4"}
+	if ids := playerIDs(decoded.Players); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expect %v but get %v", expected, ids)
+	}
+}
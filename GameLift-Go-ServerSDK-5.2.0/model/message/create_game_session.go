@@ -5,7 +5,10 @@
 
 package message
 
-import "aws/amazon-gamelift-go-sdk/model"
+import (
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model"
+)
 
 // CreateGameSessionMessage - Message from GameLift initializing GameSession Creation
 type CreateGameSessionMessage struct {
@@ -37,3 +40,16 @@ func NewGameSession(gameSession *CreateGameSessionMessage) *model.GameSession {
 		GameProperties:            gameSession.GameProperties,
 	}
 }
+
+// Validate - checks the fields of the message, see Validator.
+func (m CreateGameSessionMessage) Validate() error {
+	v := NewValidation(m.Message)
+	v.Length("GameSessionId", m.GameSessionID, 1, common.GameSessionIDMaxLength)
+	v.MaxLength("GameSessionName", m.GameSessionName, 1024)
+	v.MaxLength("GameSessionData", m.GameSessionData, 262144)
+	v.MaxLength("MatchmakerData", m.MatchmakerData, 390000)
+	v.MaxLength("IpAddress", m.IPAddress, 128)
+	v.Range("Port", m.Port, PortMin, PortMax)
+	v.Min("MaximumPlayerSessionCount", int64(m.MaximumPlayerSessionCount), 0)
+	return v.Err()
+}
@@ -32,6 +32,31 @@ const (
 	RemovePlayerSession               MessageAction = "RemovePlayerSession"
 )
 
+// knownActions - the actions sent or received by this version of the SDK.
+var knownActions = map[MessageAction]bool{
+	AcceptPlayerSession:               true,
+	ActivateGameSession:               true,
+	TerminateServerProcess:            true,
+	ActivateServerProcess:             true,
+	UpdatePlayerSessionCreationPolicy: true,
+	CreateGameSession:                 true,
+	UpdateGameSession:                 true,
+	StartMatchBackfill:                true,
+	TerminateProcess:                  true,
+	DescribePlayerSessions:            true,
+	StopMatchBackfill:                 true,
+	HeartbeatServerProcess:            true,
+	GetComputeCertificate:             true,
+	GetFleetRoleCredentials:           true,
+	RefreshConnection:                 true,
+	RemovePlayerSession:               true,
+}
+
+// IsKnown returns true if the action is sent or received by this version of the SDK.
+func (a MessageAction) IsKnown() bool {
+	return knownActions[a]
+}
+
 type Message struct {
 	Action MessageAction `json:"Action"`
 	// The ID of the request
@@ -50,6 +75,11 @@ type ResponseMessage struct {
 	ErrorMessage string `json:"ErrorMessage"`
 }
 
+// RenewRequestID replaces RequestID with a new UUID, so the request can be sent again as a new request.
+func (m *Message) RenewRequestID() {
+	m.RequestID = uuid.New().String()
+}
+
 // NewMessage retrieves a new generated Message with RequestID filled with UUID random string.
 func NewMessage(action MessageAction) Message {
 	return Message{
@@ -5,9 +5,24 @@
 
 package message
 
+import "net/url"
+
 // RefreshConnectionMessage - Message from GameLift indicating the SDK should refresh its websocket connection.
 type RefreshConnectionMessage struct {
 	Message
 	RefreshConnectionEndpoint string `json:"RefreshConnectionEndpoint"`
 	AuthToken                 string `json:"AuthToken"`
 }
+
+// Validate - checks the fields of the message, see Validator.
+// The AuthToken is optional, as it is not set for connections signed with AWS credentials.
+func (m RefreshConnectionMessage) Validate() error {
+	v := NewValidation(m.Message)
+	v.Required("RefreshConnectionEndpoint", m.RefreshConnectionEndpoint)
+	if m.RefreshConnectionEndpoint != "" {
+		if u, err := url.Parse(m.RefreshConnectionEndpoint); err != nil || u.Host == "" {
+			v.Addf("RefreshConnectionEndpoint must be an absolute URL, got %q", m.RefreshConnectionEndpoint)
+		}
+	}
+	return v.Err()
+}
@@ -11,3 +11,10 @@ type TerminateProcessMessage struct {
 	// TerminationTime is milliseconds that have elapsed since Unix epoch time begins (00:00:00 UTC Jan 1 1970).
 	TerminationTime int64 `json:"TerminationTime"`
 }
+
+// Validate - checks the fields of the message, see Validator.
+func (m TerminateProcessMessage) Validate() error {
+	v := NewValidation(m.Message)
+	v.Min("TerminationTime", m.TerminationTime, 0)
+	return v.Err()
+}
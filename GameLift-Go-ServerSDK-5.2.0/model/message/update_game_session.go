@@ -6,6 +6,7 @@
 package message
 
 import (
+	"aws/amazon-gamelift-go-sdk/common"
 	"aws/amazon-gamelift-go-sdk/model"
 )
 
@@ -15,3 +16,15 @@ type UpdateGameSessionMessage struct {
 	// The UpdateGameSession object
 	model.UpdateGameSession
 }
+
+// Validate - checks the fields of the message, see Validator.
+func (m UpdateGameSessionMessage) Validate() error {
+	v := NewValidation(m.Message)
+	v.Length("GameSession.GameSessionId", m.GameSession.GameSessionID, 1, common.GameSessionIDMaxLength)
+	v.MaxLength("GameSession.MatchmakerData", m.GameSession.MatchmakerData, 390000)
+	v.MaxLength("BackfillTicketId", m.BackfillTicketID, 128)
+	if m.UpdateReason == nil {
+		v.Addf("UpdateReason is required")
+	}
+	return v.Err()
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package message
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"aws/amazon-gamelift-go-sdk/common"
+)
+
+// Port range of game sessions and server processes.
+const (
+	PortMin = 1
+	PortMax = 65535
+)
+
+// Validator - a message or request which checks its fields.
+// Requests are validated before they are sent, and messages from GameLift before they are handled.
+type Validator interface {
+	Validate() error
+}
+
+// Validation - collects the invalid fields of a message, see Validator.
+//
+//	v := message.NewValidation(m.Message)
+//	v.Length("GameSessionId", m.GameSessionID, 1, common.GameSessionIDMaxLength)
+//	return v.Err()
+type Validation struct {
+	action   MessageAction
+	problems []string
+}
+
+// NewValidation - starts the validation of a message, checking its Action and RequestId.
+// The RequestId is optional, as GameLift doesn't set it in the messages it initiates.
+func NewValidation(m Message) *Validation {
+	v := &Validation{action: m.Action}
+	v.Required("Action", string(m.Action))
+	v.MaxLength("RequestId", m.RequestID, 1024)
+	return v
+}
+
+// Addf - adds a problem.
+func (v *Validation) Addf(format string, args ...any) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// Required - checks that value is not empty.
+func (v *Validation) Required(name, value string) {
+	if value == "" {
+		v.Addf("%s is required", name)
+	}
+}
+
+// Length - checks that value has between minLength and maxLength characters.
+func (v *Validation) Length(name, value string, minLength, maxLength int) {
+	if value == "" && minLength > 0 {
+		v.Addf("%s is required", name)
+		return
+	}
+	if n := utf8.RuneCountInString(value); n < minLength || n > maxLength {
+		v.Addf("%s must have %d to %d characters, got %d", name, minLength, maxLength, n)
+	}
+}
+
+// MaxLength - checks that value has at most maxLength characters, empty values are valid.
+func (v *Validation) MaxLength(name, value string, maxLength int) {
+	v.Length(name, value, 0, maxLength)
+}
+
+// Range - checks that value is between minValue and maxValue.
+func (v *Validation) Range(name string, value, minValue, maxValue int) {
+	if value < minValue || value > maxValue {
+		v.Addf("%s must be between %d and %d, got %d", name, minValue, maxValue, value)
+	}
+}
+
+// Min - checks that value is at least minValue.
+func (v *Validation) Min(name string, value, minValue int64) {
+	if value < minValue {
+		v.Addf("%s must be at least %d, got %d", name, minValue, value)
+	}
+}
+
+// OneOf - checks that value is one of allowed, empty values are valid.
+func (v *Validation) OneOf(name, value string, allowed ...string) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.Addf("%s must be one of %s, got %q", name, strings.Join(allowed, ", "), value)
+}
+
+// Err - returns a common.BadRequestException error listing all problems, or nil if the message is valid.
+func (v *Validation) Err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	name := "message"
+	if v.action != "" {
+		name = string(v.action) + " message"
+	}
+	return common.NewGameLiftError(common.BadRequestException, "",
+		fmt.Sprintf("invalid %s: %s", name, strings.Join(v.problems, "; ")))
+}
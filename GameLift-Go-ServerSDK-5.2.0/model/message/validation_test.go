@@ -0,0 +1,109 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package message
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model"
+)
+
+func TestValidate(t *testing.T) {
+	reason := model.MatchmakingDataUpdated
+	createGameSession := CreateGameSessionMessage{
+		Message:       Message{Action: CreateGameSession},
+		GameSessionID: "gsess-1",
+		Port:          1122,
+	}
+	updateGameSession := UpdateGameSessionMessage{
+		Message: Message{Action: UpdateGameSession},
+		UpdateGameSession: model.UpdateGameSession{
+			GameSession:  model.GameSession{GameSessionID: "gsess-1"},
+			UpdateReason: &reason,
+		},
+	}
+	refreshConnection := RefreshConnectionMessage{
+		Message:                   Message{Action: RefreshConnection},
+		RefreshConnectionEndpoint: "wss://example.test",
+	}
+
+	tests := []struct {
+		name     string
+		message  Validator
+		problems []string
+	}{
+		{name: "valid CreateGameSession", message: createGameSession},
+		{
+			name: "CreateGameSession without GameSessionId and Port",
+			message: func() CreateGameSessionMessage {
+				m := createGameSession
+				m.GameSessionID = ""
+				m.Port = 0
+				return m
+			}(),
+			problems: []string{"GameSessionId is required", "Port must be between 1 and 65535, got 0"},
+		},
+		{
+			name: "CreateGameSession with negative MaximumPlayerSessionCount",
+			message: func() CreateGameSessionMessage {
+				m := createGameSession
+				m.MaximumPlayerSessionCount = -1
+				return m
+			}(),
+			problems: []string{"MaximumPlayerSessionCount must be at least 0, got -1"},
+		},
+		{
+			name:     "message without Action",
+			message:  CreateGameSessionMessage{GameSessionID: "gsess-1", Port: 1122},
+			problems: []string{"Action is required"},
+		},
+		{name: "valid UpdateGameSession", message: updateGameSession},
+		{
+			name: "UpdateGameSession without UpdateReason and with a long BackfillTicketId",
+			message: func() UpdateGameSessionMessage {
+				m := updateGameSession
+				m.UpdateReason = nil
+				m.BackfillTicketID = strings.Repeat("t", 129)
+				return m
+			}(),
+			problems: []string{"BackfillTicketId must have 0 to 128 characters, got 129", "UpdateReason is required"},
+		},
+		{name: "valid TerminateProcess", message: TerminateProcessMessage{Message: Message{Action: TerminateProcess}}},
+		{
+			name:     "TerminateProcess with negative TerminationTime",
+			message:  TerminateProcessMessage{Message: Message{Action: TerminateProcess}, TerminationTime: -1},
+			problems: []string{"TerminationTime must be at least 0, got -1"},
+		},
+		{name: "valid RefreshConnection", message: refreshConnection},
+		{
+			name:     "RefreshConnection with relative endpoint",
+			message:  RefreshConnectionMessage{Message: Message{Action: RefreshConnection}, RefreshConnectionEndpoint: "example"},
+			problems: []string{`RefreshConnectionEndpoint must be an absolute URL, got "example"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.message.Validate()
+			if len(tt.problems) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error %s", err)
+				}
+				return
+			}
+			var gameLiftErr *common.GameLiftError
+			if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.BadRequestException {
+				t.Fatalf("unexpected error %v, want a BadRequestException", err)
+			}
+			if !strings.Contains(err.Error(), ": "+strings.Join(tt.problems, "; ")+"}") {
+				t.Fatalf("unexpected error %q, want problems %q", err, tt.problems)
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@
 
 package model
 
+import "encoding/json"
+
 // Player - represents a player in matchmaking.
 // When starting a matchmaking request, a player has a player ID, attributes, and may have latency data.
 type Player struct {
@@ -28,6 +30,9 @@ type Player struct {
 	// Key Length Constraints: Minimum length of 1.
 	// Valid Range: Minimum value of 1.
 	LatencyInMS map[string]int `json:"LatencyInMs"`
+
+	// The fields of the player in MatchmakerData which are not known by the SDK.
+	extra map[string]json.RawMessage
 }
 
 // PlayerSession - details about the connection of a player to your game server.
@@ -74,14 +79,24 @@ type PlayerSession struct {
 	// TIMEDOUT -- A player session request was received, but the player did not connect
 	// and/or was not validated within the timeout limit (60 seconds).
 	// Valid Values: RESERVED | ACTIVE | COMPLETED | TIMEDOUT
-	Status *playerSessionStatus `json:"Status,omitempty"`
+	Status *PlayerSessionStatus `json:"Status,omitempty"`
 }
 
-func (p PlayerSession) WithStatus(status playerSessionStatus) PlayerSession {
+func (p PlayerSession) WithStatus(status PlayerSessionStatus) PlayerSession {
 	p.Status = &status
 	return p
 }
 
-func (p PlayerSession) GetStatus() playerSessionStatus {
-	return *p.Status
+// GetStatus - returns the status of the player session, PlayerNotSet if it is not set.
+func (p PlayerSession) GetStatus() PlayerSessionStatus {
+	status, _ := p.LookupStatus()
+	return status
+}
+
+// LookupStatus - returns the status of the player session and true, or PlayerNotSet and false if it is not set.
+func (p PlayerSession) LookupStatus() (PlayerSessionStatus, bool) {
+	if p.Status == nil {
+		return PlayerNotSet, false
+	}
+	return *p.Status, true
 }
@@ -5,14 +5,15 @@
 
 package model
 
-import "strconv"
+import "fmt"
 
-// playerSessionStatus - unexported (private) data type, has a several predefined values see below
-type playerSessionStatus int
+// PlayerSessionStatus - the status of a player session, has a several predefined values see below.
+// It is also used to filter DescribePlayerSessions.
+type PlayerSessionStatus int
 
 // Possible player session statuses
 const (
-	PlayerNotSet playerSessionStatus = iota
+	PlayerNotSet PlayerSessionStatus = iota
 	PlayerReserved
 	PlayerActive
 	PlayerCompleted
@@ -21,33 +22,42 @@ const (
 
 var playerSessionStatusStrs = []string{"NOT_SET", "RESERVED", "ACTIVE", "COMPLETED", "TIMEDOUT"}
 
-func (p *playerSessionStatus) String() string {
-	n := int(*p)
-	if n >= len(playerSessionStatusStrs) {
-		n = 0
-	}
-	return playerSessionStatusStrs[n]
-}
-
-func (p *playerSessionStatus) toPlayerSessionStatus(s string) {
+// ParsePlayerSessionStatus - returns the status with the name, such as "ACTIVE",
+// or an error if the name is not one of the String values.
+func ParsePlayerSessionStatus(s string) (PlayerSessionStatus, error) {
 	for i := range playerSessionStatusStrs {
 		if playerSessionStatusStrs[i] == s {
-			*p = playerSessionStatus(i)
-			return
+			return PlayerSessionStatus(i), nil
 		}
 	}
-	*p = PlayerNotSet
+	return PlayerNotSet, fmt.Errorf("unknown player session status %q", s)
+}
+
+// PlayerSessionStatusValues - returns the statuses a player session can have, all except PlayerNotSet.
+func PlayerSessionStatusValues() []PlayerSessionStatus {
+	return []PlayerSessionStatus{PlayerReserved, PlayerActive, PlayerCompleted, PlayerTimedout}
+}
+
+func (p PlayerSessionStatus) String() string {
+	n := int(p)
+	if n < 0 || n >= len(playerSessionStatusStrs) {
+		n = 0
+	}
+	return playerSessionStatusStrs[n]
 }
 
-func (p *playerSessionStatus) MarshalJSON() ([]byte, error) {
-	return []byte(strconv.Quote(p.String())), nil
+// MarshalText - implements encoding.TextMarshaler, so the status is encoded by name in JSON.
+func (p PlayerSessionStatus) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
 }
 
-func (p *playerSessionStatus) UnmarshalJSON(data []byte) error {
-	origin, err := strconv.Unquote(string(data))
+// UnmarshalText - implements encoding.TextUnmarshaler. Unknown names are decoded as PlayerNotSet,
+// so new statuses of GameLift don't break the decoding of the messages.
+func (p *PlayerSessionStatus) UnmarshalText(data []byte) error {
+	status, err := ParsePlayerSessionStatus(string(data))
 	if err != nil {
-		return err
+		status = PlayerNotSet
 	}
-	p.toPlayerSessionStatus(origin)
+	*p = status
 	return nil
 }
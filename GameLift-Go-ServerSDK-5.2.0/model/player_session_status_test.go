@@ -12,7 +12,7 @@ import (
 )
 
 func TestPlayerSessionStatus_MarshalJSON(t *testing.T) {
-	cases := map[playerSessionStatus]string{
+	cases := map[PlayerSessionStatus]string{
 		PlayerActive:    "\"ACTIVE\"",
 		PlayerCompleted: "\"COMPLETED\"",
 		PlayerNotSet:    "\"NOT_SET\"",
@@ -23,7 +23,7 @@ func TestPlayerSessionStatus_MarshalJSON(t *testing.T) {
 	for key := range cases {
 		val, err := json.Marshal(&key)
 		if err != nil {
-			t.Errorf("json marshal playerSessionStatus error: %s", err.Error())
+			t.Errorf("json marshal PlayerSessionStatus error: %s", err.Error())
 			return
 		}
 		if !strings.EqualFold(cases[key], string(val)) {
@@ -34,7 +34,7 @@ func TestPlayerSessionStatus_MarshalJSON(t *testing.T) {
 }
 
 func TestPlayerSessionStatus_UnmarshalJSON(t *testing.T) {
-	cases := map[playerSessionStatus]string{
+	cases := map[PlayerSessionStatus]string{
 		PlayerActive:    "\"ACTIVE\"",
 		PlayerCompleted: "\"COMPLETED\"",
 		PlayerNotSet:    "\"NOT_SET\"",
@@ -43,12 +43,49 @@ func TestPlayerSessionStatus_UnmarshalJSON(t *testing.T) {
 	}
 
 	for key, v := range cases {
-		var val playerSessionStatus
+		var val PlayerSessionStatus
 		if err := json.Unmarshal([]byte(v), &val); err != nil {
-			t.Errorf("json unmarshal playerSessionStatus error: %s", err.Error())
+			t.Errorf("json unmarshal PlayerSessionStatus error: %s", err.Error())
 		}
 		if val != key {
 			t.Errorf("failed ")
 		}
 	}
 }
+
+func TestParsePlayerSessionStatus(t *testing.T) {
+	for _, status := range append(PlayerSessionStatusValues(), PlayerNotSet) {
+		parsed, err := ParsePlayerSessionStatus(status.String())
+		if err != nil {
+			t.Fatalf("parse %s: %s", status, err)
+		}
+		if parsed != status {
+			t.Errorf("expect %s but get %s", status, parsed)
+		}
+	}
+	if _, err := ParsePlayerSessionStatus("EXPIRED"); err == nil {
+		t.Error("expect an error for an unknown status")
+	}
+}
+
+func TestPlayerSessionStatus_UnmarshalText_Unknown(t *testing.T) {
+	status := PlayerActive
+	if err := json.Unmarshal([]byte(`"EXPIRED"`), &status); err != nil {
+		t.Fatal(err)
+	}
+	if status != PlayerNotSet {
+		t.Errorf("expect %s but get %s", PlayerNotSet, status)
+	}
+}
+
+func TestPlayerSession_LookupStatus(t *testing.T) {
+	var playerSession PlayerSession
+	if status, ok := playerSession.LookupStatus(); ok || status != PlayerNotSet {
+		t.Errorf("expect %s and false but get %s and %t", PlayerNotSet, status, ok)
+	}
+
+	playerSession = playerSession.WithStatus(PlayerCompleted)
+	if status, ok := playerSession.LookupStatus(); !ok || status != PlayerCompleted {
+		t.Errorf("expect %s and true but get %s and %t", PlayerCompleted, status, ok)
+	}
+}
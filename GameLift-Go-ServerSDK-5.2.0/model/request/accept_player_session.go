@@ -6,6 +6,7 @@
 package request
 
 import (
+	"aws/amazon-gamelift-go-sdk/common"
 	"aws/amazon-gamelift-go-sdk/model/message"
 )
 
@@ -31,3 +32,11 @@ func NewAcceptPlayerSession(gameSessionID, playerSessionID string) AcceptPlayerS
 		PlayerSessionID: playerSessionID,
 	}
 }
+
+// Validate - checks the fields of the request, see message.Validator.
+func (r AcceptPlayerSessionRequest) Validate() error {
+	v := message.NewValidation(r.Message)
+	v.Length("GameSessionId", r.GameSessionID, 1, common.GameSessionIDMaxLength)
+	v.Length("PlayerSessionId", r.PlayerSessionID, 1, 1024)
+	return v.Err()
+}
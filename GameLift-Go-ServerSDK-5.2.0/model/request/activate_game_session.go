@@ -5,7 +5,10 @@
 
 package request
 
-import "aws/amazon-gamelift-go-sdk/model/message"
+import (
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model/message"
+)
 
 // ActivateGameSessionRequest - This request is sent to the GameLift WebSocket during ActivateGameSessionRequest.
 //
@@ -25,3 +28,10 @@ func NewActivateGameSession(gameSessionID string) ActivateGameSessionRequest {
 		GameSessionID: gameSessionID,
 	}
 }
+
+// Validate - checks the fields of the request, see message.Validator.
+func (r ActivateGameSessionRequest) Validate() error {
+	v := message.NewValidation(r.Message)
+	v.Length("GameSessionId", r.GameSessionID, 1, common.GameSessionIDMaxLength)
+	return v.Err()
+}
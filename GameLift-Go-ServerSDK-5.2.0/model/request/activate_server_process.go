@@ -6,6 +6,8 @@
 package request
 
 import (
+	"fmt"
+
 	"aws/amazon-gamelift-go-sdk/model/message"
 )
 
@@ -41,3 +43,15 @@ func NewActivateServerProcess(sdkVersion, sdkLanguage string, port int) Activate
 		Port:        port,
 	}
 }
+
+// Validate - checks the fields of the request, see message.Validator.
+func (r ActivateServerProcessRequest) Validate() error {
+	v := message.NewValidation(r.Message)
+	v.Required("SdkVersion", r.SdkVersion)
+	v.Required("SdkLanguage", r.SdkLanguage)
+	v.Range("Port", r.Port, message.PortMin, message.PortMax)
+	for i, logPath := range r.LogPaths {
+		v.Required(fmt.Sprintf("LogPaths[%d]", i), logPath)
+	}
+	return v.Err()
+}
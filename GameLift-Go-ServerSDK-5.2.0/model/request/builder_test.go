@@ -0,0 +1,253 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package request
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model"
+	"aws/amazon-gamelift-go-sdk/model/message"
+)
+
+func TestDescribePlayerSessionsBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *DescribePlayerSessionsBuilder
+		expected DescribePlayerSessionsRequest
+		problems []string
+	}{
+		{
+			name: "game session with status filter",
+			builder: NewDescribePlayerSessionsBuilder().
+				WithGameSessionID("gsess-1").
+				WithPlayerSessionStatusFilter(model.PlayerActive).
+				WithNextToken("token").
+				WithLimit(10),
+			expected: DescribePlayerSessionsRequest{
+				GameSessionID:             "gsess-1",
+				PlayerSessionStatusFilter: "ACTIVE",
+				NextToken:                 "token",
+				Limit:                     10,
+			},
+		},
+		{
+			name:     "player",
+			builder:  NewDescribePlayerSessionsBuilder().WithPlayerID("player-1"),
+			expected: DescribePlayerSessionsRequest{PlayerID: "player-1"},
+		},
+		{
+			name:     "player session",
+			builder:  NewDescribePlayerSessionsBuilder().WithPlayerSessionID("psess-1"),
+			expected: DescribePlayerSessionsRequest{PlayerSessionID: "psess-1"},
+		},
+		{
+			name:     "player and player session",
+			builder:  NewDescribePlayerSessionsBuilder().WithPlayerID("player-1").WithPlayerSessionID("psess-1"),
+			problems: []string{"exactly one of GameSessionId, PlayerId or PlayerSessionId is required, got 2"},
+		},
+		{
+			name: "status filter not set",
+			builder: NewDescribePlayerSessionsBuilder().
+				WithGameSessionID("gsess-1").
+				WithPlayerSessionStatusFilter(model.PlayerNotSet),
+			problems: []string{`PlayerSessionStatusFilter must be one of RESERVED, ACTIVE, COMPLETED, TIMEDOUT, got "NOT_SET"`},
+		},
+		{
+			name:     "limit out of range",
+			builder:  NewDescribePlayerSessionsBuilder().WithGameSessionID("gsess-1").WithLimit(-1),
+			problems: []string{"Limit must be between 1 and 1024, got -1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.builder.Build()
+			assertProblems(t, err, tt.problems)
+			assertMessage(t, req.Message, message.DescribePlayerSessions)
+			if err == nil {
+				req.Message = message.Message{}
+				if !reflect.DeepEqual(req, tt.expected) {
+					t.Fatalf("unexpected request %+v, want %+v", req, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestStartMatchBackfillBuilder(t *testing.T) {
+	players := []model.Player{{PlayerID: "player-1", Team: "red"}, {PlayerID: "player-2", Team: "blue"}}
+
+	tests := []struct {
+		name     string
+		builder  *StartMatchBackfillBuilder
+		expected StartMatchBackfillRequest
+		problems []string
+	}{
+		{
+			name: "all fields",
+			builder: NewStartMatchBackfillBuilder(testMatchmakingConfigurationArn).
+				WithGameSessionArn("gsess-1").
+				WithTicketID("ticket-1").
+				WithPlayers(players[0]).
+				WithPlayers(players[1]),
+			expected: StartMatchBackfillRequest{
+				GameSessionArn:              "gsess-1",
+				MatchmakingConfigurationArn: testMatchmakingConfigurationArn,
+				TicketID:                    "ticket-1",
+				Players:                     players,
+			},
+		},
+		{
+			name:     "missing matchmaking configuration",
+			builder:  NewStartMatchBackfillBuilder("").WithPlayers(players...),
+			problems: []string{"MatchmakingConfigurationArn is required"},
+		},
+		{
+			name:     "no players",
+			builder:  NewStartMatchBackfillBuilder(testMatchmakingConfigurationArn),
+			problems: []string{"Players is required"},
+		},
+		{
+			name: "long ticket ID",
+			builder: NewStartMatchBackfillBuilder(testMatchmakingConfigurationArn).
+				WithPlayers(players...).
+				WithTicketID(strings.Repeat("t", 129)),
+			problems: []string{"TicketId must have 0 to 128 characters, got 129"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.builder.Build()
+			assertProblems(t, err, tt.problems)
+			assertMessage(t, req.Message, message.StartMatchBackfill)
+			if err == nil {
+				req.Message = message.Message{}
+				if !reflect.DeepEqual(req, tt.expected) {
+					t.Fatalf("unexpected request %+v, want %+v", req, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestStopMatchBackfillBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *StopMatchBackfillBuilder
+		expected StopMatchBackfillRequest
+		problems []string
+	}{
+		{
+			name:    "all fields",
+			builder: NewStopMatchBackfillBuilder(testMatchmakingConfigurationArn, "ticket-1").WithGameSessionArn("gsess-1"),
+			expected: StopMatchBackfillRequest{
+				GameSessionArn:              "gsess-1",
+				MatchmakingConfigurationArn: testMatchmakingConfigurationArn,
+				TicketID:                    "ticket-1",
+			},
+		},
+		{
+			name:     "missing ticket ID",
+			builder:  NewStopMatchBackfillBuilder(testMatchmakingConfigurationArn, ""),
+			problems: []string{"TicketId is required"},
+		},
+		{
+			name:    "invalid matchmaking configuration",
+			builder: NewStopMatchBackfillBuilder("arn:aws:gamelift:us-west-2:123456789012:fleet/fleet-1", "ticket-1"),
+			problems: []string{"MatchmakingConfigurationArn must match ^arn:.*:matchmakingconfiguration/[a-zA-Z0-9-.]*$, " +
+				`got "arn:aws:gamelift:us-west-2:123456789012:fleet/fleet-1"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.builder.Build()
+			assertProblems(t, err, tt.problems)
+			assertMessage(t, req.Message, message.StopMatchBackfill)
+			if err == nil {
+				req.Message = message.Message{}
+				if !reflect.DeepEqual(req, tt.expected) {
+					t.Fatalf("unexpected request %+v, want %+v", req, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestGetFleetRoleCredentialsBuilder(t *testing.T) {
+	const roleArn = "arn:aws:iam::123456789012:role/test"
+
+	tests := []struct {
+		name     string
+		builder  *GetFleetRoleCredentialsBuilder
+		expected GetFleetRoleCredentialsRequest
+		problems []string
+	}{
+		{
+			name:     "default role session name",
+			builder:  NewGetFleetRoleCredentialsBuilder(roleArn),
+			expected: GetFleetRoleCredentialsRequest{RoleArn: roleArn},
+		},
+		{
+			name:     "role session name",
+			builder:  NewGetFleetRoleCredentialsBuilder(roleArn).WithRoleSessionName("game-server"),
+			expected: GetFleetRoleCredentialsRequest{RoleArn: roleArn, RoleSessionName: "game-server"},
+		},
+		{
+			name:     "short role ARN",
+			builder:  NewGetFleetRoleCredentialsBuilder("arn:aws:iam::role"),
+			problems: []string{"RoleArn must have 20 to 2048 characters, got 17"},
+		},
+		{
+			name:     "short role session name",
+			builder:  NewGetFleetRoleCredentialsBuilder(roleArn).WithRoleSessionName("g"),
+			problems: []string{"RoleSessionName must have 2 to 64 characters, got 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.builder.Build()
+			assertProblems(t, err, tt.problems)
+			assertMessage(t, req.Message, message.GetFleetRoleCredentials)
+			if err == nil {
+				req.Message = message.Message{}
+				if !reflect.DeepEqual(req, tt.expected) {
+					t.Fatalf("unexpected request %+v, want %+v", req, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func assertProblems(t *testing.T, err error, problems []string) {
+	t.Helper()
+	if len(problems) == 0 {
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		return
+	}
+	var gameLiftErr *common.GameLiftError
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.BadRequestException {
+		t.Fatalf("unexpected error %v, want a BadRequestException", err)
+	}
+	if !strings.Contains(err.Error(), ": "+strings.Join(problems, "; ")+"}") {
+		t.Fatalf("unexpected error %q, want problems %q", err, problems)
+	}
+}
+
+func assertMessage(t *testing.T, msg message.Message, action message.MessageAction) {
+	t.Helper()
+	if msg.Action != action || msg.RequestID == "" {
+		t.Fatalf("unexpected message %+v, want a %s message with a RequestId", msg, action)
+	}
+}
@@ -6,17 +6,19 @@
 package request
 
 import (
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model"
 	"aws/amazon-gamelift-go-sdk/model/message"
 )
 
 // DescribePlayerSessionsRequest - This request is sent to the GameLift WebSocket
 // during a DescribePlayerSessionsRequest call.
 //
-// Please use NewDescribePlayerSessions function to create this request.
+// Please use NewDescribePlayerSessions function or NewDescribePlayerSessionsBuilder to create this request.
 type DescribePlayerSessionsRequest struct {
 	message.Message
 	// Unique identifier for the game session to get player sessions for.
-	// Maximum length: 256
+	// Maximum length: 1024
 	GameSessionID string `json:"GameSessionId,omitempty"`
 	// A unique identifier for a player to retrieve player sessions for.
 	// Maximum length: 1024
@@ -52,3 +54,100 @@ func NewDescribePlayerSessions() DescribePlayerSessionsRequest {
 		Message: message.NewMessage(message.DescribePlayerSessions),
 	}
 }
+
+// playerSessionStatusFilters - the valid values of DescribePlayerSessionsRequest.PlayerSessionStatusFilter.
+var playerSessionStatusFilters = func() []string {
+	statuses := model.PlayerSessionStatusValues()
+	filters := make([]string, 0, len(statuses))
+	for i := range statuses {
+		filters = append(filters, statuses[i].String())
+	}
+	return filters
+}()
+
+// DescribePlayerSessionsBuilder - builds a DescribePlayerSessionsRequest of exactly one game session,
+// player or player session.
+//
+//	req, err := request.NewDescribePlayerSessionsBuilder().
+//		WithGameSessionID(gameSessionID).
+//		WithPlayerSessionStatusFilter(model.PlayerActive).
+//		WithLimit(10).
+//		Build()
+type DescribePlayerSessionsBuilder struct {
+	req DescribePlayerSessionsRequest
+}
+
+// NewDescribePlayerSessionsBuilder - creates a builder of a DescribePlayerSessionsRequest with a new RequestID.
+func NewDescribePlayerSessionsBuilder() *DescribePlayerSessionsBuilder {
+	return &DescribePlayerSessionsBuilder{req: NewDescribePlayerSessions()}
+}
+
+// WithGameSessionID - retrieves the player sessions of the game session.
+func (b *DescribePlayerSessionsBuilder) WithGameSessionID(gameSessionID string) *DescribePlayerSessionsBuilder {
+	b.req.GameSessionID = gameSessionID
+	return b
+}
+
+// WithPlayerID - retrieves the player sessions of the player.
+func (b *DescribePlayerSessionsBuilder) WithPlayerID(playerID string) *DescribePlayerSessionsBuilder {
+	b.req.PlayerID = playerID
+	return b
+}
+
+// WithPlayerSessionID - retrieves a single player session.
+func (b *DescribePlayerSessionsBuilder) WithPlayerSessionID(playerSessionID string) *DescribePlayerSessionsBuilder {
+	b.req.PlayerSessionID = playerSessionID
+	return b
+}
+
+// WithPlayerSessionStatusFilter - retrieves only the player sessions with the status.
+func (b *DescribePlayerSessionsBuilder) WithPlayerSessionStatusFilter(status model.PlayerSessionStatus) *DescribePlayerSessionsBuilder {
+	b.req.PlayerSessionStatusFilter = status.String()
+	return b
+}
+
+// WithNextToken - starts from the page following the one which returned the token.
+func (b *DescribePlayerSessionsBuilder) WithNextToken(nextToken string) *DescribePlayerSessionsBuilder {
+	b.req.NextToken = nextToken
+	return b
+}
+
+// WithLimit - sets the max number of player sessions per page, between 1 and 1024.
+func (b *DescribePlayerSessionsBuilder) WithLimit(limit int) *DescribePlayerSessionsBuilder {
+	b.req.Limit = limit
+	return b
+}
+
+// Build - returns the request, or a common.BadRequestException error if it is invalid, see Validate.
+func (b *DescribePlayerSessionsBuilder) Build() (DescribePlayerSessionsRequest, error) {
+	return b.req, b.req.Validate()
+}
+
+// Validate - checks the fields of the request, see message.Validator.
+func (r DescribePlayerSessionsRequest) Validate() error {
+	v := message.NewValidation(r.Message)
+	if n := countNotEmpty(r.GameSessionID, r.PlayerID, r.PlayerSessionID); n != 1 {
+		v.Addf("exactly one of GameSessionId, PlayerId or PlayerSessionId is required, got %d", n)
+	}
+	v.MaxLength("GameSessionId", r.GameSessionID, common.GameSessionIDMaxLength)
+	v.MaxLength("PlayerId", r.PlayerID, 1024)
+	v.MaxLength("PlayerSessionId", r.PlayerSessionID, 1024)
+	// NOT_SET, the filter of model.PlayerNotSet and unknown statuses, is rejected too
+	v.OneOf("PlayerSessionStatusFilter", r.PlayerSessionStatusFilter, playerSessionStatusFilters...)
+	v.MaxLength("NextToken", r.NextToken, 1024)
+	// Zero is omitted from the request, so GameLift uses its default limit
+	if r.Limit != 0 {
+		v.Range("Limit", r.Limit, 1, 1024)
+	}
+	return v.Err()
+}
+
+func countNotEmpty(values ...string) int {
+	n := 0
+	for _, value := range values {
+		if value != "" {
+			n++
+		}
+	}
+	return n
+}
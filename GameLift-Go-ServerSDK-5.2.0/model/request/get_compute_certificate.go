@@ -24,3 +24,8 @@ func NewGetComputeCertificate() GetComputeCertificateRequest {
 		Message: message.NewMessage(message.GetComputeCertificate),
 	}
 }
+
+// Validate - checks the fields of the request, see message.Validator.
+func (r GetComputeCertificateRequest) Validate() error {
+	return message.NewValidation(r.Message).Err()
+}
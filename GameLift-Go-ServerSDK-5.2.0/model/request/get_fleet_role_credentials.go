@@ -6,12 +6,13 @@
 package request
 
 import (
+	"aws/amazon-gamelift-go-sdk/common"
 	"aws/amazon-gamelift-go-sdk/model/message"
 )
 
 // GetFleetRoleCredentialsRequest - Request to the Gamelift to get credentials for the fleet.
 //
-// Please use NewGetFleetRoleCredentials to create a new request.
+// Please use NewGetFleetRoleCredentials or NewGetFleetRoleCredentialsBuilder to create a new request.
 type GetFleetRoleCredentialsRequest struct {
 	message.Message
 	// The Amazon Resource Name (ARN) of the role to assume.
@@ -29,3 +30,42 @@ func NewGetFleetRoleCredentials() GetFleetRoleCredentialsRequest {
 		Message: message.NewMessage(message.GetFleetRoleCredentials),
 	}
 }
+
+// GetFleetRoleCredentialsBuilder - builds a GetFleetRoleCredentialsRequest.
+//
+//	req, err := request.NewGetFleetRoleCredentialsBuilder(roleArn).
+//		WithRoleSessionName("my-game-server").
+//		Build()
+type GetFleetRoleCredentialsBuilder struct {
+	req GetFleetRoleCredentialsRequest
+}
+
+// NewGetFleetRoleCredentialsBuilder - creates a builder of a GetFleetRoleCredentialsRequest with a new RequestID
+// for the role to assume.
+func NewGetFleetRoleCredentialsBuilder(roleArn string) *GetFleetRoleCredentialsBuilder {
+	req := NewGetFleetRoleCredentials()
+	req.RoleArn = roleArn
+	return &GetFleetRoleCredentialsBuilder{req: req}
+}
+
+// WithRoleSessionName - sets the name of the assumed role session, fleetId-hostId if it is not set.
+func (b *GetFleetRoleCredentialsBuilder) WithRoleSessionName(roleSessionName string) *GetFleetRoleCredentialsBuilder {
+	b.req.RoleSessionName = roleSessionName
+	return b
+}
+
+// Build - returns the request, or a common.BadRequestException error if it is invalid, see Validate.
+func (b *GetFleetRoleCredentialsBuilder) Build() (GetFleetRoleCredentialsRequest, error) {
+	return b.req, b.req.Validate()
+}
+
+// Validate - checks the fields of the request, see message.Validator.
+// The RoleSessionName is optional, server.GetFleetRoleCredentials sets it to fleetId-hostId if it is empty.
+func (r GetFleetRoleCredentialsRequest) Validate() error {
+	v := message.NewValidation(r.Message)
+	v.Length("RoleArn", r.RoleArn, 20, 2048)
+	if r.RoleSessionName != "" {
+		v.Length("RoleSessionName", r.RoleSessionName, 2, common.RoleSessionNameMaxLength)
+	}
+	return v.Err()
+}
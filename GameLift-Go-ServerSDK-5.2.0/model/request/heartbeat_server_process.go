@@ -24,3 +24,8 @@ func NewHeartbeatServerProcess(status bool) HeartbeatServerProcessRequest {
 		HealthStatus: status,
 	}
 }
+
+// Validate - checks the fields of the request, see message.Validator.
+func (r HeartbeatServerProcessRequest) Validate() error {
+	return message.NewValidation(r.Message).Err()
+}
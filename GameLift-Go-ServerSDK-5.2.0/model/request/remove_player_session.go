@@ -6,6 +6,7 @@
 package request
 
 import (
+	"aws/amazon-gamelift-go-sdk/common"
 	"aws/amazon-gamelift-go-sdk/model/message"
 )
 
@@ -15,7 +16,7 @@ import (
 type RemovePlayerSessionRequest struct {
 	message.Message
 	// Unique identifier for the game session to get player sessions for.
-	// Maximum length: 256
+	// Length Constraints: Minimum length of 1. Maximum length of 1024.
 	GameSessionID string `json:"GameSessionId,omitempty"`
 	// A unique identifier for a player session to retrieve.
 	PlayerSessionID string `json:"PlayerSessionId,omitempty"`
@@ -30,3 +31,11 @@ func NewRemovePlayerSession(gameSessionID, playerSessionID string) RemovePlayerS
 		PlayerSessionID: playerSessionID,
 	}
 }
+
+// Validate - checks the fields of the request, see message.Validator.
+func (r RemovePlayerSessionRequest) Validate() error {
+	v := message.NewValidation(r.Message)
+	v.Length("GameSessionId", r.GameSessionID, 1, common.GameSessionIDMaxLength)
+	v.Length("PlayerSessionId", r.PlayerSessionID, 1, 1024)
+	return v.Err()
+}
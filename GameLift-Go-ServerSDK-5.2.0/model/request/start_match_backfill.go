@@ -6,13 +6,16 @@
 package request
 
 import (
+	"fmt"
+	"regexp"
+
 	"aws/amazon-gamelift-go-sdk/model"
 	"aws/amazon-gamelift-go-sdk/model/message"
 )
 
 // StartMatchBackfillRequest - This request is sent to the GameLift WebSocket during a DescribePlayerSessionsRequest call.
 //
-// Please use NewStartMatchBackfill to create a new request.
+// Please use NewStartMatchBackfill or NewStartMatchBackfillBuilder to create a new request.
 type StartMatchBackfillRequest struct {
 	message.Message
 	// A unique identifier for the game session. Use the game session ID.
@@ -44,3 +47,79 @@ func NewStartMatchBackfill(gameSessionArn, matchmakingConfigurationArn string, p
 		Players:                     players,
 	}
 }
+
+// StartMatchBackfillBuilder - builds a StartMatchBackfillRequest.
+//
+//	req, err := request.NewStartMatchBackfillBuilder(matchmakingConfigurationArn).
+//		WithGameSessionArn(gameSessionID).
+//		WithPlayers(matchmakerData.Players...).
+//		Build()
+type StartMatchBackfillBuilder struct {
+	req StartMatchBackfillRequest
+}
+
+// NewStartMatchBackfillBuilder - creates a builder of a StartMatchBackfillRequest with a new RequestID
+// for the matchmaking configuration.
+func NewStartMatchBackfillBuilder(matchmakingConfigurationArn string) *StartMatchBackfillBuilder {
+	return &StartMatchBackfillBuilder{req: NewStartMatchBackfill("", matchmakingConfigurationArn, nil)}
+}
+
+// WithGameSessionArn - sets the game session to backfill, not needed for standalone FlexMatch.
+func (b *StartMatchBackfillBuilder) WithGameSessionArn(gameSessionArn string) *StartMatchBackfillBuilder {
+	b.req.GameSessionArn = gameSessionArn
+	return b
+}
+
+// WithTicketID - sets the ID of the backfill ticket, GameLift generates one if it is not set.
+func (b *StartMatchBackfillBuilder) WithTicketID(ticketID string) *StartMatchBackfillBuilder {
+	b.req.TicketID = ticketID
+	return b
+}
+
+// WithPlayers - adds the players currently assigned to the game session.
+func (b *StartMatchBackfillBuilder) WithPlayers(players ...model.Player) *StartMatchBackfillBuilder {
+	b.req.Players = append(b.req.Players, players...)
+	return b
+}
+
+// Build - returns the request, or a common.BadRequestException error if it is invalid, see Validate.
+func (b *StartMatchBackfillBuilder) Build() (StartMatchBackfillRequest, error) {
+	return b.req, b.req.Validate()
+}
+
+// matchmakingConfigurationArnPattern - the pattern of the MatchmakingConfigurationArn of the backfill requests.
+var matchmakingConfigurationArnPattern = regexp.MustCompile(`^arn:.*:matchmakingconfiguration/[a-zA-Z0-9-.]*$`)
+
+// Validate - checks the fields of the request, see message.Validator.
+func (r StartMatchBackfillRequest) Validate() error {
+	v := message.NewValidation(r.Message)
+	v.MaxLength("GameSessionArn", r.GameSessionArn, 256)
+	validateMatchmakingConfigurationArn(v, r.MatchmakingConfigurationArn)
+	v.MaxLength("TicketId", r.TicketID, 128)
+	if len(r.Players) == 0 {
+		v.Addf("Players is required")
+	}
+	for i := range r.Players {
+		validatePlayer(v, fmt.Sprintf("Players[%d]", i), &r.Players[i])
+	}
+	return v.Err()
+}
+
+func validateMatchmakingConfigurationArn(v *message.Validation, arn string) {
+	v.Required("MatchmakingConfigurationArn", arn)
+	if arn != "" && !matchmakingConfigurationArnPattern.MatchString(arn) {
+		v.Addf("MatchmakingConfigurationArn must match %s, got %q", matchmakingConfigurationArnPattern, arn)
+	}
+}
+
+func validatePlayer(v *message.Validation, name string, player *model.Player) {
+	v.Length(name+".PlayerId", player.PlayerID, 1, 1024)
+	v.MaxLength(name+".Team", player.Team, 1024)
+	for key := range player.PlayerAttributes {
+		v.Length(name+".PlayerAttributes key", key, 1, 1024)
+	}
+	for region, latency := range player.LatencyInMS {
+		v.Required(name+".LatencyInMs key", region)
+		v.Min(fmt.Sprintf("%s.LatencyInMs[%s]", name, region), int64(latency), 1)
+	}
+}
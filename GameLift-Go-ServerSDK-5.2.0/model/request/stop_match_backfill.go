@@ -11,7 +11,7 @@ import (
 
 // StopMatchBackfillRequest
 //
-// Please use NewStopMatchBackfill to create a new request.
+// Please use NewStopMatchBackfill or NewStopMatchBackfillBuilder to create a new request.
 type StopMatchBackfillRequest struct {
 	message.Message
 	// A unique identifier for the game session. Use the game session ID.
@@ -34,3 +34,41 @@ func NewStopMatchBackfill() StopMatchBackfillRequest {
 		Message: message.NewMessage(message.StopMatchBackfill),
 	}
 }
+
+// StopMatchBackfillBuilder - builds a StopMatchBackfillRequest.
+//
+//	req, err := request.NewStopMatchBackfillBuilder(matchmakingConfigurationArn, ticketID).
+//		WithGameSessionArn(gameSessionID).
+//		Build()
+type StopMatchBackfillBuilder struct {
+	req StopMatchBackfillRequest
+}
+
+// NewStopMatchBackfillBuilder - creates a builder of a StopMatchBackfillRequest with a new RequestID
+// for the ticket of the matchmaking configuration.
+func NewStopMatchBackfillBuilder(matchmakingConfigurationArn, ticketID string) *StopMatchBackfillBuilder {
+	req := NewStopMatchBackfill()
+	req.MatchmakingConfigurationArn = matchmakingConfigurationArn
+	req.TicketID = ticketID
+	return &StopMatchBackfillBuilder{req: req}
+}
+
+// WithGameSessionArn - sets the game session of the backfill ticket, not needed for standalone FlexMatch.
+func (b *StopMatchBackfillBuilder) WithGameSessionArn(gameSessionArn string) *StopMatchBackfillBuilder {
+	b.req.GameSessionArn = gameSessionArn
+	return b
+}
+
+// Build - returns the request, or a common.BadRequestException error if it is invalid, see Validate.
+func (b *StopMatchBackfillBuilder) Build() (StopMatchBackfillRequest, error) {
+	return b.req, b.req.Validate()
+}
+
+// Validate - checks the fields of the request, see message.Validator.
+func (r StopMatchBackfillRequest) Validate() error {
+	v := message.NewValidation(r.Message)
+	v.MaxLength("GameSessionArn", r.GameSessionArn, 256)
+	validateMatchmakingConfigurationArn(v, r.MatchmakingConfigurationArn)
+	v.Length("TicketId", r.TicketID, 1, 128)
+	return v.Err()
+}
@@ -23,3 +23,8 @@ func NewTerminateServerProcess() TerminateServerProcessRequest {
 		Message: message.NewMessage(message.TerminateServerProcess),
 	}
 }
+
+// Validate - checks the fields of the request, see message.Validator.
+func (r TerminateServerProcessRequest) Validate() error {
+	return message.NewValidation(r.Message).Err()
+}
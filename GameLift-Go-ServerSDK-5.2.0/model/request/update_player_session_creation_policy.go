@@ -6,6 +6,7 @@
 package request
 
 import (
+	"aws/amazon-gamelift-go-sdk/common"
 	"aws/amazon-gamelift-go-sdk/model"
 	"aws/amazon-gamelift-go-sdk/model/message"
 )
@@ -16,7 +17,7 @@ import (
 type UpdatePlayerSessionCreationPolicyRequest struct {
 	message.Message
 	// A unique identifier for the game session to update.
-	// Length Constraints: Minimum length of 1. Maximum length of 256.
+	// Length Constraints: Minimum length of 1. Maximum length of 1024.
 	GameSessionID string `json:"GameSessionId,omitempty"`
 	// A policy that determines whether the game session is accepting new players.
 	// Valid Values: model.AcceptAll | model.DenyAll
@@ -35,3 +36,13 @@ func NewUpdatePlayerSessionCreationPolicy(
 		PlayerSessionPolicy: &policy,
 	}
 }
+
+// Validate - checks the fields of the request, see message.Validator.
+func (r UpdatePlayerSessionCreationPolicyRequest) Validate() error {
+	v := message.NewValidation(r.Message)
+	v.Length("GameSessionId", r.GameSessionID, 1, common.GameSessionIDMaxLength)
+	if r.PlayerSessionPolicy == nil || (*r.PlayerSessionPolicy != model.AcceptAll && *r.PlayerSessionPolicy != model.DenyAll) {
+		v.Addf("PlayerSessionPolicy must be ACCEPT_ALL or DENY_ALL")
+	}
+	return v.Err()
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package request
+
+import (
+	"strings"
+	"testing"
+
+	"aws/amazon-gamelift-go-sdk/model"
+	"aws/amazon-gamelift-go-sdk/model/message"
+)
+
+const testMatchmakingConfigurationArn = "arn:aws:gamelift:us-west-2:123456789012:matchmakingconfiguration/test-config"
+
+func TestValidate(t *testing.T) {
+	describePlayerSessions := func(modify func(r *DescribePlayerSessionsRequest)) DescribePlayerSessionsRequest {
+		r := NewDescribePlayerSessions()
+		r.GameSessionID = "gsess-1"
+		modify(&r)
+		return r
+	}
+	fleetRoleCredentials := func(roleArn, roleSessionName string) GetFleetRoleCredentialsRequest {
+		r := NewGetFleetRoleCredentials()
+		r.RoleArn = roleArn
+		r.RoleSessionName = roleSessionName
+		return r
+	}
+	startMatchBackfill := func(arn string, players ...model.Player) StartMatchBackfillRequest {
+		return NewStartMatchBackfill("gsess-1", arn, players)
+	}
+	stopMatchBackfill := func(arn, ticketID string) StopMatchBackfillRequest {
+		r := NewStopMatchBackfill()
+		r.MatchmakingConfigurationArn = arn
+		r.TicketID = ticketID
+		return r
+	}
+	player := model.Player{PlayerID: "player-1", LatencyInMS: map[string]int{"us-west-2": 20}}
+
+	tests := []struct {
+		name     string
+		request  message.Validator
+		problems []string
+	}{
+		{name: "valid AcceptPlayerSession", request: NewAcceptPlayerSession("gsess-1", "psess-1")},
+		{
+			name:     "AcceptPlayerSession without PlayerSessionId",
+			request:  NewAcceptPlayerSession("gsess-1", ""),
+			problems: []string{"PlayerSessionId is required"},
+		},
+		{name: "valid ActivateGameSession", request: NewActivateGameSession("gsess-1")},
+		{
+			name:     "ActivateGameSession without GameSessionId",
+			request:  NewActivateGameSession(""),
+			problems: []string{"GameSessionId is required"},
+		},
+		{name: "valid ActivateServerProcess", request: NewActivateServerProcess("5.0.0", "Go", 8080)},
+		{
+			name:     "ActivateServerProcess with Port out of range",
+			request:  NewActivateServerProcess("5.0.0", "Go", 70000),
+			problems: []string{"Port must be between 1 and 65535, got 70000"},
+		},
+		{name: "valid DescribePlayerSessions", request: describePlayerSessions(func(r *DescribePlayerSessionsRequest) { r.Limit = 1024 })},
+		{
+			name:     "DescribePlayerSessions without filter",
+			request:  describePlayerSessions(func(r *DescribePlayerSessionsRequest) { r.GameSessionID = "" }),
+			problems: []string{"exactly one of GameSessionId, PlayerId or PlayerSessionId is required, got 0"},
+		},
+		{
+			name:     "DescribePlayerSessions with Limit out of range",
+			request:  describePlayerSessions(func(r *DescribePlayerSessionsRequest) { r.Limit = 1025 }),
+			problems: []string{"Limit must be between 1 and 1024, got 1025"},
+		},
+		{
+			name:     "DescribePlayerSessions with unknown status",
+			request:  describePlayerSessions(func(r *DescribePlayerSessionsRequest) { r.PlayerSessionStatusFilter = "DONE" }),
+			problems: []string{`PlayerSessionStatusFilter must be one of RESERVED, ACTIVE, COMPLETED, TIMEDOUT, got "DONE"`},
+		},
+		{name: "valid GetComputeCertificate", request: NewGetComputeCertificate()},
+		{
+			name:    "valid GetFleetRoleCredentials",
+			request: fleetRoleCredentials("arn:aws:iam::123456789012:role/test", "fleet-1-host-1"),
+		},
+		{
+			name:     "GetFleetRoleCredentials with long RoleSessionName",
+			request:  fleetRoleCredentials("arn:aws:iam::123456789012:role/test", strings.Repeat("r", 65)),
+			problems: []string{"RoleSessionName must have 2 to 64 characters, got 65"},
+		},
+		{
+			name:     "GetFleetRoleCredentials without RoleArn",
+			request:  fleetRoleCredentials("", "fleet-1-host-1"),
+			problems: []string{"RoleArn is required"},
+		},
+		{name: "valid HeartbeatServerProcess", request: NewHeartbeatServerProcess(true)},
+		{name: "valid RemovePlayerSession", request: NewRemovePlayerSession("gsess-1", "psess-1")},
+		{name: "RemovePlayerSession with 1024 characters GameSessionId", request: NewRemovePlayerSession(strings.Repeat("g", 1024), "psess-1")},
+		{
+			name:     "RemovePlayerSession with long GameSessionId",
+			request:  NewRemovePlayerSession(strings.Repeat("g", 1025), "psess-1"),
+			problems: []string{"GameSessionId must have 1 to 1024 characters, got 1025"},
+		},
+		{
+			name:     "DescribePlayerSessions with long GameSessionId",
+			request:  describePlayerSessions(func(r *DescribePlayerSessionsRequest) { r.GameSessionID = strings.Repeat("g", 1025) }),
+			problems: []string{"GameSessionId must have 0 to 1024 characters, got 1025"},
+		},
+		{name: "valid StartMatchBackfill", request: startMatchBackfill(testMatchmakingConfigurationArn, player)},
+		{
+			name:    "StartMatchBackfill with invalid ARN and no players",
+			request: startMatchBackfill("test-config"),
+			problems: []string{
+				`MatchmakingConfigurationArn must match ^arn:.*:matchmakingconfiguration/[a-zA-Z0-9-.]*$, got "test-config"`,
+				"Players is required",
+			},
+		},
+		{
+			name:     "StartMatchBackfill with invalid player",
+			request:  startMatchBackfill(testMatchmakingConfigurationArn, model.Player{LatencyInMS: map[string]int{"us-west-2": 0}}),
+			problems: []string{"Players[0].PlayerId is required", "Players[0].LatencyInMs[us-west-2] must be at least 1, got 0"},
+		},
+		{name: "valid StopMatchBackfill", request: stopMatchBackfill(testMatchmakingConfigurationArn, "ticket-1")},
+		{
+			name:     "StopMatchBackfill without TicketId and MatchmakingConfigurationArn",
+			request:  stopMatchBackfill("", ""),
+			problems: []string{"MatchmakingConfigurationArn is required", "TicketId is required"},
+		},
+		{name: "valid TerminateServerProcess", request: NewTerminateServerProcess()},
+		{name: "valid UpdatePlayerSessionCreationPolicy", request: NewUpdatePlayerSessionCreationPolicy("gsess-1", model.AcceptAll)},
+		{
+			name:     "UpdatePlayerSessionCreationPolicy with NotSet policy",
+			request:  NewUpdatePlayerSessionCreationPolicy("gsess-1", model.NotSet),
+			problems: []string{"PlayerSessionPolicy must be ACCEPT_ALL or DENY_ALL"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertProblems(t, tt.request.Validate(), tt.problems)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package authtoken
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"aws/amazon-gamelift-go-sdk/common"
+)
+
+// CommandProvider - returns the auth token printed by a command, e.g.
+//
+//	authtoken.NewCommandProvider("aws", "gamelift", "get-compute-auth-token",
+//		"--fleet-id", fleetID, "--compute-name", computeName)
+//
+// The output is either the token itself or the JSON output of GetComputeAuthToken.
+// In the latter case the token is cached until it is close to ExpirationTimestamp,
+// otherwise the command is run before every connection attempt.
+type CommandProvider struct {
+	name  string
+	args  []string
+	clock common.Clock
+
+	mtx     sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// getComputeAuthTokenOutput - the JSON output of GetComputeAuthToken.
+type getComputeAuthTokenOutput struct {
+	AuthToken           string
+	ExpirationTimestamp json.RawMessage
+}
+
+// NewCommandProvider - creates a CommandProvider of the command with the specified name and arguments.
+// The command is run without a shell.
+func NewCommandProvider(name string, args ...string) *CommandProvider {
+	return &CommandProvider{name: name, args: args, clock: common.NewRealClock()}
+}
+
+// AuthToken - returns the cached token or runs the command. The command is killed when ctx is done.
+func (p *CommandProvider) AuthToken(ctx context.Context) (string, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.token != "" && p.clock.Now().Before(p.expires.Add(-refreshWindow)) {
+		return p.token, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	//nolint:gosec // The command is configured by the game server, not by an untrusted input
+	cmd := exec.CommandContext(ctx, p.name, p.args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("auth token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	token, expires, err := parseCommandOutput(stdout.Bytes())
+	if err != nil {
+		return "", err
+	}
+	p.token, p.expires = token, expires
+	return token, nil
+}
+
+// parseCommandOutput - returns the token and its expiration time, which is zero if the output is the token itself.
+func parseCommandOutput(output []byte) (string, time.Time, error) {
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return "", time.Time{}, fmt.Errorf("auth token command printed nothing")
+	}
+	if output[0] != '{' {
+		return string(output), time.Time{}, nil
+	}
+	var res getComputeAuthTokenOutput
+	if err := json.Unmarshal(output, &res); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decode the auth token command output: %w", err)
+	}
+	if res.AuthToken == "" {
+		return "", time.Time{}, fmt.Errorf("auth token command output has no AuthToken")
+	}
+	return res.AuthToken, parseTimestamp(res.ExpirationTimestamp), nil
+}
+
+// parseTimestamp - parses an ISO 8601 string or epoch seconds, returns zero time if the timestamp is missing or invalid.
+func parseTimestamp(raw json.RawMessage) time.Time {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t
+		}
+		raw = []byte(s)
+	}
+	seconds, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package authtoken
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+)
+
+const helperProcessEnv = "GAMELIFT_SDK_TEST_HELPER_PROCESS"
+
+// TestHelperProcess - isn't a real test, it is the command run by the tests of CommandProvider.
+// It appends a line to the file of the first argument to count the runs and prints the second argument.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv(helperProcessEnv) != "1" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	runs, output := args[1], args[2]
+	file, err := os.OpenFile(runs, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err == nil {
+		_, err = file.WriteString("run\n")
+		file.Close()
+	}
+	if err != nil || output == "fail" {
+		fmt.Fprint(os.Stderr, "test failure")
+		os.Exit(1)
+	}
+	fmt.Print(output)
+	os.Exit(0)
+}
+
+// newHelperCommandProvider - returns a CommandProvider of the helper process printing output and the file of its runs.
+func newHelperCommandProvider(t *testing.T, output string) (*CommandProvider, string) {
+	t.Setenv(helperProcessEnv, "1")
+	runs := filepath.Join(t.TempDir(), "runs")
+	return NewCommandProvider(os.Args[0], "-test.run=TestHelperProcess", "--", runs, output), runs
+}
+
+func countRuns(t *testing.T, runs string) int {
+	content, err := os.ReadFile(runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(content) / len("run\n")
+}
+
+// GIVEN command printing the token WHEN the token is requested twice THEN the command is run each time
+func TestCommandProvider_PlainToken(t *testing.T) {
+	// GIVEN
+	p, runs := newHelperCommandProvider(t, "  plain-token\n")
+
+	// WHEN
+	for i := 0; i < 2; i++ {
+		token, err := p.AuthToken(context.Background())
+
+		// THEN
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "plain-token" {
+			t.Errorf("unexpected token: %q", token)
+		}
+	}
+	if n := countRuns(t, runs); n != 2 {
+		t.Errorf("unexpected number of runs: %d", n)
+	}
+}
+
+// GIVEN command printing GetComputeAuthToken output WHEN the token is requested
+// THEN the token is cached until it is close to the expiration
+func TestCommandProvider_GetComputeAuthTokenOutput(t *testing.T) {
+	now := time.Date(2024, 8, 5, 10, 0, 0, 0, time.UTC)
+	for name, expiration := range map[string]string{
+		"iso8601": `"2024-08-05T10:15:00+00:00"`,
+		"epoch":   fmt.Sprint(now.Add(15 * time.Minute).Unix()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			// GIVEN
+			p, runs := newHelperCommandProvider(t, fmt.Sprintf(
+				`{"FleetId": "fleet-1", "ComputeName": "compute-1", "AuthToken": "json-token", "ExpirationTimestamp": %s}`,
+				expiration,
+			))
+			clock := mock.NewFakeClock(now)
+			p.clock = clock
+
+			// WHEN
+			first, err := p.AuthToken(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			clock.Advance(10 * time.Minute)
+			second, err := p.AuthToken(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			cachedRuns := countRuns(t, runs)
+			clock.Advance(4 * time.Minute)
+			if _, err = p.AuthToken(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			// THEN
+			if first != "json-token" || second != "json-token" {
+				t.Errorf("unexpected tokens: %q, %q", first, second)
+			}
+			if cachedRuns != 1 {
+				t.Errorf("unexpected number of runs before the refresh window: %d", cachedRuns)
+			}
+			if n := countRuns(t, runs); n != 2 {
+				t.Errorf("unexpected number of runs: %d", n)
+			}
+		})
+	}
+}
+
+// GIVEN failing command or unexpected output WHEN the token is requested THEN an error is returned
+func TestCommandProvider_Error(t *testing.T) {
+	for name, output := range map[string]string{
+		"failure":        "fail",
+		"empty":          "\n",
+		"invalid json":   `{"AuthToken": `,
+		"json w/o token": `{"FleetId": "fleet-1"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			// GIVEN
+			p, _ := newHelperCommandProvider(t, output)
+
+			// WHEN
+			token, err := p.AuthToken(context.Background())
+
+			// THEN
+			if err == nil || token != "" {
+				t.Errorf("expected error, got %q, %v", token, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package authtoken
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileProvider - returns the auth token stored in a file, e.g. by an external agent calling GetComputeAuthToken.
+// The file is watched for changes: it is read again only if its modification time or size changed since the last read,
+// so the agent can rotate the token by rewriting the file.
+type FileProvider struct {
+	path string
+
+	mtx     sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileProvider - creates a FileProvider of the file with the specified path.
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+// AuthToken - returns the token stored in the file, without leading and trailing white space.
+// Returns an error if the file doesn't exist or is empty.
+func (p *FileProvider) AuthToken(context.Context) (string, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the auth token file: %w", err)
+	}
+	if p.token != "" && info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return p.token, nil
+	}
+	content, err := os.ReadFile(p.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the auth token file: %w", err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("the auth token file %s is empty", p.path)
+	}
+	p.token, p.modTime, p.size = token, info.ModTime(), info.Size()
+	return token, nil
+}
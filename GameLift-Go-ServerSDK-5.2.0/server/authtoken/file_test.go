@@ -0,0 +1,94 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package authtoken
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTokenFile(t *testing.T, path, content string, modTime time.Time) {
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// GIVEN token file WHEN an external agent rewrites it THEN the new token is returned
+func TestFileProvider_ReadsRotatedToken(t *testing.T) {
+	// GIVEN
+	path := filepath.Join(t.TempDir(), "auth-token")
+	modTime := time.Now().Add(-time.Hour)
+	writeTokenFile(t, path, " first-token\n", modTime)
+	p := NewFileProvider(path)
+
+	// WHEN
+	first, err := p.AuthToken(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTokenFile(t, path, "second-token\n", modTime.Add(time.Minute))
+	second, err := p.AuthToken(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN
+	if first != "first-token" || second != "second-token" {
+		t.Errorf("unexpected tokens: %q, %q", first, second)
+	}
+}
+
+// GIVEN token file read once WHEN the file is unchanged THEN the cached token is returned
+func TestFileProvider_CachesUnchangedFile(t *testing.T) {
+	// GIVEN
+	path := filepath.Join(t.TempDir(), "auth-token")
+	modTime := time.Now().Add(-time.Hour)
+	writeTokenFile(t, path, "first-token", modTime)
+	p := NewFileProvider(path)
+	if _, err := p.AuthToken(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN the content changes without changing the modification time and size
+	writeTokenFile(t, path, "other-token", modTime)
+	token, err := p.AuthToken(context.Background())
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "first-token" {
+		t.Errorf("unexpected token: %q", token)
+	}
+}
+
+// GIVEN missing or empty token file WHEN the token is requested THEN an error is returned
+func TestFileProvider_Error(t *testing.T) {
+	dir := t.TempDir()
+	emptyFile := filepath.Join(dir, "empty")
+	writeTokenFile(t, emptyFile, " \n", time.Now())
+
+	for name, path := range map[string]string{
+		"missing": filepath.Join(dir, "missing"),
+		"empty":   emptyFile,
+	} {
+		t.Run(name, func(t *testing.T) {
+			// WHEN
+			token, err := NewFileProvider(path).AuthToken(context.Background())
+
+			// THEN
+			if err == nil || token != "" {
+				t.Errorf("expected error, got %q, %v", token, err)
+			}
+		})
+	}
+}
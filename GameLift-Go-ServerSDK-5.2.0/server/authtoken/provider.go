@@ -0,0 +1,38 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package authtoken provides the sources of the auth token used to connect Anywhere computes to GameLift,
+// see server.ServerParameters.AuthTokenProvider. Auth tokens returned by GetComputeAuthToken are short-lived,
+// so the provider is called before every connection attempt, including reconnects.
+//
+//	params := server.ServerParameters{
+//		WebSocketURL:      "wss://us-west-2.api.amazongamelift.com",
+//		ProcessID:         processID,
+//		HostID:            hostID,
+//		FleetID:           fleetID,
+//		AuthTokenProvider: authtoken.NewFileProvider("/run/gamelift/auth-token"),
+//	}
+package authtoken
+
+import (
+	"context"
+	"time"
+)
+
+// refreshWindow - how long before the expiration a token is requested again.
+const refreshWindow = time.Minute
+
+// Provider - the source of the auth token used to connect to GameLift.
+type Provider interface {
+	AuthToken(ctx context.Context) (string, error)
+}
+
+// ProviderFunc - adapts a function to the Provider interface.
+type ProviderFunc func(ctx context.Context) (string, error)
+
+// AuthToken - calls f(ctx).
+func (f ProviderFunc) AuthToken(ctx context.Context) (string, error) {
+	return f(ctx)
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"runtime/debug"
+
+	"aws/amazon-gamelift-go-sdk/server/metrics"
+)
+
+// invokeCallback - calls a user callback, and recovers it if it panics, so a bug in the game server code
+// doesn't take the whole process down. Returns true if the callback panicked.
+func (state *gameLiftServerState) invokeCallback(name string, callback func()) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			state.onCallbackPanic(name, r, debug.Stack())
+		}
+	}()
+	callback()
+	return false
+}
+
+// onCallbackPanic - logs and counts the panic, marks the process unhealthy until the next health check
+// and calls OnCallbackPanic.
+func (state *gameLiftServerState) onCallbackPanic(name string, recovered any, stack []byte) {
+	state.callbackPanicked.Store(true)
+	state.getMetrics().IncrCounter(metrics.CallbackPanics, 1)
+	lg.Errorf("Recovered from a panic in %s, the process is reported unhealthy on the next health check: %v\n%s",
+		name, recovered, stack)
+	if state.parameters == nil || state.parameters.OnCallbackPanic == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			lg.Errorf("Recovered from a panic in OnCallbackPanic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	state.parameters.OnCallbackPanic(name, recovered, stack)
+}
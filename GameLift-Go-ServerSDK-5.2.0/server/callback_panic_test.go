@@ -0,0 +1,106 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model"
+	"aws/amazon-gamelift-go-sdk/model/request"
+	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+	"aws/amazon-gamelift-go-sdk/server/metrics"
+)
+
+// newCallbackPanicTestState - returns an active server state with the logger and the metrics replaced with mocks.
+func newCallbackPanicTestState(
+	t *testing.T,
+	params *ProcessParameters,
+) (*gameLiftServerState, *mock.MockIGameLiftManager, *mock.MockILogger, *mock.MockIMetrics) {
+	ctrl := gomock.NewController(t)
+	manager := mock.NewMockIGameLiftManager(ctrl)
+	logger := mock.NewTestLogger(t, ctrl)
+	metricsMock := mock.NewMockIMetrics(ctrl)
+	metricsMock.EXPECT().Observe(metrics.RequestAttempts, gomock.Any()).AnyTimes()
+	previousLogger := lg
+	lg = logger
+	t.Cleanup(func() { lg = previousLogger })
+
+	state := &gameLiftServerState{
+		wsGameLift:         manager,
+		parameters:         params,
+		healthCheckTimeout: common.HealthcheckTimeoutDefault,
+		serviceCallTimeout: common.ServiceCallTimeoutDefault,
+		clock:              mock.NewFakeClock(time.Now()),
+		metrics:            metricsMock,
+	}
+	state.isReadyProcess.Store(true)
+	return state, manager, logger, metricsMock
+}
+
+// GIVEN OnStartGameSession panicking WHEN a game session starts
+// THEN the panic is recovered, logged, counted, passed to OnCallbackPanic and the next heartbeat is unhealthy
+func TestGameLiftServerState_CallbackPanic(t *testing.T) {
+	// GIVEN
+	var panickedCallback string
+	var recovered any
+	var stack []byte
+	state, manager, logger, metricsMock := newCallbackPanicTestState(t, &ProcessParameters{
+		OnStartGameSession: func(model.GameSession) { panic("test panic") },
+		OnHealthCheck:      func() bool { return true },
+		OnCallbackPanic: func(callback string, r any, s []byte) {
+			panickedCallback, recovered, stack = callback, r, s
+		},
+	})
+
+	// EXPECT
+	metricsMock.EXPECT().IncrCounter(metrics.CallbackPanics, int64(1))
+	logger.EXPECT().Errorf(gomock.Any(), "OnStartGameSession", "test panic", gomock.Any())
+	gomock.InOrder(
+		manager.EXPECT().
+			HandleRequest(ignoreRequestID(request.NewHeartbeatServerProcess(false)), gomock.Any(), common.ServiceCallTimeoutDefault),
+		manager.EXPECT().
+			HandleRequest(ignoreRequestID(request.NewHeartbeatServerProcess(true)), gomock.Any(), common.ServiceCallTimeoutDefault),
+	)
+
+	// WHEN
+	state.OnStartGameSession(&model.GameSession{GameSessionID: "test-game-session-id"})
+	state.heartbeatServerProcess(nil, time.Time{})
+	state.heartbeatServerProcess(nil, time.Time{})
+
+	// THEN
+	assertEqual(t, "OnStartGameSession", panickedCallback)
+	assertEqual(t, "test panic", recovered)
+	if !strings.Contains(string(stack), "TestGameLiftServerState_CallbackPanic") {
+		t.Fatalf("the stack trace does not contain the test function:\n%s", stack)
+	}
+}
+
+// GIVEN OnHealthCheck and OnCallbackPanic panicking WHEN the health is reported THEN the process is reported unhealthy
+func TestGameLiftServerState_HealthCheckPanic(t *testing.T) {
+	// GIVEN
+	state, manager, logger, metricsMock := newCallbackPanicTestState(t, &ProcessParameters{
+		OnHealthCheck:   func() bool { panic("test panic") },
+		OnCallbackPanic: func(string, any, []byte) { panic("test hook panic") },
+	})
+
+	// EXPECT
+	metricsMock.EXPECT().IncrCounter(metrics.CallbackPanics, int64(1))
+	logger.EXPECT().Errorf(gomock.Any(), "OnHealthCheck", "test panic", gomock.Any())
+	logger.EXPECT().Errorf("Recovered from a panic in OnCallbackPanic: %v\n%s", "test hook panic", gomock.Any())
+	manager.EXPECT().
+		HandleRequest(ignoreRequestID(request.NewHeartbeatServerProcess(false)), gomock.Any(), common.ServiceCallTimeoutDefault)
+
+	// WHEN
+	state.heartbeatServerProcess(nil, time.Time{})
+
+	// THEN
+	assertEqual(t, false, state.callbackPanicked.Load())
+}
@@ -0,0 +1,394 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model/message"
+)
+
+// Config - tuning of the SDK: timeouts, buffers, retries and health checks.
+//
+// InitSDK starts from ServerParameters.Config, or DefaultConfig if it is nil, overridden by the
+// EnableCompression, PlayerSessionCache and RequestTimeouts fields of ServerParameters if they are set,
+// then applies the file set in the GAMELIFT_SDK_CONFIG_FILE environment variable and
+// the environment variables of the fields, e.g. SERVICE_CALL_TIMEOUT, and validates the result.
+// Start from DefaultConfig when the config is set in code, zero values are not replaced with the defaults.
+//
+//   - ServiceCallTimeout - default timeout of the requests to GameLift, see ServerParameters.RequestTimeouts.
+//   - ServiceBufferSize - size in bytes of the read and write buffers of the websocket connection.
+//   - RetryInterval, MaxRetry, RetryFactor - retries of failed websocket writes, the attempt n waits
+//     n * RetryFactor * RetryInterval before the next one.
+//   - HealthcheckInterval - interval between the health checks reported to GameLift.
+//   - HealthcheckTimeout - time to wait for OnHealthCheck before the process is reported unhealthy,
+//     must be less than HealthcheckInterval.
+//   - HealthcheckMaxJitter - max random deviation of the health check interval, must be less than HealthcheckInterval.
+//   - WebsocketPingInterval, WebsocketPongTimeout, WebsocketReadLimit, WebsocketLargeMessageSize - keepalive and
+//     message size limits of the websocket connection. Zero WebsocketPingInterval disables pings,
+//     zero WebsocketReadLimit means no limit and zero WebsocketLargeMessageSize disables logging of large messages.
+//   - WebsocketCompression - negotiate permessage-deflate compression of websocket messages with GameLift.
+//     The environment variable is GAMELIFT_SDK_WEBSOCKET_COMPRESSION.
+//   - PlayerSessionCacheTTL, PlayerSessionCacheMaxEntries - the cache of DescribePlayerSessions lookups,
+//     see PlayerSessionCacheParameters. Zero PlayerSessionCacheTTL disables the cache.
+//   - RequestTimeouts - timeouts of the requests by action, see ServerParameters.RequestTimeouts.
+//     They are set as "StartMatchBackfill=1m,HeartbeatServerProcess=5s" in the environment variable,
+//     and as such a string or a map in the config file. Both keep the timeouts of the other actions.
+//   - RequestRetry - default retry policy of the idempotent requests, see ServerParameters.RetryPolicies.
+//   - DispatcherWorkers - max number of unordered inbound messages, such as unknown messages, handled at the same time.
+//     Responses to requests are not limited, they are passed to the requests as soon as they are read.
+//   - DispatcherQueueSize - number of lifecycle messages of one game session, such as UpdateGameSession,
+//     waiting for the previous ones above which the queue is reported with the DispatcherBlocked metric.
+//     The messages of a game session are handled one by one in the order they were received.
+//     The SDK keeps reading from GameLift when a limit is reached, the messages wait in memory.
+type Config struct {
+	ServiceCallTimeout time.Duration
+	ServiceBufferSize  int
+
+	RetryInterval time.Duration
+	MaxRetry      int
+	RetryFactor   int
+
+	HealthcheckInterval  time.Duration
+	HealthcheckTimeout   time.Duration
+	HealthcheckMaxJitter time.Duration
+
+	WebsocketPingInterval     time.Duration
+	WebsocketPongTimeout      time.Duration
+	WebsocketReadLimit        int64
+	WebsocketLargeMessageSize int
+	WebsocketCompression      bool
+
+	PlayerSessionCacheTTL        time.Duration
+	PlayerSessionCacheMaxEntries int
+
+	RequestTimeouts map[message.MessageAction]time.Duration
+	RequestRetry    RetryPolicy
+
+	DispatcherWorkers   int
+	DispatcherQueueSize int
+}
+
+// configField - a field of Config with its key in config files and its environment variable.
+type configField struct {
+	key   string
+	env   string
+	value any
+}
+
+// DefaultConfig - returns the config used if nothing is set in code, in a file or in the environment.
+func DefaultConfig() Config {
+	return Config{
+		ServiceCallTimeout:           common.ServiceCallTimeoutDefault,
+		ServiceBufferSize:            common.ServiceBufferSizeDefault,
+		RetryInterval:                common.RetryIntervalDefault,
+		MaxRetry:                     common.MaxRetryDefault,
+		RetryFactor:                  common.RetryFactorDefault,
+		HealthcheckInterval:          common.HealthcheckIntervalDefault,
+		HealthcheckTimeout:           common.HealthcheckTimeoutDefault,
+		HealthcheckMaxJitter:         common.HealthcheckMaxJitterDefault,
+		WebsocketPingInterval:        common.WebsocketPingIntervalDefault,
+		WebsocketPongTimeout:         common.WebsocketPongTimeoutDefault,
+		WebsocketReadLimit:           common.WebsocketReadLimitDefault,
+		WebsocketLargeMessageSize:    common.WebsocketLargeMessageSizeDefault,
+		PlayerSessionCacheMaxEntries: common.PlayerSessionCacheMaxEntriesDefault,
+		RequestRetry: RetryPolicy{
+			MaxAttempts: common.RequestRetryMaxAttemptsDefault,
+			BaseDelay:   common.RequestRetryBaseDelayDefault,
+			MaxDelay:    common.RequestRetryMaxDelayDefault,
+		},
+		DispatcherWorkers:   common.DispatcherWorkersDefault,
+		DispatcherQueueSize: common.DispatcherQueueSizeDefault,
+	}
+}
+
+// ConfigFromEnvironment - returns DefaultConfig overridden by the environment variables.
+// Returns a common.BadRequestException error if a variable can't be parsed.
+func ConfigFromEnvironment() (Config, error) {
+	cfg := DefaultConfig()
+	if err := cfg.applyEnvironment(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// LoadConfigFile - returns DefaultConfig overridden by a JSON (.json) or YAML (.yaml, .yml) file.
+// The keys are the field names in lower camel case and durations are strings such as "300ms" or "1m30s":
+//
+//	serviceCallTimeout: 30s
+//	healthcheckInterval: 1m
+//	requestRetryMaxAttempts: 5
+//
+// Returns a common.BadRequestException error if the file can't be read or has an unknown key or an invalid value.
+func LoadConfigFile(path string) (Config, error) {
+	cfg := DefaultConfig()
+	if err := cfg.applyFile(path); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// newConfig - returns the config of InitSDK: params.Config or DefaultConfig, overridden by
+// the GAMELIFT_SDK_CONFIG_FILE file and the environment variables.
+func newConfig(params *ServerParameters) (Config, error) {
+	cfg := DefaultConfig()
+	if params.Config != nil {
+		cfg = *params.Config
+	}
+	cfg.applyParameters(params)
+	if path := common.GetEnvStringOrDefault(common.EnvironmentKeyConfigFile, ""); path != "" {
+		if err := cfg.applyFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := cfg.applyEnvironment(); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) fields() []configField {
+	return []configField{
+		{"serviceCallTimeout", common.ServiceCallTimeout, &c.ServiceCallTimeout},
+		{"serviceBufferSize", common.ServiceBufferSize, &c.ServiceBufferSize},
+		{"retryInterval", common.RetryInterval, &c.RetryInterval},
+		{"maxRetry", common.MaxRetry, &c.MaxRetry},
+		{"retryFactor", common.RetryFactor, &c.RetryFactor},
+		{"healthcheckInterval", common.HealthcheckInterval, &c.HealthcheckInterval},
+		{"healthcheckTimeout", common.HealthcheckTimeout, &c.HealthcheckTimeout},
+		{"healthcheckMaxJitter", common.HealthcheckMaxJitter, &c.HealthcheckMaxJitter},
+		{"websocketPingInterval", common.WebsocketPingInterval, &c.WebsocketPingInterval},
+		{"websocketPongTimeout", common.WebsocketPongTimeout, &c.WebsocketPongTimeout},
+		{"websocketReadLimit", common.WebsocketReadLimit, &c.WebsocketReadLimit},
+		{"websocketLargeMessageSize", common.WebsocketLargeMessageSize, &c.WebsocketLargeMessageSize},
+		{"websocketCompression", common.EnvironmentKeyWebsocketCompression, &c.WebsocketCompression},
+		{"playerSessionCacheTTL", common.PlayerSessionCacheTTL, &c.PlayerSessionCacheTTL},
+		{"playerSessionCacheMaxEntries", common.PlayerSessionCacheMaxEntries, &c.PlayerSessionCacheMaxEntries},
+		{"requestTimeouts", common.RequestTimeouts, &c.RequestTimeouts},
+		{"requestRetryMaxAttempts", common.RequestRetryMaxAttempts, &c.RequestRetry.MaxAttempts},
+		{"requestRetryBaseDelay", common.RequestRetryBaseDelay, &c.RequestRetry.BaseDelay},
+		{"requestRetryMaxDelay", common.RequestRetryMaxDelay, &c.RequestRetry.MaxDelay},
+		{"dispatcherWorkers", common.DispatcherWorkers, &c.DispatcherWorkers},
+		{"dispatcherQueueSize", common.DispatcherQueueSize, &c.DispatcherQueueSize},
+	}
+}
+
+// applyParameters - overrides the fields with the settings of ServerParameters which are set.
+func (c *Config) applyParameters(params *ServerParameters) {
+	if params.EnableCompression {
+		c.WebsocketCompression = true
+	}
+	if params.PlayerSessionCache.TTL != 0 {
+		c.PlayerSessionCacheTTL = params.PlayerSessionCache.TTL
+	}
+	if params.PlayerSessionCache.MaxEntries != 0 {
+		c.PlayerSessionCacheMaxEntries = params.PlayerSessionCache.MaxEntries
+	}
+	if len(params.RequestTimeouts) > 0 {
+		c.RequestTimeouts = mergeRequestTimeouts(c.RequestTimeouts, params.RequestTimeouts)
+	}
+}
+
+// applyEnvironment - overrides the fields whose environment variables are set.
+func (c *Config) applyEnvironment() error {
+	for _, field := range c.fields() {
+		value, ok := os.LookupEnv(field.env)
+		if !ok {
+			continue
+		}
+		if err := setConfigValue(field.value, value); err != nil {
+			return common.NewGameLiftErrorWithCause(common.BadRequestException, "",
+				fmt.Sprintf("invalid value %q of %s: %s", value, field.env, err), err)
+		}
+	}
+	return nil
+}
+
+// applyFile - overrides the fields set in a JSON or YAML file.
+func (c *Config) applyFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return common.NewGameLiftErrorWithCause(common.BadRequestException, "",
+			fmt.Sprintf("failed to read the config file %s: %s", path, err), err)
+	}
+	values := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		// Keeps the integers as written instead of converting them to float64
+		decoder.UseNumber()
+		err = decoder.Decode(&values)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	default:
+		return common.NewGameLiftError(common.BadRequestException, "",
+			fmt.Sprintf("unsupported extension %q of the config file %s, expected .json, .yaml or .yml", ext, path))
+	}
+	if err != nil {
+		return common.NewGameLiftErrorWithCause(common.BadRequestException, "",
+			fmt.Sprintf("failed to parse the config file %s: %s", path, err), err)
+	}
+
+	fields := make(map[string]configField)
+	for _, field := range c.fields() {
+		fields[field.key] = field
+	}
+	for key, value := range values {
+		field, ok := fields[key]
+		if !ok {
+			return common.NewGameLiftError(common.BadRequestException, "",
+				fmt.Sprintf("unknown key %q in the config file %s", key, path))
+		}
+		text := configFileValue(value)
+		if err := setConfigValue(field.value, text); err != nil {
+			return common.NewGameLiftErrorWithCause(common.BadRequestException, "",
+				fmt.Sprintf("invalid value %q of %s in the config file %s: %s", text, key, path, err), err)
+		}
+	}
+	return nil
+}
+
+// configFileValue - returns a value of a config file as it is written in an environment variable,
+// maps such as the request timeouts are written as "key=value,key=value".
+func configFileValue(value any) string {
+	values, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Sprint(value)
+	}
+	entries := make([]string, 0, len(values))
+	for key, v := range values {
+		entries = append(entries, fmt.Sprintf("%s=%v", key, v))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// setConfigValue - parses value into the field pointed by dst.
+func setConfigValue(dst any, value string) error {
+	value = strings.TrimSpace(value)
+	switch v := dst.(type) {
+	case *time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*v = d
+	case *int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*v = n
+	case *int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		*v = n
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*v = b
+	case *map[message.MessageAction]time.Duration:
+		timeouts, err := parseRequestTimeouts(value)
+		if err != nil {
+			return err
+		}
+		*v = mergeRequestTimeouts(*v, timeouts)
+	default:
+		return fmt.Errorf("unsupported field type %T", dst)
+	}
+	return nil
+}
+
+// Validate - returns a common.BadRequestException error listing all invalid fields, or nil.
+func (c Config) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+	check(c.ServiceCallTimeout > 0, "ServiceCallTimeout must be positive, got %s", c.ServiceCallTimeout)
+	check(c.ServiceBufferSize > 0, "ServiceBufferSize must be positive, got %d", c.ServiceBufferSize)
+	check(c.RetryInterval > 0, "RetryInterval must be positive, got %s", c.RetryInterval)
+	check(c.MaxRetry > 0, "MaxRetry must be positive, got %d", c.MaxRetry)
+	check(c.RetryFactor > 0, "RetryFactor must be positive, got %d", c.RetryFactor)
+	check(c.HealthcheckInterval > 0, "HealthcheckInterval must be positive, got %s", c.HealthcheckInterval)
+	check(c.HealthcheckTimeout > 0 && c.HealthcheckTimeout < c.HealthcheckInterval,
+		"HealthcheckTimeout must be positive and less than HealthcheckInterval %s, got %s",
+		c.HealthcheckInterval, c.HealthcheckTimeout)
+	check(c.HealthcheckMaxJitter >= 0 && c.HealthcheckMaxJitter < c.HealthcheckInterval,
+		"HealthcheckMaxJitter must be non-negative and less than HealthcheckInterval %s, got %s",
+		c.HealthcheckInterval, c.HealthcheckMaxJitter)
+	check(c.WebsocketPingInterval >= 0, "WebsocketPingInterval must be non-negative, got %s", c.WebsocketPingInterval)
+	check(c.WebsocketPingInterval == 0 || c.WebsocketPongTimeout > 0,
+		"WebsocketPongTimeout must be positive if pings are enabled, got %s", c.WebsocketPongTimeout)
+	check(c.WebsocketReadLimit >= 0, "WebsocketReadLimit must be non-negative, got %d", c.WebsocketReadLimit)
+	check(c.WebsocketLargeMessageSize >= 0,
+		"WebsocketLargeMessageSize must be non-negative, got %d", c.WebsocketLargeMessageSize)
+	check(c.PlayerSessionCacheTTL >= 0, "PlayerSessionCacheTTL must be non-negative, got %s", c.PlayerSessionCacheTTL)
+	check(c.PlayerSessionCacheMaxEntries > 0,
+		"PlayerSessionCacheMaxEntries must be positive, got %d", c.PlayerSessionCacheMaxEntries)
+	for _, action := range sortedActions(c.RequestTimeouts) {
+		timeout := c.RequestTimeouts[action]
+		check(requestResponseActions[action], "RequestTimeouts: %s has no response, its timeout can't be set", action)
+		check(timeout > 0, "RequestTimeouts: the timeout of %s must be positive, got %s", action, timeout)
+	}
+	check(c.RequestRetry.MaxAttempts > 0, "RequestRetry.MaxAttempts must be positive, got %d", c.RequestRetry.MaxAttempts)
+	check(c.RequestRetry.BaseDelay > 0, "RequestRetry.BaseDelay must be positive, got %s", c.RequestRetry.BaseDelay)
+	check(c.RequestRetry.MaxDelay >= c.RequestRetry.BaseDelay,
+		"RequestRetry.MaxDelay must not be less than RequestRetry.BaseDelay %s, got %s",
+		c.RequestRetry.BaseDelay, c.RequestRetry.MaxDelay)
+	check(c.DispatcherWorkers > 0, "DispatcherWorkers must be positive, got %d", c.DispatcherWorkers)
+	check(c.DispatcherQueueSize > 0, "DispatcherQueueSize must be positive, got %d", c.DispatcherQueueSize)
+	if len(problems) > 0 {
+		return common.NewGameLiftError(common.BadRequestException, "",
+			"invalid SDK config: "+strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Describe - returns all fields with their environment variables on one line, for logging at startup, e.g.
+//
+//	serviceCallTimeout=20s (SERVICE_CALL_TIMEOUT), serviceBufferSize=2048 (SERVICE_BUFFER_SIZE), ...
+func (c Config) Describe() string {
+	fields := c.fields()
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		var value any
+		switch v := field.value.(type) {
+		case *time.Duration:
+			value = *v
+		case *int:
+			value = *v
+		case *int64:
+			value = *v
+		case *bool:
+			value = *v
+		case *map[message.MessageAction]time.Duration:
+			value = formatRequestTimeouts(*v)
+		}
+		parts = append(parts, fmt.Sprintf("%s=%v (%s)", field.key, value, field.env))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,357 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model/message"
+)
+
+// writeConfigFile - writes a config file to a temporary directory and returns its path.
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultConfig_IsValid(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigFromEnvironment(t *testing.T) {
+	// GIVEN
+	t.Setenv(common.ServiceCallTimeout, "30s")
+	t.Setenv(common.ServiceBufferSize, "4096")
+	t.Setenv(common.WebsocketReadLimit, "65536")
+	t.Setenv(common.RequestRetryBaseDelay, "250ms")
+	t.Setenv(common.EnvironmentKeyWebsocketCompression, "true")
+	t.Setenv(common.PlayerSessionCacheTTL, "5s")
+	t.Setenv(common.RequestTimeouts, " HeartbeatServerProcess=5s, GetComputeCertificate=30s")
+
+	// WHEN
+	cfg, err := ConfigFromEnvironment()
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := DefaultConfig()
+	expected.ServiceCallTimeout = 30 * time.Second
+	expected.ServiceBufferSize = 4096
+	expected.WebsocketReadLimit = 65536
+	expected.RequestRetry.BaseDelay = 250 * time.Millisecond
+	expected.WebsocketCompression = true
+	expected.PlayerSessionCacheTTL = 5 * time.Second
+	expected.RequestTimeouts = map[message.MessageAction]time.Duration{
+		message.HeartbeatServerProcess: 5 * time.Second,
+		message.GetComputeCertificate:  30 * time.Second,
+	}
+	if !reflect.DeepEqual(expected, cfg) {
+		t.Fatalf("Expected %v but got %v", expected, cfg)
+	}
+}
+
+// GIVEN an unparsable environment variable WHEN ConfigFromEnvironment THEN an error is returned instead of the default
+func TestConfigFromEnvironment_Invalid(t *testing.T) {
+	for env, value := range map[string]string{
+		common.HealthcheckInterval:                "60",
+		common.EnvironmentKeyWebsocketCompression: "yes please",
+		common.PlayerSessionCacheMaxEntries:       "many",
+		common.RequestTimeouts:                    "StartMatchBackfill=1 minute",
+	} {
+		t.Run(env, func(t *testing.T) {
+			// GIVEN
+			t.Setenv(env, value)
+
+			// WHEN
+			_, err := ConfigFromEnvironment()
+
+			// THEN
+			if !errors.Is(err, common.ErrBadRequestException) || !strings.Contains(err.Error(), env) {
+				t.Fatalf("expected BadRequestException about %s, got %v", env, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	expected := DefaultConfig()
+	expected.ServiceCallTimeout = 30 * time.Second
+	expected.HealthcheckInterval = 2 * time.Minute
+	expected.WebsocketLargeMessageSize = 1048576
+	expected.RequestRetry.MaxAttempts = 5
+	expected.WebsocketCompression = true
+	expected.RequestTimeouts = map[message.MessageAction]time.Duration{
+		message.StartMatchBackfill:     time.Minute,
+		message.HeartbeatServerProcess: 5 * time.Second,
+	}
+
+	for name, content := range map[string]string{
+		"config.json": `{
+			"serviceCallTimeout": "30s",
+			"healthcheckInterval": "2m",
+			"websocketLargeMessageSize": 1048576,
+			"websocketCompression": true,
+			"requestRetryMaxAttempts": 5,
+			"requestTimeouts": {"StartMatchBackfill": "1m", "HeartbeatServerProcess": "5s"}
+		}`,
+		"config.yaml": `
+serviceCallTimeout: 30s
+healthcheckInterval: 2m
+websocketLargeMessageSize: 1048576
+websocketCompression: true
+requestRetryMaxAttempts: 5
+requestTimeouts: StartMatchBackfill=1m,HeartbeatServerProcess=5s
+`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			// WHEN
+			cfg, err := LoadConfigFile(writeConfigFile(t, name, content))
+
+			// THEN
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(expected, cfg) {
+				t.Fatalf("Expected %v but got %v", expected, cfg)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "unknown key", file: "config.yaml", content: "serviceCallTimeot: 30s"},
+		{name: "invalid duration", file: "config.json", content: `{"retryInterval": 2}`},
+		{name: "invalid int", file: "config.yml", content: "maxRetry: five"},
+		{name: "invalid syntax", file: "config.json", content: `{"maxRetry": 5`},
+		{name: "unsupported extension", file: "config.toml", content: "maxRetry = 5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			_, err := LoadConfigFile(writeConfigFile(t, tt.file, tt.content))
+
+			// THEN
+			if !errors.Is(err, common.ErrBadRequestException) {
+				t.Fatalf("expected BadRequestException, got %v", err)
+			}
+		})
+	}
+
+	// GIVEN a missing file WHEN LoadConfigFile THEN an error is returned
+	if _, err := LoadConfigFile(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, common.ErrBadRequestException) {
+		t.Fatalf("expected BadRequestException, got %v", err)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(cfg *Config)
+		field  string
+	}{
+		{name: "zero service call timeout", update: func(cfg *Config) { cfg.ServiceCallTimeout = 0 }, field: "ServiceCallTimeout"},
+		{name: "zero max retry", update: func(cfg *Config) { cfg.MaxRetry = 0 }, field: "MaxRetry"},
+		{
+			name:   "health check timeout not less than interval",
+			update: func(cfg *Config) { cfg.HealthcheckTimeout = cfg.HealthcheckInterval },
+			field:  "HealthcheckTimeout",
+		},
+		{
+			name:   "jitter not less than interval",
+			update: func(cfg *Config) { cfg.HealthcheckMaxJitter = cfg.HealthcheckInterval + time.Second },
+			field:  "HealthcheckMaxJitter",
+		},
+		{
+			name:   "pings without pong timeout",
+			update: func(cfg *Config) { cfg.WebsocketPongTimeout = 0 },
+			field:  "WebsocketPongTimeout",
+		},
+		{
+			name:   "negative player session cache TTL",
+			update: func(cfg *Config) { cfg.PlayerSessionCacheTTL = -time.Second },
+			field:  "PlayerSessionCacheTTL",
+		},
+		{
+			name:   "zero player session cache entries",
+			update: func(cfg *Config) { cfg.PlayerSessionCacheMaxEntries = 0 },
+			field:  "PlayerSessionCacheMaxEntries",
+		},
+		{
+			name: "timeout of action without response",
+			update: func(cfg *Config) {
+				cfg.RequestTimeouts = map[message.MessageAction]time.Duration{message.AcceptPlayerSession: time.Second}
+			},
+			field: "RequestTimeouts",
+		},
+		{
+			name: "timeout of unknown action",
+			update: func(cfg *Config) {
+				cfg.RequestTimeouts = map[message.MessageAction]time.Duration{"Unknown": time.Second}
+			},
+			field: "RequestTimeouts",
+		},
+		{
+			name: "zero timeout",
+			update: func(cfg *Config) {
+				cfg.RequestTimeouts = map[message.MessageAction]time.Duration{message.StartMatchBackfill: 0}
+			},
+			field: "RequestTimeouts",
+		},
+		{
+			name:   "max delay less than base delay",
+			update: func(cfg *Config) { cfg.RequestRetry.MaxDelay = cfg.RequestRetry.BaseDelay / 2 },
+			field:  "RequestRetry.MaxDelay",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			cfg := DefaultConfig()
+			tt.update(&cfg)
+
+			// WHEN
+			err := cfg.Validate()
+
+			// THEN
+			if !errors.Is(err, common.ErrBadRequestException) || !strings.Contains(err.Error(), tt.field) {
+				t.Fatalf("expected BadRequestException about %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+// GIVEN pings disabled and no jitter WHEN Validate THEN the config is valid
+func TestConfig_ValidateAllowsDisabledFeatures(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.WebsocketPingInterval = 0
+	cfg.WebsocketPongTimeout = 0
+	cfg.HealthcheckMaxJitter = 0
+	cfg.WebsocketLargeMessageSize = 0
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfig_Describe(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.RequestTimeouts = map[message.MessageAction]time.Duration{
+		message.StartMatchBackfill:     time.Minute,
+		message.HeartbeatServerProcess: 5 * time.Second,
+	}
+	description := cfg.Describe()
+
+	for _, expected := range []string{
+		"serviceCallTimeout=20s (SERVICE_CALL_TIMEOUT)",
+		"healthcheckMaxJitter=10s (HEALTHCHECK_MAX_JITTER)",
+		"websocketReadLimit=0 (WEBSOCKET_READ_LIMIT)",
+		"requestRetryMaxAttempts=3 (REQUEST_RETRY_MAX_ATTEMPTS)",
+		"websocketCompression=false (GAMELIFT_SDK_WEBSOCKET_COMPRESSION)",
+		"playerSessionCacheMaxEntries=1024 (PLAYER_SESSION_CACHE_MAX_ENTRIES)",
+		"requestTimeouts=HeartbeatServerProcess=5s,StartMatchBackfill=1m0s (REQUEST_TIMEOUTS)",
+	} {
+		if !strings.Contains(description, expected) {
+			t.Fatalf("%q does not contain %q", description, expected)
+		}
+	}
+}
+
+// GIVEN a config in code, a config file and an environment variable WHEN newConfig
+// THEN the file overrides the code and the environment overrides the file
+func TestNewConfig_Precedence(t *testing.T) {
+	// GIVEN
+	code := DefaultConfig()
+	code.MaxRetry = 7
+	code.RetryFactor = 3
+	code.RetryInterval = time.Second
+	t.Setenv(common.EnvironmentKeyConfigFile, writeConfigFile(t, "config.yaml", "retryFactor: 4\nretryInterval: 3s\n"))
+	t.Setenv(common.RetryInterval, "5s")
+
+	// WHEN
+	cfg, err := newConfig(&ServerParameters{Config: &code})
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, 7, cfg.MaxRetry)
+	assertEqual(t, 4, cfg.RetryFactor)
+	assertEqual(t, 5*time.Second, cfg.RetryInterval)
+}
+
+// GIVEN an environment variable making the config invalid WHEN InitSDK THEN BadRequestException is returned
+// GIVEN settings of ServerParameters WHEN the config is resolved
+// THEN they override ServerParameters.Config and the environment overrides them
+func TestNewConfig_ServerParameters(t *testing.T) {
+	// GIVEN
+	code := DefaultConfig()
+	code.RequestTimeouts = map[message.MessageAction]time.Duration{message.DescribePlayerSessions: time.Minute}
+	params := ServerParameters{
+		Config:             &code,
+		EnableCompression:  true,
+		PlayerSessionCache: PlayerSessionCacheParameters{TTL: 5 * time.Second, MaxEntries: 10},
+		RequestTimeouts: map[message.MessageAction]time.Duration{
+			message.StartMatchBackfill:    time.Minute,
+			message.GetComputeCertificate: 10 * time.Second,
+		},
+	}
+	t.Setenv(common.PlayerSessionCacheTTL, "30s")
+	t.Setenv(common.RequestTimeouts, "GetComputeCertificate=30s")
+
+	// WHEN
+	cfg, err := newConfig(&params)
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, true, cfg.WebsocketCompression)
+	assertEqual(t, 30*time.Second, cfg.PlayerSessionCacheTTL)
+	assertEqual(t, 10, cfg.PlayerSessionCacheMaxEntries)
+	expectedTimeouts := map[message.MessageAction]time.Duration{
+		message.DescribePlayerSessions: time.Minute,
+		message.StartMatchBackfill:     time.Minute,
+		message.GetComputeCertificate:  30 * time.Second,
+	}
+	if !reflect.DeepEqual(expectedTimeouts, cfg.RequestTimeouts) {
+		t.Fatalf("Expected %v but got %v", expectedTimeouts, cfg.RequestTimeouts)
+	}
+	assertEqual(t, 1, len(code.RequestTimeouts))
+}
+
+func TestInitSDK_InvalidConfig(t *testing.T) {
+	// GIVEN
+	t.Setenv(common.HealthcheckTimeout, "2m")
+
+	// WHEN
+	err := InitSDK(testServerParams)
+
+	// THEN
+	if !errors.Is(err, common.ErrBadRequestException) {
+		t.Fatalf("expected BadRequestException, got %v", err)
+	}
+	if srv != nil {
+		t.Fatal("the SDK is initialized with an invalid config")
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"time"
+
+	"aws/amazon-gamelift-go-sdk/server/internal/security"
+)
+
+const containerCredentialsRelativeURI = "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"
+
+// NewContainerProvider - creates a Provider of the ECS container credentials.
+// The credentials are requested from the container credentials endpoint with AWS_CONTAINER_CREDENTIALS_RELATIVE_URI
+// and cached until they are close to expiration.
+func NewContainerProvider() Provider {
+	return newContainerProvider(&http.Client{Timeout: 5 * time.Second})
+}
+
+// HTTPGetter - the HTTP client of the container credentials endpoint, such as an *http.Client.
+type HTTPGetter interface {
+	Get(url string) (*http.Response, error)
+}
+
+// NewContainerProviderWithClient - creates a Provider of the ECS container credentials requested with httpClient,
+// e.g. an *http.Client with custom timeouts. A nil httpClient, including a nil *http.Client,
+// is replaced with the client of NewContainerProvider.
+// If httpClient has a Do method, as *http.Client does, the request is sent with the context of Retrieve.
+// Otherwise Retrieve returns when the context is done without waiting for the request.
+func NewContainerProviderWithClient(httpClient HTTPGetter) Provider {
+	if httpClient == nil {
+		return NewContainerProvider()
+	}
+	if v := reflect.ValueOf(httpClient); v.Kind() == reflect.Ptr && v.IsNil() {
+		return NewContainerProvider()
+	}
+	return newContainerProvider(httpClient)
+}
+
+func newContainerProvider(httpClient HTTPGetter) *cache {
+	return newCache(func(ctx context.Context) (Credentials, error) {
+		if os.Getenv(containerCredentialsRelativeURI) == "" {
+			return Credentials{}, fmt.Errorf("%w: %s is not set", ErrNoCredentials, containerCredentialsRelativeURI)
+		}
+		fetcher, err := security.NewContainerCredentialsFetcher(contextGetter{ctx: ctx, httpClient: httpClient})
+		if err != nil {
+			return Credentials{}, err
+		}
+		awsCredentials, err := fetcher.FetchContainerCredentials()
+		if err != nil {
+			return Credentials{}, err
+		}
+		return Credentials{
+			AccessKeyID:     awsCredentials.AccessKey,
+			SecretAccessKey: awsCredentials.SecretKey,
+			SessionToken:    awsCredentials.SessionToken,
+			Expires:         awsCredentials.Expiration,
+			Source:          "ContainerProvider",
+		}, nil
+	})
+}
+
+// contextGetter - sends the requests of httpClient with ctx, so the retrieval stops when ctx is done,
+// e.g. when a ChainProvider is canceled.
+type contextGetter struct {
+	ctx        context.Context
+	httpClient HTTPGetter
+}
+
+func (g contextGetter) Get(url string) (*http.Response, error) {
+	if doer, ok := g.httpClient.(interface {
+		Do(req *http.Request) (*http.Response, error)
+	}); ok {
+		req, err := http.NewRequestWithContext(g.ctx, http.MethodGet, url, http.NoBody)
+		if err != nil {
+			return nil, err
+		}
+		return doer.Do(req)
+	}
+
+	type getResult struct {
+		resp *http.Response
+		err  error
+	}
+	done := make(chan getResult, 1)
+	go func() {
+		resp, err := g.httpClient.Get(url)
+		done <- getResult{resp: resp, err: err}
+	}()
+	select {
+	case res := <-done:
+		return res.resp, res.err
+	case <-g.ctx.Done():
+		go func() {
+			// The response is no longer needed, the connection is released once it arrives
+			if res := <-done; res.resp != nil {
+				res.resp.Body.Close()
+			}
+		}()
+		return nil, g.ctx.Err()
+	}
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+)
+
+func containerCredentialsResponse(accessKey string, expiration time.Time) *http.Response {
+	body := fmt.Sprintf(`{
+		"AccessKeyId": "%s",
+		"SecretAccessKey": "containerSecretKey",
+		"Token": "containerToken",
+		"Expiration": "%s"
+	}`, accessKey, expiration.Format(time.RFC3339))
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// GIVEN container credentials WHEN Retrieve THEN the credentials are fetched again shortly before they expire
+func TestContainerProvider_RefreshesBeforeExpiration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// GIVEN
+	t.Setenv(containerCredentialsRelativeURI, "/v2/credentials")
+	clock := mock.NewFakeClock(time.Date(2024, 8, 5, 10, 0, 0, 0, time.UTC))
+	httpClient := mock.NewMockHttpClient(ctrl)
+	provider := newContainerProvider(httpClient)
+	provider.clock = clock
+
+	// EXPECT
+	gomock.InOrder(
+		httpClient.EXPECT().
+			Get("http://169.254.170.2/v2/credentials").
+			Return(containerCredentialsResponse("firstAccessKey", clock.Now().Add(time.Hour)), nil),
+		httpClient.EXPECT().
+			Get("http://169.254.170.2/v2/credentials").
+			Return(containerCredentialsResponse("secondAccessKey", clock.Now().Add(2*time.Hour)), nil),
+	)
+
+	// WHEN
+	first, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Hour - refreshWindow - time.Second)
+	cached, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Second)
+	refreshed, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN
+	if first.AccessKeyID != "firstAccessKey" || cached.AccessKeyID != "firstAccessKey" || refreshed.AccessKeyID != "secondAccessKey" {
+		t.Fatalf("unexpected credentials: %+v, %+v, %+v", first, cached, refreshed)
+	}
+	if !first.Expires.Equal(time.Date(2024, 8, 5, 11, 0, 0, 0, time.UTC)) || first.SessionToken != "containerToken" {
+		t.Fatalf("unexpected credentials: %+v", first)
+	}
+}
+
+func TestContainerProvider_NotConfigured(t *testing.T) {
+	// GIVEN
+	t.Setenv(containerCredentialsRelativeURI, "")
+	provider := newContainerProvider(mock.NewMockHttpClient(gomock.NewController(t)))
+
+	// WHEN
+	_, err := provider.Retrieve(context.Background())
+
+	// THEN
+	if !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// GIVEN an *http.Client WHEN the container credentials are retrieved THEN they are requested with the client
+func TestNewContainerProviderWithClient(t *testing.T) {
+	// GIVEN
+	t.Setenv(containerCredentialsRelativeURI, "/v2/credentials")
+	var requestedURL string
+	httpClient := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requestedURL = req.URL.String()
+		return containerCredentialsResponse("containerAccessKey", time.Now().Add(time.Hour)), nil
+	})}
+
+	// WHEN
+	credentials, err := NewContainerProviderWithClient(httpClient).Retrieve(context.Background())
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requestedURL != "http://169.254.170.2/v2/credentials" || credentials.AccessKeyID != "containerAccessKey" {
+		t.Fatalf("unexpected request %s or credentials %+v", requestedURL, credentials)
+	}
+}
+
+// GIVEN a nil *http.Client WHEN NewContainerProviderWithClient THEN the client of NewContainerProvider is used
+func TestNewContainerProviderWithClient_NilHTTPClient(t *testing.T) {
+	// GIVEN
+	t.Setenv(containerCredentialsRelativeURI, "/v2/credentials")
+	var httpClient *http.Client
+	// The request is canceled before it is sent
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// WHEN
+	_, err := NewContainerProviderWithClient(httpClient).Retrieve(ctx)
+
+	// THEN
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// GIVEN an *http.Client WHEN the context of Retrieve is canceled THEN the request is canceled
+func TestContainerProvider_CanceledWithHTTPClient(t *testing.T) {
+	// GIVEN
+	t.Setenv(containerCredentialsRelativeURI, "/v2/credentials")
+	ctx, cancel := context.WithCancel(context.Background())
+	httpClient := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		cancel()
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})}
+
+	// WHEN
+	_, err := NewContainerProviderWithClient(httpClient).Retrieve(ctx)
+
+	// THEN
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// GIVEN an HTTPGetter without Do WHEN the context of Retrieve is canceled THEN Retrieve returns without the response
+func TestContainerProvider_CanceledWithHTTPGetter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// GIVEN
+	t.Setenv(containerCredentialsRelativeURI, "/v2/credentials")
+	ctx, cancel := context.WithCancel(context.Background())
+	httpClient := mock.NewMockHttpClient(ctrl)
+	release := make(chan struct{})
+	closed := make(chan struct{})
+
+	// EXPECT
+	httpClient.EXPECT().
+		Get("http://169.254.170.2/v2/credentials").
+		DoAndReturn(func(string) (*http.Response, error) {
+			cancel()
+			<-release
+			resp := containerCredentialsResponse("containerAccessKey", time.Now().Add(time.Hour))
+			resp.Body = closeNotifier{ReadCloser: resp.Body, closed: closed}
+			return resp, nil
+		})
+
+	// WHEN
+	_, err := newContainerProvider(httpClient).Retrieve(ctx)
+	close(release)
+
+	// THEN
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	// The late response is closed
+	<-closed
+}
+
+type closeNotifier struct {
+	io.ReadCloser
+	closed chan struct{}
+}
+
+func (c closeNotifier) Close() error {
+	close(c.closed)
+	return c.ReadCloser.Close()
+}
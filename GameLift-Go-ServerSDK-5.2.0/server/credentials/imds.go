@@ -0,0 +1,116 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	imdsDefaultEndpoint = "http://169.254.169.254"
+	imdsTokenPath       = "/latest/api/token"
+	imdsCredentialsPath = "/latest/meta-data/iam/security-credentials/"
+	imdsTokenTTLHeader  = "X-aws-ec2-metadata-token-ttl-seconds"
+	imdsTokenHeader     = "X-aws-ec2-metadata-token"
+	imdsTokenTTLSeconds = "21600"
+)
+
+// IMDSOptions - settings of the provider of the EC2 instance profile credentials.
+//
+//   - Endpoint - the instance metadata service endpoint. Defaults to AWS_EC2_METADATA_SERVICE_ENDPOINT
+//     or http://169.254.169.254.
+//   - HTTPClient - the client used to call the instance metadata service. Defaults to a client with a 1 second timeout,
+//     so hosts outside of EC2 quickly fall through to the next provider of a chain.
+type IMDSOptions struct {
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+// imdsCredentials - the response of the instance metadata service with the role credentials.
+type imdsCredentials struct {
+	Code            string
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string
+	Token           string
+	Expiration      time.Time
+}
+
+// NewIMDSProvider - creates a Provider of the instance profile credentials of an EC2 instance using IMDSv2.
+// The credentials are cached until they are close to expiration.
+// Set AWS_EC2_METADATA_DISABLED=true to disable the provider.
+func NewIMDSProvider(opts IMDSOptions) Provider {
+	endpoint := strings.TrimRight(firstNonEmpty(opts.Endpoint, os.Getenv("AWS_EC2_METADATA_SERVICE_ENDPOINT"), imdsDefaultEndpoint), "/")
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: time.Second}
+	}
+	return newCache(func(ctx context.Context) (Credentials, error) {
+		if strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+			return Credentials{}, fmt.Errorf("%w: AWS_EC2_METADATA_DISABLED is set", ErrNoCredentials)
+		}
+		token, err := imdsCall(ctx, httpClient, http.MethodPut, endpoint+imdsTokenPath, http.Header{
+			imdsTokenTTLHeader: []string{imdsTokenTTLSeconds},
+		})
+		if err != nil {
+			return Credentials{}, fmt.Errorf("%w: failed to get the instance metadata token: %s", ErrNoCredentials, err)
+		}
+		header := http.Header{imdsTokenHeader: []string{string(token)}}
+		roles, err := imdsCall(ctx, httpClient, http.MethodGet, endpoint+imdsCredentialsPath, header)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("%w: failed to get the instance profile: %s", ErrNoCredentials, err)
+		}
+		role := strings.TrimSpace(strings.SplitN(string(roles), "\n", 2)[0])
+		if role == "" {
+			return Credentials{}, fmt.Errorf("%w: the instance has no instance profile", ErrNoCredentials)
+		}
+		body, err := imdsCall(ctx, httpClient, http.MethodGet, endpoint+imdsCredentialsPath+role, header)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("failed to get the instance profile credentials: %w", err)
+		}
+		var res imdsCredentials
+		if err = json.Unmarshal(body, &res); err != nil {
+			return Credentials{}, fmt.Errorf("failed to decode the instance profile credentials: %w", err)
+		}
+		if res.Code != "Success" {
+			return Credentials{}, fmt.Errorf("failed to get the instance profile credentials: %s", res.Code)
+		}
+		return Credentials{
+			AccessKeyID:     res.AccessKeyID,
+			SecretAccessKey: res.SecretAccessKey,
+			SessionToken:    res.Token,
+			Expires:         res.Expiration,
+			Source:          "IMDSProvider",
+		}, nil
+	})
+}
+
+func imdsCall(ctx context.Context, httpClient *http.Client, method, url string, header http.Header) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unsuccessful response from the instance metadata service: %s", resp.Status)
+	}
+	return body, nil
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testIMDSToken = "test-imds-token"
+
+// startIMDS starts a fake instance metadata service which requires an IMDSv2 token.
+func startIMDS(t *testing.T, role string, expiration time.Time) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(imdsTokenPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.Header.Get(imdsTokenTTLHeader) == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(testIMDSToken))
+	})
+	mux.HandleFunc(imdsCredentialsPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(imdsTokenHeader) != testIMDSToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case imdsCredentialsPath:
+			_, _ = w.Write([]byte(role))
+		case imdsCredentialsPath + role:
+			_, _ = fmt.Fprintf(w, `{
+				"Code": "Success",
+				"Type": "AWS-HMAC",
+				"AccessKeyId": "imdsAccessKey",
+				"SecretAccessKey": "imdsSecretKey",
+				"Token": "imdsToken",
+				"Expiration": "%s"
+			}`, expiration.Format(time.RFC3339))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestIMDSProvider(t *testing.T) {
+	// GIVEN
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "")
+	expiration := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	srv := startIMDS(t, "test-instance-role", expiration)
+
+	// WHEN
+	c, err := NewIMDSProvider(IMDSOptions{Endpoint: srv.URL}).Retrieve(context.Background())
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.AccessKeyID != "imdsAccessKey" || c.SecretAccessKey != "imdsSecretKey" || c.SessionToken != "imdsToken" {
+		t.Fatalf("unexpected credentials: %+v", c)
+	}
+	if !c.Expires.Equal(expiration) {
+		t.Fatalf("unexpected expiration: %v", c.Expires)
+	}
+}
+
+func TestIMDSProvider_NoInstanceProfile(t *testing.T) {
+	// GIVEN
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "")
+	srv := startIMDS(t, "", time.Now())
+
+	// WHEN
+	_, err := NewIMDSProvider(IMDSOptions{Endpoint: srv.URL}).Retrieve(context.Background())
+
+	// THEN
+	if !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+}
+
+func TestIMDSProvider_Disabled(t *testing.T) {
+	// GIVEN
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	srv := startIMDS(t, "test-instance-role", time.Now().Add(time.Hour))
+
+	// WHEN
+	_, err := NewIMDSProvider(IMDSOptions{Endpoint: srv.URL}).Retrieve(context.Background())
+
+	// THEN
+	if !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package credentials provides the sources of the AWS credentials used to sign the connection to GameLift
+// when the SDK is initialized without an auth token, see server.ServerParameters.CredentialsProvider.
+//
+//	params := server.ServerParameters{
+//		WebSocketURL:        "wss://us-west-2.api.amazongamelift.com",
+//		ProcessID:           processID,
+//		HostID:              hostID,
+//		FleetID:             fleetID,
+//		AwsRegion:           "us-west-2",
+//		CredentialsProvider: credentials.NewDefaultChain(),
+//	}
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"aws/amazon-gamelift-go-sdk/common"
+)
+
+// refreshWindow - how long before the expiration temporary credentials are retrieved again.
+const refreshWindow = 5 * time.Minute
+
+// ErrNoCredentials - returned by a provider whose source of credentials is not configured,
+// e.g. the environment variables are not set. Use errors.Is to check for it.
+var ErrNoCredentials = errors.New("no AWS credentials found")
+
+// Credentials - AWS credentials with GameLift access.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken - the token of temporary credentials.
+	SessionToken string
+	// Expires - the expiration time of temporary credentials, zero if the credentials don't expire.
+	Expires time.Time
+	// Source - the name of the provider which returned the credentials.
+	Source string
+}
+
+// HasKeys - returns true if both the access key ID and the secret access key are set.
+func (c Credentials) HasKeys() bool {
+	return c.AccessKeyID != "" && c.SecretAccessKey != ""
+}
+
+// expiresWithin - returns true if the credentials expire within d from now.
+func (c Credentials) expiresWithin(now time.Time, d time.Duration) bool {
+	return !c.Expires.IsZero() && !now.Before(c.Expires.Add(-d))
+}
+
+// Provider - the source of AWS credentials.
+// Retrieve is called before every connection attempt to GameLift, so the credentials can be rotated.
+// Providers of temporary credentials cache them until they are close to expiration.
+type Provider interface {
+	Retrieve(ctx context.Context) (Credentials, error)
+}
+
+// ProviderFunc - adapts a function to the Provider interface.
+type ProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Retrieve - calls f(ctx).
+func (f ProviderFunc) Retrieve(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// StaticProvider - returns the credentials it was created with until they are updated.
+type StaticProvider struct {
+	mtx         sync.RWMutex
+	credentials Credentials
+}
+
+// NewStaticProvider - creates a StaticProvider of the specified keys.
+func NewStaticProvider(accessKeyID, secretAccessKey, sessionToken string) *StaticProvider {
+	p := &StaticProvider{}
+	p.Update(accessKeyID, secretAccessKey, sessionToken)
+	return p
+}
+
+// Retrieve - returns the current credentials or ErrNoCredentials if the keys are empty.
+func (p *StaticProvider) Retrieve(context.Context) (Credentials, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	if !p.credentials.HasKeys() {
+		return Credentials{}, fmt.Errorf("%w: static credentials are empty", ErrNoCredentials)
+	}
+	return p.credentials, nil
+}
+
+// Update - replaces the credentials returned by the following Retrieve calls.
+func (p *StaticProvider) Update(accessKeyID, secretAccessKey, sessionToken string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.credentials = Credentials{
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		SessionToken:    sessionToken,
+		Source:          "StaticProvider",
+	}
+}
+
+// NewEnvProvider - creates a Provider of the credentials from the standard AWS environment variables
+// AWS_ACCESS_KEY_ID (or AWS_ACCESS_KEY), AWS_SECRET_ACCESS_KEY (or AWS_SECRET_KEY) and AWS_SESSION_TOKEN.
+// The variables are read on every Retrieve call.
+func NewEnvProvider() Provider {
+	return ProviderFunc(func(context.Context) (Credentials, error) {
+		c := Credentials{
+			AccessKeyID:     firstEnv("AWS_ACCESS_KEY_ID", "AWS_ACCESS_KEY"),
+			SecretAccessKey: firstEnv("AWS_SECRET_ACCESS_KEY", "AWS_SECRET_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			Source:          "EnvProvider",
+		}
+		if !c.HasKeys() {
+			return Credentials{}, fmt.Errorf("%w: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are not set", ErrNoCredentials)
+		}
+		return c, nil
+	})
+}
+
+// ChainProvider - returns the credentials of the first provider in the chain that succeeds.
+// The successful provider is used directly by the following Retrieve calls until it fails.
+type ChainProvider struct {
+	providers []Provider
+
+	mtx     sync.Mutex
+	current Provider
+}
+
+// NewChainProvider - creates a ChainProvider of the providers in the order they are tried.
+func NewChainProvider(providers ...Provider) *ChainProvider {
+	return &ChainProvider{providers: providers}
+}
+
+// NewDefaultChain - creates a ChainProvider which looks for credentials in the following order:
+// environment variables, shared credentials and config files, web identity token file,
+// ECS container credentials and EC2 instance profile (IMDSv2).
+func NewDefaultChain() *ChainProvider {
+	return NewChainProvider(
+		NewEnvProvider(),
+		NewSharedConfigProvider(SharedConfigOptions{}),
+		NewWebIdentityProvider(WebIdentityOptions{}),
+		NewContainerProvider(),
+		NewIMDSProvider(IMDSOptions{}),
+	)
+}
+
+// Retrieve - returns the credentials of the first provider that succeeds.
+// Returns an error wrapping ErrNoCredentials with the reasons of all providers if none of them succeeds.
+func (p *ChainProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.current != nil {
+		if c, err := p.current.Retrieve(ctx); err == nil {
+			return c, nil
+		}
+		p.current = nil
+	}
+	reasons := make([]string, 0, len(p.providers))
+	for _, provider := range p.providers {
+		c, err := provider.Retrieve(ctx)
+		if err == nil {
+			p.current = provider
+			return c, nil
+		}
+		reasons = append(reasons, err.Error())
+		if ctx.Err() != nil {
+			return Credentials{}, ctx.Err()
+		}
+	}
+	return Credentials{}, fmt.Errorf("%w in the chain: [%s]", ErrNoCredentials, strings.Join(reasons, "; "))
+}
+
+// cache - caches temporary credentials until they are close to expiration.
+// Credentials without expiration are cached forever.
+type cache struct {
+	fetch func(ctx context.Context) (Credentials, error)
+	clock common.Clock
+
+	mtx         sync.Mutex
+	credentials *Credentials
+}
+
+func newCache(fetch func(ctx context.Context) (Credentials, error)) *cache {
+	return &cache{fetch: fetch, clock: common.NewRealClock()}
+}
+
+func (c *cache) Retrieve(ctx context.Context) (Credentials, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.credentials != nil && !c.credentials.expiresWithin(c.clock.Now(), refreshWindow) {
+		return *c.credentials, nil
+	}
+	credentials, err := c.fetch(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	c.credentials = &credentials
+	return credentials, nil
+}
+
+func firstEnv(keys ...string) string {
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package credentials
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+)
+
+// countingProvider returns the credentials or the error and counts the calls.
+type countingProvider struct {
+	credentials Credentials
+	err         error
+	calls       int
+}
+
+func (p *countingProvider) Retrieve(context.Context) (Credentials, error) {
+	p.calls++
+	return p.credentials, p.err
+}
+
+func TestStaticProvider(t *testing.T) {
+	// GIVEN
+	provider := NewStaticProvider("testAccessKey", "testSecretKey", "testToken")
+
+	// WHEN
+	first, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider.Update("rotatedAccessKey", "rotatedSecretKey", "")
+	second, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider.Update("", "", "")
+	_, emptyErr := provider.Retrieve(context.Background())
+
+	// THEN
+	if first.AccessKeyID != "testAccessKey" || first.SecretAccessKey != "testSecretKey" || first.SessionToken != "testToken" {
+		t.Errorf("unexpected credentials: %+v", first)
+	}
+	if second.AccessKeyID != "rotatedAccessKey" || second.SessionToken != "" {
+		t.Errorf("unexpected credentials: %+v", second)
+	}
+	if !errors.Is(emptyErr, ErrNoCredentials) {
+		t.Errorf("expected ErrNoCredentials, got %v", emptyErr)
+	}
+}
+
+func TestEnvProvider(t *testing.T) {
+	t.Run("standard variables", func(t *testing.T) {
+		t.Setenv("AWS_ACCESS_KEY_ID", "testAccessKey")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "testSecretKey")
+		t.Setenv("AWS_SESSION_TOKEN", "testToken")
+
+		c, err := NewEnvProvider().Retrieve(context.Background())
+		if err != nil || c.AccessKeyID != "testAccessKey" || c.SecretAccessKey != "testSecretKey" || c.SessionToken != "testToken" {
+			t.Fatalf("unexpected credentials: %+v, %v", c, err)
+		}
+	})
+
+	t.Run("alternative variables", func(t *testing.T) {
+		t.Setenv("AWS_ACCESS_KEY_ID", "")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+		t.Setenv("AWS_ACCESS_KEY", "testAccessKey")
+		t.Setenv("AWS_SECRET_KEY", "testSecretKey")
+
+		c, err := NewEnvProvider().Retrieve(context.Background())
+		if err != nil || c.AccessKeyID != "testAccessKey" || c.SecretAccessKey != "testSecretKey" {
+			t.Fatalf("unexpected credentials: %+v, %v", c, err)
+		}
+	})
+
+	t.Run("missing variables", func(t *testing.T) {
+		t.Setenv("AWS_ACCESS_KEY_ID", "")
+		t.Setenv("AWS_ACCESS_KEY", "")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "testSecretKey")
+
+		if _, err := NewEnvProvider().Retrieve(context.Background()); !errors.Is(err, ErrNoCredentials) {
+			t.Fatalf("expected ErrNoCredentials, got %v", err)
+		}
+	})
+}
+
+func TestChainProvider_UsesFirstSuccessfulProvider(t *testing.T) {
+	// GIVEN
+	missing := &countingProvider{err: ErrNoCredentials}
+	found := &countingProvider{credentials: Credentials{AccessKeyID: "testAccessKey", SecretAccessKey: "testSecretKey"}}
+	unused := &countingProvider{credentials: Credentials{AccessKeyID: "unusedAccessKey", SecretAccessKey: "unusedSecretKey"}}
+	chain := NewChainProvider(missing, found, unused)
+
+	// WHEN
+	for i := 0; i < 2; i++ {
+		c, err := chain.Retrieve(context.Background())
+		if err != nil || c.AccessKeyID != "testAccessKey" {
+			t.Fatalf("unexpected credentials: %+v, %v", c, err)
+		}
+	}
+
+	// THEN
+	// The successful provider is used directly by the following calls
+	if missing.calls != 1 || found.calls != 2 || unused.calls != 0 {
+		t.Fatalf("unexpected calls: %d, %d, %d", missing.calls, found.calls, unused.calls)
+	}
+}
+
+func TestChainProvider_FallsBackWhenProviderFails(t *testing.T) {
+	// GIVEN
+	first := &countingProvider{credentials: Credentials{AccessKeyID: "firstAccessKey", SecretAccessKey: "firstSecretKey"}}
+	second := &countingProvider{credentials: Credentials{AccessKeyID: "secondAccessKey", SecretAccessKey: "secondSecretKey"}}
+	chain := NewChainProvider(first, second)
+	if _, err := chain.Retrieve(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	first.err = errors.New("test error")
+	c, err := chain.Retrieve(context.Background())
+
+	// THEN
+	if err != nil || c.AccessKeyID != "secondAccessKey" {
+		t.Fatalf("unexpected credentials: %+v, %v", c, err)
+	}
+}
+
+func TestChainProvider_NoCredentials(t *testing.T) {
+	// GIVEN
+	chain := NewChainProvider(
+		&countingProvider{err: errors.New("first error")},
+		&countingProvider{err: errors.New("second error")},
+	)
+
+	// WHEN
+	_, err := chain.Retrieve(context.Background())
+
+	// THEN
+	if !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+	if err.Error() != "no AWS credentials found in the chain: [first error; second error]" {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+}
+
+func TestCache_RefreshesBeforeExpiration(t *testing.T) {
+	// GIVEN
+	clock := mock.NewFakeClock(time.Now())
+	source := &countingProvider{credentials: Credentials{AccessKeyID: "testAccessKey", Expires: clock.Now().Add(time.Hour)}}
+	c := newCache(source.Retrieve)
+	c.clock = clock
+
+	// WHEN
+	_, _ = c.Retrieve(context.Background())
+	clock.Advance(time.Hour - refreshWindow - time.Second)
+	_, _ = c.Retrieve(context.Background())
+	callsBeforeRefresh := source.calls
+	clock.Advance(time.Second)
+	_, _ = c.Retrieve(context.Background())
+
+	// THEN
+	if callsBeforeRefresh != 1 || source.calls != 2 {
+		t.Fatalf("unexpected calls: %d, %d", callsBeforeRefresh, source.calls)
+	}
+}
+
+func TestCache_ErrorsAreNotCached(t *testing.T) {
+	// GIVEN
+	source := &countingProvider{err: errors.New("test error")}
+	c := newCache(source.Retrieve)
+
+	// WHEN
+	_, firstErr := c.Retrieve(context.Background())
+	source.err = nil
+	source.credentials = Credentials{AccessKeyID: "testAccessKey", SecretAccessKey: "testSecretKey"}
+	second, secondErr := c.Retrieve(context.Background())
+
+	// THEN
+	if firstErr == nil || secondErr != nil || second.AccessKeyID != "testAccessKey" {
+		t.Fatalf("unexpected results: %v, %+v, %v", firstErr, second, secondErr)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package credentials
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultProfile = "default"
+
+// SharedConfigOptions - settings of the provider of the credentials from the shared AWS files.
+//
+//   - CredentialsFile - the path to the shared credentials file.
+//     Defaults to AWS_SHARED_CREDENTIALS_FILE or ~/.aws/credentials.
+//   - ConfigFile - the path to the shared config file. Defaults to AWS_CONFIG_FILE or ~/.aws/config.
+//   - Profile - the name of the profile. Defaults to AWS_PROFILE or "default".
+type SharedConfigOptions struct {
+	CredentialsFile string
+	ConfigFile      string
+	Profile         string
+}
+
+// NewSharedConfigProvider - creates a Provider of the static keys of a profile in the shared AWS files.
+// The credentials file takes precedence over the config file.
+// The files are read on every Retrieve call, so the keys can be rotated by rewriting the files.
+func NewSharedConfigProvider(opts SharedConfigOptions) Provider {
+	return ProviderFunc(func(context.Context) (Credentials, error) {
+		profile := firstNonEmpty(opts.Profile, os.Getenv("AWS_PROFILE"), defaultProfile)
+		credentialsFile := firstNonEmpty(opts.CredentialsFile, os.Getenv("AWS_SHARED_CREDENTIALS_FILE"))
+		configFile := firstNonEmpty(opts.ConfigFile, os.Getenv("AWS_CONFIG_FILE"))
+		if credentialsFile == "" || configFile == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return Credentials{}, fmt.Errorf("%w: can't locate the shared config files: %s", ErrNoCredentials, err)
+			}
+			credentialsFile = firstNonEmpty(credentialsFile, filepath.Join(home, ".aws", "credentials"))
+			configFile = firstNonEmpty(configFile, filepath.Join(home, ".aws", "config"))
+		}
+
+		// Profiles of the config file except default are prefixed with "profile "
+		configSection := profile
+		if profile != defaultProfile {
+			configSection = "profile " + profile
+		}
+		for _, file := range []struct{ path, section string }{
+			{credentialsFile, profile},
+			{configFile, configSection},
+		} {
+			c, err := readSharedConfigKeys(file.path, file.section)
+			if err != nil {
+				return Credentials{}, err
+			}
+			if c.HasKeys() {
+				return c, nil
+			}
+		}
+		return Credentials{}, fmt.Errorf("%w: profile %q has no keys in %s or %s",
+			ErrNoCredentials, profile, credentialsFile, configFile)
+	})
+}
+
+// readSharedConfigKeys - reads the keys of the section of an INI file. A missing file has no keys.
+func readSharedConfigKeys(path, section string) (Credentials, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Credentials{}, nil
+	}
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to open the shared config file: %w", err)
+	}
+	defer file.Close()
+
+	c := Credentials{Source: "SharedConfigProvider: " + path}
+	inSection := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inSection = strings.TrimSpace(line[1:len(line)-1]) == section
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !inSection || !found {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "aws_access_key_id":
+			c.AccessKeyID = strings.TrimSpace(value)
+		case "aws_secret_access_key":
+			c.SecretAccessKey = strings.TrimSpace(value)
+		case "aws_session_token":
+			c.SessionToken = strings.TrimSpace(value)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return Credentials{}, fmt.Errorf("failed to read the shared config file: %w", err)
+	}
+	return c, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package credentials
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testCredentialsFile = `
+# comment
+[default]
+aws_access_key_id = defaultAccessKey
+aws_secret_access_key = defaultSecretKey
+
+[anywhere]
+aws_access_key_id=anywhereAccessKey
+aws_secret_access_key=anywhereSecretKey
+aws_session_token=anywhereToken
+`
+
+const testConfigFile = `
+[default]
+region = us-west-2
+
+[profile from-config]
+region = us-west-2
+aws_access_key_id = configAccessKey
+aws_secret_access_key = configSecretKey
+`
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	return path
+}
+
+func TestSharedConfigProvider(t *testing.T) {
+	credentialsFile := writeFile(t, "credentials", testCredentialsFile)
+	configFile := writeFile(t, "config", testConfigFile)
+	missingFile := filepath.Join(t.TempDir(), "missing")
+
+	tests := []struct {
+		name              string
+		opts              SharedConfigOptions
+		profileEnv        string
+		expectedAccessKey string
+		expectedToken     string
+	}{
+		{
+			name:              "default profile",
+			opts:              SharedConfigOptions{CredentialsFile: credentialsFile, ConfigFile: configFile},
+			expectedAccessKey: "defaultAccessKey",
+		},
+		{
+			name:              "profile option",
+			opts:              SharedConfigOptions{CredentialsFile: credentialsFile, ConfigFile: configFile, Profile: "anywhere"},
+			expectedAccessKey: "anywhereAccessKey",
+			expectedToken:     "anywhereToken",
+		},
+		{
+			name:              "AWS_PROFILE",
+			opts:              SharedConfigOptions{CredentialsFile: credentialsFile, ConfigFile: configFile},
+			profileEnv:        "anywhere",
+			expectedAccessKey: "anywhereAccessKey",
+			expectedToken:     "anywhereToken",
+		},
+		{
+			name:              "profile of the config file",
+			opts:              SharedConfigOptions{CredentialsFile: missingFile, ConfigFile: configFile, Profile: "from-config"},
+			expectedAccessKey: "configAccessKey",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("AWS_PROFILE", test.profileEnv)
+
+			c, err := NewSharedConfigProvider(test.opts).Retrieve(context.Background())
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.AccessKeyID != test.expectedAccessKey || c.SessionToken != test.expectedToken || !c.HasKeys() {
+				t.Fatalf("unexpected credentials: %+v", c)
+			}
+		})
+	}
+}
+
+func TestSharedConfigProvider_EnvironmentFiles(t *testing.T) {
+	// GIVEN
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", writeFile(t, "credentials", testCredentialsFile))
+	t.Setenv("AWS_CONFIG_FILE", writeFile(t, "config", testConfigFile))
+	t.Setenv("AWS_PROFILE", "")
+
+	// WHEN
+	c, err := NewSharedConfigProvider(SharedConfigOptions{}).Retrieve(context.Background())
+
+	// THEN
+	if err != nil || c.AccessKeyID != "defaultAccessKey" {
+		t.Fatalf("unexpected credentials: %+v, %v", c, err)
+	}
+}
+
+func TestSharedConfigProvider_NoCredentials(t *testing.T) {
+	// GIVEN
+	opts := SharedConfigOptions{
+		CredentialsFile: filepath.Join(t.TempDir(), "missing"),
+		ConfigFile:      writeFile(t, "config", testConfigFile),
+		Profile:         "unknown",
+	}
+
+	// WHEN
+	_, err := NewSharedConfigProvider(opts).Retrieve(context.Background())
+
+	// THEN
+	if !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+}
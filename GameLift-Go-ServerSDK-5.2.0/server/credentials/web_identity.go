@@ -0,0 +1,128 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package credentials
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const stsAPIVersion = "2011-06-15"
+
+// WebIdentityOptions - settings of the provider of the credentials of a role assumed with a web identity token.
+//
+//   - RoleArn - the ARN of the role to assume. Defaults to AWS_ROLE_ARN.
+//   - TokenFile - the path to the file with the web identity token. Defaults to AWS_WEB_IDENTITY_TOKEN_FILE.
+//   - RoleSessionName - the name of the role session. Defaults to AWS_ROLE_SESSION_NAME or a generated one.
+//   - Region - the region of the STS endpoint. Defaults to AWS_REGION or AWS_DEFAULT_REGION.
+//   - Endpoint - the STS endpoint. Defaults to the regional endpoint, or the global one if the region is not set.
+//   - HTTPClient - the client used to call STS. Defaults to a client with a 10 seconds timeout.
+type WebIdentityOptions struct {
+	RoleArn         string
+	TokenFile       string
+	RoleSessionName string
+	Region          string
+	Endpoint        string
+	HTTPClient      *http.Client
+}
+
+// assumeRoleWithWebIdentityResponse - the response of the STS AssumeRoleWithWebIdentity action.
+type assumeRoleWithWebIdentityResponse struct {
+	Credentials struct {
+		AccessKeyID     string    `xml:"AccessKeyId"`
+		SecretAccessKey string    `xml:"SecretAccessKey"`
+		SessionToken    string    `xml:"SessionToken"`
+		Expiration      time.Time `xml:"Expiration"`
+	} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+}
+
+// stsErrorResponse - the error response of STS.
+type stsErrorResponse struct {
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// NewWebIdentityProvider - creates a Provider of the credentials of a role assumed with STS AssumeRoleWithWebIdentity,
+// e.g. on Kubernetes with IAM roles for service accounts.
+// The token file is read on every request, so the token can be rotated. The credentials are cached until they
+// are close to expiration.
+func NewWebIdentityProvider(opts WebIdentityOptions) Provider {
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return newCache(func(ctx context.Context) (Credentials, error) {
+		roleArn := firstNonEmpty(opts.RoleArn, os.Getenv("AWS_ROLE_ARN"))
+		tokenFile := firstNonEmpty(opts.TokenFile, os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"))
+		if roleArn == "" || tokenFile == "" {
+			return Credentials{}, fmt.Errorf("%w: AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE are not set", ErrNoCredentials)
+		}
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("failed to read the web identity token file: %w", err)
+		}
+		sessionName := firstNonEmpty(
+			opts.RoleSessionName,
+			os.Getenv("AWS_ROLE_SESSION_NAME"),
+			"gamelift-sdk-"+strconv.FormatInt(time.Now().UnixNano(), 10),
+		)
+
+		form := url.Values{}
+		form.Set("Action", "AssumeRoleWithWebIdentity")
+		form.Set("Version", stsAPIVersion)
+		form.Set("RoleArn", roleArn)
+		form.Set("RoleSessionName", sessionName)
+		form.Set("WebIdentityToken", strings.TrimSpace(string(token)))
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, stsEndpoint(opts), strings.NewReader(form.Encode()))
+		if err != nil {
+			return Credentials{}, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("failed to call AssumeRoleWithWebIdentity: %w", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("failed to read the AssumeRoleWithWebIdentity response: %w", err)
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			var stsErr stsErrorResponse
+			_ = xml.Unmarshal(body, &stsErr)
+			return Credentials{}, fmt.Errorf("AssumeRoleWithWebIdentity failed: %s: %s %s", resp.Status, stsErr.Code, stsErr.Message)
+		}
+		var res assumeRoleWithWebIdentityResponse
+		if err = xml.Unmarshal(body, &res); err != nil {
+			return Credentials{}, fmt.Errorf("failed to decode the AssumeRoleWithWebIdentity response: %w", err)
+		}
+		return Credentials{
+			AccessKeyID:     res.Credentials.AccessKeyID,
+			SecretAccessKey: res.Credentials.SecretAccessKey,
+			SessionToken:    res.Credentials.SessionToken,
+			Expires:         res.Credentials.Expiration,
+			Source:          "WebIdentityProvider",
+		}, nil
+	})
+}
+
+func stsEndpoint(opts WebIdentityOptions) string {
+	if opts.Endpoint != "" {
+		return opts.Endpoint
+	}
+	if region := firstNonEmpty(opts.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION")); region != "" {
+		return fmt.Sprintf("https://sts.%s.amazonaws.com/", region)
+	}
+	return "https://sts.amazonaws.com/"
+}
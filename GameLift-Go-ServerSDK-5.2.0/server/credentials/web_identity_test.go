@@ -0,0 +1,132 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package credentials
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testAssumeRoleWithWebIdentityResponse = `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>webIdentityAccessKey</AccessKeyId>
+      <SecretAccessKey>webIdentitySecretKey</SecretAccessKey>
+      <SessionToken>webIdentityToken</SessionToken>
+      <Expiration>2024-08-05T11:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`
+
+const testSTSErrorResponse = `<ErrorResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <Error>
+    <Type>Sender</Type>
+    <Code>InvalidIdentityToken</Code>
+    <Message>Token is expired</Message>
+  </Error>
+</ErrorResponse>`
+
+func TestWebIdentityProvider(t *testing.T) {
+	// GIVEN
+	var form map[string]string
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		form = map[string]string{}
+		for key := range r.PostForm {
+			form[key] = r.PostForm.Get(key)
+		}
+		_, _ = w.Write([]byte(testAssumeRoleWithWebIdentityResponse))
+	}))
+	defer sts.Close()
+	t.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/test-role")
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", writeFile(t, "token", "test-web-identity-token\n"))
+	t.Setenv("AWS_ROLE_SESSION_NAME", "test-session")
+
+	// WHEN
+	c, err := NewWebIdentityProvider(WebIdentityOptions{Endpoint: sts.URL}).Retrieve(context.Background())
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.AccessKeyID != "webIdentityAccessKey" || c.SecretAccessKey != "webIdentitySecretKey" || c.SessionToken != "webIdentityToken" {
+		t.Fatalf("unexpected credentials: %+v", c)
+	}
+	if !c.Expires.Equal(time.Date(2024, 8, 5, 11, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected expiration: %v", c.Expires)
+	}
+	expectedForm := map[string]string{
+		"Action":           "AssumeRoleWithWebIdentity",
+		"Version":          stsAPIVersion,
+		"RoleArn":          "arn:aws:iam::123456789012:role/test-role",
+		"RoleSessionName":  "test-session",
+		"WebIdentityToken": "test-web-identity-token",
+	}
+	for key, value := range expectedForm {
+		if form[key] != value {
+			t.Errorf("unexpected value for %s: got %s, want %s", key, form[key], value)
+		}
+	}
+}
+
+func TestWebIdentityProvider_STSError(t *testing.T) {
+	// GIVEN
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(testSTSErrorResponse))
+	}))
+	defer sts.Close()
+	opts := WebIdentityOptions{
+		RoleArn:   "arn:aws:iam::123456789012:role/test-role",
+		TokenFile: writeFile(t, "token", "test-web-identity-token"),
+		Endpoint:  sts.URL,
+	}
+
+	// WHEN
+	_, err := NewWebIdentityProvider(opts).Retrieve(context.Background())
+
+	// THEN
+	if err == nil || !strings.Contains(err.Error(), "InvalidIdentityToken Token is expired") {
+		t.Fatalf("expected STS error, got %v", err)
+	}
+}
+
+func TestWebIdentityProvider_NotConfigured(t *testing.T) {
+	// GIVEN
+	t.Setenv("AWS_ROLE_ARN", "")
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+
+	// WHEN
+	_, err := NewWebIdentityProvider(WebIdentityOptions{}).Retrieve(context.Background())
+
+	// THEN
+	if !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+}
+
+func TestSTSEndpoint(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	if endpoint := stsEndpoint(WebIdentityOptions{}); endpoint != "https://sts.amazonaws.com/" {
+		t.Errorf("unexpected global endpoint: %s", endpoint)
+	}
+	if endpoint := stsEndpoint(WebIdentityOptions{Region: "us-west-2"}); endpoint != "https://sts.us-west-2.amazonaws.com/" {
+		t.Errorf("unexpected regional endpoint: %s", endpoint)
+	}
+	t.Setenv("AWS_REGION", "ap-northeast-1")
+	if endpoint := stsEndpoint(WebIdentityOptions{}); endpoint != "https://sts.ap-northeast-1.amazonaws.com/" {
+		t.Errorf("unexpected regional endpoint: %s", endpoint)
+	}
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package fleetrole provides an aws-sdk-go-v2 credentials provider for the fleet role credentials
+// retrieved with server.GetFleetRoleCredentials.
+package fleetrole
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model/request"
+	"aws/amazon-gamelift-go-sdk/model/result"
+	"aws/amazon-gamelift-go-sdk/server"
+)
+
+// ProviderName - the source of the credentials returned by CredentialsProvider.
+const ProviderName = "GameLiftFleetRoleProvider"
+
+// minRefreshInterval - minimal delay between background refreshes, e.g. after a failed one.
+const minRefreshInterval = 30 * time.Second
+
+// Options - settings of the CredentialsProvider.
+type Options struct {
+	// RoleSessionName - an identifier for the assumed role session. Defaults to fleetId-hostId.
+	// Length Constraints: Minimum length of 2. Maximum length of 64.
+	RoleSessionName string
+
+	// RefreshWindow - how long before the expiration the credentials are refreshed in the background.
+	// Zero or values greater than common.InstanceRoleCredentialTTL mean common.InstanceRoleCredentialTTL,
+	// because GetFleetRoleCredentials returns the cached credentials until then.
+	RefreshWindow time.Duration
+}
+
+// refreshCall - GetFleetRoleCredentials round trip shared by concurrent Retrieve calls.
+type refreshCall struct {
+	done  chan struct{}
+	creds aws.Credentials
+	err   error
+}
+
+// CredentialsProvider - implements aws.CredentialsProvider for a fleet role.
+// Credentials are refreshed in the background before they expire, so AWS clients never get expired keys.
+// Concurrent Retrieve calls share a single GetFleetRoleCredentials request.
+//
+// Create a separate provider for every role or role session name.
+// Call Close to stop the background refresh when the provider is no longer needed.
+//
+//	provider := fleetrole.NewCredentialsProvider("arn:aws:iam::123456789012:role/exampleGameLiftAction", fleetrole.Options{})
+//	defer provider.Close()
+//	s3Client := s3.New(s3.Options{Region: "us-west-2", Credentials: provider})
+type CredentialsProvider struct {
+	roleArn       string
+	sessionName   string
+	refreshWindow time.Duration
+	clock         common.Clock
+	fetch         func(request.GetFleetRoleCredentialsRequest) (result.GetFleetRoleCredentialsResult, error)
+
+	mtx           sync.Mutex
+	creds         aws.Credentials
+	inFlight      *refreshCall
+	loopStarted   bool
+	closed        chan struct{}
+	closeOnce     sync.Once
+	loopWaitGroup sync.WaitGroup
+}
+
+// NewCredentialsProvider - creates a CredentialsProvider of the role with the specified ARN.
+// Credentials are requested on the first Retrieve call, so the provider can be created before server.ProcessReady.
+func NewCredentialsProvider(roleArn string, opts Options) *CredentialsProvider {
+	return newCredentialsProvider(roleArn, opts, common.NewRealClock(), server.GetFleetRoleCredentials)
+}
+
+func newCredentialsProvider(
+	roleArn string,
+	opts Options,
+	clock common.Clock,
+	fetch func(request.GetFleetRoleCredentialsRequest) (result.GetFleetRoleCredentialsResult, error),
+) *CredentialsProvider {
+	refreshWindow := opts.RefreshWindow
+	if refreshWindow <= 0 || refreshWindow > common.InstanceRoleCredentialTTL {
+		refreshWindow = common.InstanceRoleCredentialTTL
+	}
+	return &CredentialsProvider{
+		roleArn:       roleArn,
+		sessionName:   opts.RoleSessionName,
+		refreshWindow: refreshWindow,
+		clock:         clock,
+		fetch:         fetch,
+		closed:        make(chan struct{}),
+	}
+}
+
+// Retrieve - returns the current credentials of the role.
+// It waits for new credentials only if there are no valid ones, e.g. on the first call.
+func (p *CredentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	p.mtx.Lock()
+	if p.creds.HasKeys() && p.clock.Now().Before(p.creds.Expires) {
+		creds := p.creds
+		p.mtx.Unlock()
+		return creds, nil
+	}
+	call := p.startRefresh()
+	p.mtx.Unlock()
+
+	select {
+	case <-call.done:
+		return call.creds, call.err
+	case <-ctx.Done():
+		return aws.Credentials{}, ctx.Err()
+	}
+}
+
+// Close - stops the background refresh. Retrieve can still be called after Close.
+func (p *CredentialsProvider) Close() {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+	})
+	p.loopWaitGroup.Wait()
+}
+
+// startRefresh - returns the ongoing refresh or starts a new one. Must be called with p.mtx locked.
+func (p *CredentialsProvider) startRefresh() *refreshCall {
+	if p.inFlight != nil {
+		return p.inFlight
+	}
+	call := &refreshCall{done: make(chan struct{})}
+	p.inFlight = call
+	go p.refresh(call)
+	return call
+}
+
+func (p *CredentialsProvider) refresh(call *refreshCall) {
+	req := request.NewGetFleetRoleCredentials()
+	req.RoleArn = p.roleArn
+	req.RoleSessionName = p.sessionName
+	res, err := p.fetch(req)
+
+	var creds aws.Credentials
+	if err == nil {
+		creds = aws.Credentials{
+			AccessKeyID:     res.AccessKeyID,
+			SecretAccessKey: res.SecretAccessKey,
+			SessionToken:    res.SessionToken,
+			Source:          ProviderName,
+			CanExpire:       true,
+			Expires:         time.UnixMilli(res.Expiration),
+		}
+	}
+
+	p.mtx.Lock()
+	p.inFlight = nil
+	if err == nil {
+		p.creds = creds
+		p.startRefreshLoop()
+	}
+	p.mtx.Unlock()
+
+	call.creds, call.err = creds, err
+	close(call.done)
+}
+
+// startRefreshLoop - starts the background refresh once. Must be called with p.mtx locked.
+func (p *CredentialsProvider) startRefreshLoop() {
+	if p.loopStarted {
+		return
+	}
+	select {
+	case <-p.closed:
+		return
+	default:
+	}
+	p.loopStarted = true
+	p.loopWaitGroup.Add(1)
+	go p.refreshLoop()
+}
+
+func (p *CredentialsProvider) refreshLoop() {
+	defer p.loopWaitGroup.Done()
+	for {
+		select {
+		case <-p.closed:
+			return
+		case <-p.clock.After(p.nextRefresh()):
+		}
+
+		p.mtx.Lock()
+		call := p.startRefresh()
+		p.mtx.Unlock()
+
+		select {
+		case <-p.closed:
+			return
+		case <-call.done:
+		}
+	}
+}
+
+// nextRefresh - returns the time until the next background refresh.
+func (p *CredentialsProvider) nextRefresh() time.Duration {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	untilRefresh := p.creds.Expires.Add(-p.refreshWindow).Sub(p.clock.Now())
+	if untilRefresh < minRefreshInterval {
+		return minRefreshInterval
+	}
+	return untilRefresh
+}
@@ -0,0 +1,269 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package fleetrole
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model/request"
+	"aws/amazon-gamelift-go-sdk/model/result"
+	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+)
+
+const (
+	testRoleArn     = "arn:aws:iam::123456789012:role/test-role"
+	testSessionName = "test-session"
+	testLifetime    = time.Hour
+)
+
+// fakeFetch returns credentials which expire testLifetime after the current time of the clock.
+type fakeFetch struct {
+	clock *mock.FakeClock
+
+	mtx      sync.Mutex
+	requests []request.GetFleetRoleCredentialsRequest
+	err      error
+	release  chan struct{}
+}
+
+func (f *fakeFetch) fetch(req request.GetFleetRoleCredentialsRequest) (result.GetFleetRoleCredentialsResult, error) {
+	f.mtx.Lock()
+	f.requests = append(f.requests, req)
+	release, err := f.release, f.err
+	f.mtx.Unlock()
+
+	if release != nil {
+		<-release
+	}
+	if err != nil {
+		return result.GetFleetRoleCredentialsResult{}, err
+	}
+	return result.GetFleetRoleCredentialsResult{
+		AccessKeyID:     "access-key",
+		SecretAccessKey: "secret-key",
+		SessionToken:    "session-token",
+		Expiration:      f.clock.Now().Add(testLifetime).UnixMilli(),
+	}, nil
+}
+
+func (f *fakeFetch) calls() int {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return len(f.requests)
+}
+
+func (f *fakeFetch) setErr(err error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.err = err
+}
+
+func newTestProvider(opts Options) (*CredentialsProvider, *fakeFetch, *mock.FakeClock) {
+	clock := mock.NewFakeClock(time.Now().Truncate(time.Millisecond))
+	f := &fakeFetch{clock: clock}
+	return newCredentialsProvider(testRoleArn, opts, clock, f.fetch), f, clock
+}
+
+func TestCredentialsProvider_Retrieve(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	// GIVEN
+	provider, f, clock := newTestProvider(Options{RoleSessionName: testSessionName})
+	defer provider.Close()
+
+	// WHEN
+	creds, err := provider.Retrieve(context.Background())
+
+	// THEN
+	if err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+	if creds.AccessKeyID != "access-key" || creds.SecretAccessKey != "secret-key" || creds.SessionToken != "session-token" {
+		t.Fatalf("unexpected credentials: %+v", creds)
+	}
+	if creds.Source != ProviderName || !creds.CanExpire || !creds.Expires.Equal(clock.Now().Add(testLifetime)) {
+		t.Fatalf("unexpected credentials metadata: %+v", creds)
+	}
+	if f.requests[0].RoleArn != testRoleArn || f.requests[0].RoleSessionName != testSessionName {
+		t.Fatalf("unexpected request: %+v", f.requests[0])
+	}
+
+	// Valid credentials are returned without a new request
+	if _, err = provider.Retrieve(context.Background()); err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+	if f.calls() != 1 {
+		t.Fatalf("expected 1 request, got %d", f.calls())
+	}
+}
+
+func TestCredentialsProvider_CoalescesConcurrentRetrieves(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	// GIVEN
+	provider, f, _ := newTestProvider(Options{})
+	defer provider.Close()
+	f.release = make(chan struct{})
+
+	// WHEN
+	const retrieves = 10
+	errs := make(chan error, retrieves)
+	for i := 0; i < retrieves; i++ {
+		go func() {
+			_, err := provider.Retrieve(context.Background())
+			errs <- err
+		}()
+	}
+	for f.calls() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(f.release)
+
+	// THEN
+	for i := 0; i < retrieves; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("retrieve: %v", err)
+		}
+	}
+	if f.calls() != 1 {
+		t.Fatalf("expected 1 request, got %d", f.calls())
+	}
+}
+
+func TestCredentialsProvider_RefreshesBeforeExpiration(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	// GIVEN
+	const refreshWindow = 10 * time.Minute
+	provider, f, clock := newTestProvider(Options{RefreshWindow: refreshWindow})
+	defer provider.Close()
+	first, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+
+	// WHEN
+	clock.BlockUntil(1)
+	clock.Advance(testLifetime - refreshWindow)
+	// The refresh loop waits for the next refresh once the new credentials are stored
+	clock.BlockUntil(1)
+
+	// THEN
+	if f.calls() != 2 {
+		t.Fatalf("expected 2 requests, got %d", f.calls())
+	}
+	second, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+	if !second.Expires.After(first.Expires) {
+		t.Fatalf("credentials were not refreshed: %v, %v", first.Expires, second.Expires)
+	}
+	if f.calls() != 2 {
+		t.Fatalf("expected 2 requests, got %d", f.calls())
+	}
+}
+
+func TestCredentialsProvider_RetriesFailedRefresh(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	// GIVEN
+	provider, f, clock := newTestProvider(Options{})
+	defer provider.Close()
+	first, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+	f.setErr(errors.New("test error"))
+
+	// WHEN
+	clock.BlockUntil(1)
+	clock.Advance(testLifetime - common.InstanceRoleCredentialTTL)
+	clock.BlockUntil(1)
+	clock.Advance(minRefreshInterval)
+	clock.BlockUntil(1)
+
+	// THEN
+	if f.calls() != 3 {
+		t.Fatalf("expected 3 requests, got %d", f.calls())
+	}
+	// The previous credentials are still valid
+	creds, err := provider.Retrieve(context.Background())
+	if err != nil || creds != first {
+		t.Fatalf("expected the previous credentials, got %+v, %v", creds, err)
+	}
+
+	f.setErr(nil)
+	clock.Advance(minRefreshInterval)
+	clock.BlockUntil(1)
+	creds, err = provider.Retrieve(context.Background())
+	if err != nil || !creds.Expires.After(first.Expires) {
+		t.Fatalf("expected new credentials, got %+v, %v", creds, err)
+	}
+}
+
+func TestCredentialsProvider_RetrieveError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	// GIVEN
+	provider, f, _ := newTestProvider(Options{})
+	defer provider.Close()
+	expectedErr := errors.New("test error")
+	f.setErr(expectedErr)
+
+	// WHEN
+	_, err := provider.Retrieve(context.Background())
+
+	// THEN
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("expected %v, got %v", expectedErr, err)
+	}
+}
+
+func TestCredentialsProvider_RetrieveContextCanceled(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	// GIVEN
+	provider, f, _ := newTestProvider(Options{})
+	defer provider.Close()
+	f.release = make(chan struct{})
+	defer close(f.release)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// WHEN
+	_, err := provider.Retrieve(ctx)
+
+	// THEN
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestCredentialsProvider_Close(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	// GIVEN
+	provider, f, clock := newTestProvider(Options{})
+	if _, err := provider.Retrieve(context.Background()); err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+	clock.BlockUntil(1)
+
+	// WHEN
+	provider.Close()
+	provider.Close()
+
+	// THEN
+	// Expired credentials are requested on demand after Close
+	clock.Advance(testLifetime)
+	if _, err := provider.Retrieve(context.Background()); err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+	if f.calls() != 2 {
+		t.Fatalf("expected 2 requests, got %d", f.calls())
+	}
+}
@@ -6,20 +6,30 @@
 package server
 
 import (
+	"fmt"
+	"io"
+	"os"
+
 	"aws/amazon-gamelift-go-sdk/common"
 	"aws/amazon-gamelift-go-sdk/model"
+	"aws/amazon-gamelift-go-sdk/model/message"
 	"aws/amazon-gamelift-go-sdk/model/request"
 	"aws/amazon-gamelift-go-sdk/model/result"
 	"aws/amazon-gamelift-go-sdk/server/internal"
 	"aws/amazon-gamelift-go-sdk/server/internal/transport"
 	"aws/amazon-gamelift-go-sdk/server/log"
+	"aws/amazon-gamelift-go-sdk/server/metrics"
 )
 
 var srv iGameLiftServerState
 var state gameLiftServerState
 var manager internal.IGameLiftManager
 
+// wsClient - the websocket client reused by every InitSDK, created with its transport and recorder by the first one.
+var wsClient internal.IWebSocketClient
+
 var lg log.ILogger = log.GetDefaultLogger()
+var mtr metrics.IMetrics = metrics.GetDefaultMetrics()
 
 func getServerParamsFromEnvironment() (ServerParameters, error) {
 	websocketURL, err := common.GetEnvStringOrError(common.EnvironmentKeyWebsocketURL)
@@ -56,6 +66,72 @@ func getServerParamsFromEnvironment() (ServerParameters, error) {
 	}, nil
 }
 
+// getDialerConfig - returns the network settings of the websocket connection.
+// Environment variables take precedence over ServerParameters.
+func getDialerConfig(params *ServerParameters, cfg Config) transport.DialerConfig {
+	return transport.DialerConfig{
+		ProxyURL:          common.GetEnvStringOrDefault(common.EnvironmentKeyProxyURL, params.ProxyURL),
+		RootCAFile:        common.GetEnvStringOrDefault(common.EnvironmentKeyRootCAFile, params.RootCAFile),
+		ClientCertFile:    common.GetEnvStringOrDefault(common.EnvironmentKeyClientCertFile, params.ClientCertFile),
+		ClientKeyFile:     common.GetEnvStringOrDefault(common.EnvironmentKeyClientKeyFile, params.ClientKeyFile),
+		MinTLSVersion:     common.GetEnvStringOrDefault(common.EnvironmentKeyMinTLSVersion, params.MinTLSVersion),
+		EnableCompression: cfg.WebsocketCompression,
+		BufferSize:        cfg.ServiceBufferSize,
+	}
+}
+
+// newRecorder - returns a recorder of the connection to GameLift if ServerParameters.RecordFile or
+// the GAMELIFT_SDK_RECORD_FILE environment variable is set, otherwise nil.
+func newRecorder(params *ServerParameters, clock common.Clock) (*transport.Recorder, error) {
+	recordFile := common.GetEnvStringOrDefault(common.EnvironmentKeyRecordFile, params.RecordFile)
+	if recordFile == "" {
+		return nil, nil
+	}
+	file, err := os.OpenFile(recordFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, common.NewGameLiftErrorWithCause(common.BadRequestException, "",
+			fmt.Sprintf("failed to open record file: %s", err), err)
+	}
+	lg.Warnf("Recording the messages of GameLift to %s", recordFile)
+	// The file stays open for the lifetime of the process, as the transport is created only once, see wsClient
+	return transport.NewRecorder(file, lg, clock), nil
+}
+
+// newWebsocketClient - creates the websocket client with its transport.
+func newWebsocketClient(params *ServerParameters, cfg Config) (internal.IWebSocketClient, error) {
+	wsDialer, err := transport.NewDialer(lg, getDialerConfig(params, cfg))
+	if err != nil {
+		return nil, err
+	}
+	recorder, err := newRecorder(params, state.clock)
+	if err != nil {
+		return nil, err
+	}
+	if recorder != nil {
+		// Every connection attempt is recorded, including the reconnects of the websocket transport
+		wsDialer = recorder.Dialer(wsDialer)
+	}
+	wsTransport := transport.Websocket(lg, mtr, wsDialer, state.clock, transport.WebsocketConfig{
+		PingInterval:     cfg.WebsocketPingInterval,
+		PongTimeout:      cfg.WebsocketPongTimeout,
+		ReadLimit:        cfg.WebsocketReadLimit,
+		LargeMessageSize: cfg.WebsocketLargeMessageSize,
+	})
+	if recorder != nil {
+		// Every write attempt is recorded, as the recorder wraps the transport under the retries
+		wsTransport = recorder.Transport(wsTransport)
+	}
+	wsTransport = transport.WithRetry(wsTransport, lg, state.clock, transport.RetryConfig{
+		MaxRetry: cfg.MaxRetry,
+		Factor:   cfg.RetryFactor,
+		Interval: cfg.RetryInterval,
+	})
+	return internal.GetWebsocketClient(wsTransport, lg, mtr, internal.DispatcherConfig{
+		Workers:   cfg.DispatcherWorkers,
+		QueueSize: cfg.DispatcherQueueSize,
+	}), nil
+}
+
 // SetLoggerInterface - use this function to inject custom logger to the GameLift SDK.
 //
 // It allows you to add your own logger to the SDK from the application, see log.ILogger.
@@ -63,6 +139,14 @@ func SetLoggerInterface(l log.ILogger) {
 	lg = l
 }
 
+// SetMetricsInterface - use this function to inject custom metrics sink to the GameLift SDK.
+//
+// It allows you to report the SDK metrics, such as websocket message sizes, to your monitoring system,
+// see metrics.IMetrics. By default, all metrics are discarded.
+func SetMetricsInterface(m metrics.IMetrics) {
+	mtr = m
+}
+
 // GetSdkVersion - returns the current version number of the SDK built into the server process.
 // The returned string includes the version number only (ex. 5.0.0).
 // If not successful, returns an error message see common.SdkVersionDetectionFailed.
@@ -87,6 +171,12 @@ func GetSdkVersion() (string, error) {
 //	}
 //
 // InitSDK will establish a local connection with GameLift's agent to enable further communication.
+// If the credentials, the auth token or the container metadata can't be retrieved, returns a common.GameLiftError
+// of the common.CredentialsFetchFailed, common.RequestSigningFailed or common.MetadataFetchFailed type,
+// and InitSDK can be called again.
+//
+// The tuning of the SDK is resolved from ServerParameters.Config, the GAMELIFT_SDK_CONFIG_FILE file
+// and the environment variables, see Config. If it is invalid, returns a common.BadRequestException error.
 //
 //	err := server.InitSDK(serverParameters)
 func InitSDK(params ServerParameters) error {
@@ -94,16 +184,31 @@ func InitSDK(params ServerParameters) error {
 	if srv != nil {
 		return common.NewGameLiftError(common.AlreadyInitialized, "", "")
 	}
+	if state.clock == nil {
+		state.clock = common.NewRealClock()
+	}
+	state.metrics = mtr
+	cfg, err := newConfig(&params)
+	if err != nil {
+		return err
+	}
+	lg.Debugf("GameLift SDK config: %s", cfg.Describe())
+	params.Config = &cfg
 	if manager == nil {
-		wsDialer := transport.NewDialer(lg)
-		wsTransport := transport.Websocket(lg, wsDialer)
-		wsTransport = transport.WithRetry(wsTransport, lg)
-		client := internal.GetWebsocketClient(wsTransport, lg)
-		manager = internal.GetGameLiftManager(&state, client, lg)
+		// The websocket client is a singleton, so its transport is not created again after Destroy
+		if wsClient == nil {
+			if wsClient, err = newWebsocketClient(&params, cfg); err != nil {
+				return err
+			}
+		}
+		manager = internal.GetGameLiftManager(&state, wsClient, lg, state.clock)
 	}
 	err = state.init(&params, manager)
+	if err != nil {
+		return err
+	}
 	srv = &state
-	return err
+	return nil
 }
 
 // InitSDKFromEnvironment - Initializes the GameLift server SDK from system environment variables
@@ -118,7 +223,7 @@ func InitSDK(params ServerParameters) error {
 func InitSDKFromEnvironment() error {
 	serverParams, err := getServerParamsFromEnvironment()
 	if err != nil {
-		return common.NewGameLiftError(common.NotInitialized, "Could not get server parameters from system environment variables", err.Error())
+		return common.NewGameLiftErrorWithCause(common.NotInitialized, "Could not get server parameters from system environment variables", "", err)
 	}
 	return InitSDK(serverParams)
 }
@@ -239,7 +344,7 @@ func GetTerminationTime() (int64, error) {
 // AcceptPlayerSession - notifies the GameLift service that a player with the specified player session ID has connected
 // to the server process and needs validation. GameLift verifies that the player session ID is valid—that is,
 // that the player ID has reserved a player slot in the game session. Once validated,
-// GameLift changes the model.playerSessionStatus from model.PlayerReserved to model.PlayerActive
+// GameLift changes the model.PlayerSessionStatus from model.PlayerReserved to model.PlayerActive
 //
 //	Receive: Unique ID issued by GameLift when a new player session is created.
 //
@@ -284,15 +389,110 @@ func RemovePlayerSession(playerSessionID string) error {
 // If successful, a result.DescribePlayerSessionsResult object is returned
 // containing a set of player session objects that fit the request parameters.
 //
-//	describePlayerSessionsRequest := request.NewDescribePlayerSessions() // to create this request, please use this func
-//	describePlayerSessionsRequest.GameSessionID, _ = server.GetGameSessionID() //gets the IDs for the current game session
-//	describePlayerSessionsRequest.Limit = 10 // return the first 10 player sessions
-//	describePlayerSessionsRequest.PlayerSessionStatusFilter = "ACTIVE" // All player sessions actively connected to a specified game session
+//	gameSessionID, _ := server.GetGameSessionID() // gets the ID of the current game session
+//	describePlayerSessionsRequest, err := request.NewDescribePlayerSessionsBuilder(). // to create this request, please use this builder
+//		WithGameSessionID(gameSessionID).
+//		WithLimit(10). // return the first 10 player sessions
+//		WithPlayerSessionStatusFilter(model.PlayerActive). // All player sessions actively connected to a specified game session
+//		Build()
 //	describePlayerSessionsResult, err := server.DescribePlayerSessions(describePlayerSessionsRequest)
 func DescribePlayerSessions(req request.DescribePlayerSessionsRequest) (result.DescribePlayerSessionsResult, error) {
 	return srv.describePlayerSessions(&req)
 }
 
+// DescribePlayerSessionsPages - retrieves all pages of player sessions that fit the request parameters.
+// Each page is requested with DescribePlayerSessions, following the NextToken of the previous page.
+//
+//	Receive: request.DescribePlayerSessionsRequest - object describing which player sessions to retrieve,
+//	Limit sets the size of each page.
+//	fn - function called with every page, lastPage is true for the final one. Return false to stop the iteration.
+//
+// Returns the error of the first failed DescribePlayerSessions call, if any.
+//
+//	gameSessionID, _ := server.GetGameSessionID()
+//	describePlayerSessionsRequest, _ := request.NewDescribePlayerSessionsBuilder().WithGameSessionID(gameSessionID).Build()
+//	err := server.DescribePlayerSessionsPages(describePlayerSessionsRequest,
+//		func(page result.DescribePlayerSessionsResult, lastPage bool) bool {
+//			for _, playerSession := range page.PlayerSessions {
+//				// process the player session
+//			}
+//			return true // continue with the next page
+//		})
+func DescribePlayerSessionsPages(
+	req request.DescribePlayerSessionsRequest,
+	fn func(page result.DescribePlayerSessionsResult, lastPage bool) bool,
+) error {
+	return describePlayerSessionsPages(req, 0, fn)
+}
+
+// DescribeAllPlayerSessions - retrieves player sessions that fit the request parameters from all pages.
+//
+//	Receive: request.DescribePlayerSessionsRequest - object describing which player sessions to retrieve,
+//	maxResults - overall maximum number of player sessions to return, zero or less means no limit.
+//
+//	describePlayerSessionsRequest, _ := request.NewDescribePlayerSessionsBuilder().WithPlayerID("player-1").Build()
+//	playerSessions, err := server.DescribeAllPlayerSessions(describePlayerSessionsRequest, 100)
+func DescribeAllPlayerSessions(req request.DescribePlayerSessionsRequest, maxResults int) ([]model.PlayerSession, error) {
+	var playerSessions []model.PlayerSession
+	err := describePlayerSessionsPages(req, maxResults, func(page result.DescribePlayerSessionsResult, _ bool) bool {
+		playerSessions = append(playerSessions, page.PlayerSessions...)
+		return true
+	})
+	return playerSessions, err
+}
+
+// ListActivePlayerSessions - retrieves all player sessions of the game session
+// that are actively connected to the server process.
+//
+//	gameSessionID, _ := server.GetGameSessionID()
+//	playerSessions, err := server.ListActivePlayerSessions(gameSessionID)
+func ListActivePlayerSessions(gameSessionID string) ([]model.PlayerSession, error) {
+	req, err := request.NewDescribePlayerSessionsBuilder().
+		WithGameSessionID(gameSessionID).
+		WithPlayerSessionStatusFilter(model.PlayerActive).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	return DescribeAllPlayerSessions(req, 0)
+}
+
+func describePlayerSessionsPages(
+	req request.DescribePlayerSessionsRequest,
+	maxResults int,
+	fn func(page result.DescribePlayerSessionsResult, lastPage bool) bool,
+) error {
+	remaining := maxResults
+	for {
+		// Do not request more player sessions than needed to reach maxResults
+		if maxResults > 0 && (req.Limit <= 0 || req.Limit > remaining) {
+			req.Limit = remaining
+			// A page cannot be larger than the limit accepted by GameLift
+			if req.Limit > common.MaxPlayerSessions {
+				req.Limit = common.MaxPlayerSessions
+			}
+		}
+		page, err := srv.describePlayerSessions(&req)
+		if err != nil {
+			return err
+		}
+		lastPage := page.NextToken == ""
+		if maxResults > 0 {
+			if len(page.PlayerSessions) > remaining {
+				page.PlayerSessions = page.PlayerSessions[:remaining]
+			}
+			remaining -= len(page.PlayerSessions)
+			lastPage = lastPage || remaining <= 0
+		}
+		if !fn(page, lastPage) || lastPage {
+			return nil
+		}
+		req.NextToken = page.NextToken
+		// Every page is a separate request that needs its own ID
+		req.RequestID = message.NewMessage(message.DescribePlayerSessions).RequestID
+	}
+}
+
 // StartMatchBackfill - sends a request to find new players for open slots in a game session created with FlexMatch.
 //
 //	See also the AWS SDK action https://docs.aws.amazon.com/gamelift/latest/apireference/API_StartMatchBackfill.html.
@@ -311,19 +511,21 @@ func DescribePlayerSessions(req request.DescribePlayerSessionsRequest) (result.D
 //	- The matchmaker to send the request to. The full configuration ARN is required.
 //		This value can be acquired from the game session's matchmaker data.
 //	- The ID of the game session that is being backfilled.
-//	- Available matchmaking data for the game session's current players.
+//	- Available matchmaking data for the game session's current players, including their latencies,
+//		which are kept by model.MatchmakerData.
 //
 //	If successful, returns a result.StartMatchBackfillResult - object with the match backfill ticket id,
 //	otherwise return an error.
 //
-//	startBackfillRequest := request.NewStartMatchBackfill() // Please use this function to create request
-//	startBackfillRequest.RequestID = "a ticket ID"          // optional
-//	startBackfillRequest.MatchmakingConfigurationArn = "the matchmaker configuration ARN"
 //	var matchMaker model.MatchmakerData
 //	if err := matchMaker.UnmarshalJSON([]byte(gameSession.MatchmakerData)); err != nil {
 //		return
 //	}
-//	startBackfillRequest.Players = matchMaker.Players
+//	startBackfillRequest, err := request.NewStartMatchBackfillBuilder("the matchmaker configuration ARN").
+//		WithGameSessionArn(gameSession.GameSessionID).
+//		WithTicketID("a ticket ID"). // optional
+//		WithPlayers(matchMaker.Players...).
+//		Build()
 //	res, err := server.StartMatchBackfill(startBackfillRequest)
 //
 //	// Implement callback function for backfill
@@ -345,10 +547,11 @@ func StartMatchBackfill(req request.StartMatchBackfillRequest) (result.StartMatc
 //
 //	Returns an error if failure with an error message.
 //
-//	stopBackfillRequest := request.NewStopMatchBackfill()     // Please use this function to create request
-//	stopBackfillRequest.TicketID = "a ticket ID"              // optional, if not provided one is autogenerated
-//	stopBackfillRequest.MatchmakingConfigurationArn = "the matchmaker configuration ARN" // from the game session matchmaker data
-//	err := server.StopMatchBackfill(stopBackfillRequest)
+//	stopBackfillRequest, err := request.NewStopMatchBackfillBuilder(
+//		"the matchmaker configuration ARN", // from the game session matchmaker data
+//		"a ticket ID",                      // returned by StartMatchBackfill
+//	).Build()
+//	err = server.StopMatchBackfill(stopBackfillRequest)
 func StopMatchBackfill(req request.StopMatchBackfillRequest) error {
 	return srv.stopMatchBackfill(&req)
 }
@@ -383,8 +586,9 @@ func GetComputeCertificate() (result.GetComputeCertificateResult, error) {
 // request.GetFleetRoleCredentialsRequest - object identifying the Amazon Resource Name of the role you are requesting the credentials of.
 //
 //	// form the customer credentials request
-//	getFleetRoleCredentialsRequest := request.NewGetFleetRoleCredentials() // Please use this function to create request
-//	getFleetRoleCredentialsRequest.RoleArn = "arn:aws:iam::123456789012:role/service-role/exampleGameLiftAction"
+//	getFleetRoleCredentialsRequest, err := request.NewGetFleetRoleCredentialsBuilder(
+//		"arn:aws:iam::123456789012:role/service-role/exampleGameLiftAction",
+//	).Build()
 //	credentials, err := server.GetFleetRoleCredentials(getFleetRoleCredentialsRequest)
 func GetFleetRoleCredentials(
 	req request.GetFleetRoleCredentialsRequest,
@@ -392,6 +596,16 @@ func GetFleetRoleCredentials(
 	return srv.getFleetRoleCredentials(&req)
 }
 
+// UpdateAwsCredentials - replaces the AWS credentials passed to InitSDK with rotated ones.
+// The connect URL is signed with SigV4 again on every reconnect, so the new credentials are used from the next reconnect on.
+// Returns an error if the SDK was initialized with an auth token, with a CredentialsProvider or on CONTAINER compute,
+// where the provider or the container credentials endpoint rotates the credentials.
+//
+//	err := server.UpdateAwsCredentials(accessKey, secretKey, sessionToken)
+func UpdateAwsCredentials(accessKey, secretKey, sessionToken string) error {
+	return srv.updateAwsCredentials(accessKey, secretKey, sessionToken)
+}
+
 // Destroy - deletes the instance of the GameLift Game Server SDK on your resource.
 // This removes all state information, stops heartbeat communication with GameLift, stops game session management, and
 // closes any connections. Call this after you've use server.ProcessEnding()
@@ -414,3 +628,29 @@ func Destroy() error {
 	srv = nil
 	return nil
 }
+
+// ReplayRecording - calls the callbacks of params with the messages received from GameLift in a recording
+// of ServerParameters.RecordFile, in the order they were received, to reproduce lifecycle bugs in unit tests.
+// Returns when every callback returned, or a common.BadRequestException error if the recording is invalid.
+//
+// The messages are handled as if the process was ready, without InitSDK and ProcessReady.
+// The SDK calls of the callbacks, e.g. ActivateGameSession, are not replayed: they use the SDK initialized
+// by InitSDK, if any.
+//
+//	file, err := os.Open("gamelift.jsonl")
+//	...
+//	err = server.ReplayRecording(file, processParameters)
+func ReplayRecording(r io.Reader, params ProcessParameters) error {
+	replayState := &gameLiftServerState{
+		parameters: &params,
+		clock:      common.NewRealClock(),
+		metrics:    mtr,
+	}
+	replayState.isReadyProcess.Store(true)
+	replayManager, err := internal.NewReplayManager(r, replayState, lg, mtr, replayState.clock)
+	if err != nil {
+		return err
+	}
+	replayState.wsGameLift = replayManager
+	return replayManager.Connect("", "", "", "", "", nil)
+}
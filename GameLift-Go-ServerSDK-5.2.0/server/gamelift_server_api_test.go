@@ -7,10 +7,22 @@ package server
 
 import (
 	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model"
+	"aws/amazon-gamelift-go-sdk/model/request"
+	"aws/amazon-gamelift-go-sdk/model/result"
+	"aws/amazon-gamelift-go-sdk/server/internal"
 	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+	"errors"
 	"github.com/golang/mock/gomock"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 var testServerParams = ServerParameters{
@@ -115,6 +127,35 @@ func TestInitSDK(t *testing.T) {
 	Destroy()
 }
 
+func TestInitSDK_RetryAfterFailure(t *testing.T) {
+	// GIVEN
+	mockManager := newMockManager(t)
+	gomock.InOrder(
+		mockManager.
+			EXPECT().
+			Connect(testServerParams.WebSocketURL, testServerParams.ProcessID, testServerParams.HostID, testServerParams.FleetID, testServerParams.AuthToken, nil).
+			Return(errors.New("test error")),
+		mockManager.
+			EXPECT().
+			Connect(testServerParams.WebSocketURL, testServerParams.ProcessID, testServerParams.HostID, testServerParams.FleetID, testServerParams.AuthToken, nil).
+			Return(nil),
+	)
+	mockManager.EXPECT().Disconnect().Times(1)
+
+	// WHEN
+	firstErr := InitSDK(testServerParams)
+	secondErr := InitSDK(testServerParams)
+
+	// THEN
+	if firstErr == nil {
+		t.Fatal("Expected the first InitSDK to fail")
+	}
+	if secondErr != nil {
+		t.Fatal(secondErr)
+	}
+	Destroy()
+}
+
 func TestInitSDKFromEnvironment(t *testing.T) {
 	// GIVEN
 	if err := setEnvironmentVariables(); err != nil {
@@ -152,6 +193,138 @@ func TestInitSDKFromEnvironment_UndefinedEnv(t *testing.T) {
 	}
 }
 
+// GIVEN a record file WHEN the recorded transport writes a message THEN the message is appended to the file
+func TestNewRecorder(t *testing.T) {
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	transportMock := mock.NewMockITransport(ctrl)
+	params := testServerParams
+	params.RecordFile = filepath.Join(t.TempDir(), "gamelift.jsonl")
+
+	// EXPECT
+	transportMock.EXPECT().Write([]byte(`{"Action":"HeartbeatServerProcess"}`))
+
+	// WHEN
+	recorder, err := newRecorder(&params, common.NewRealClock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Transport(transportMock).Write([]byte(`{"Action":"HeartbeatServerProcess"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN
+	recording, err := os.ReadFile(params.RecordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(recording), `"direction":"out","data":{"Action":"HeartbeatServerProcess"}`) {
+		t.Fatalf("unexpected recording %s", recording)
+	}
+}
+
+// GIVEN a record file in a missing directory WHEN the recorder is created THEN a BadRequestException error is returned
+func TestNewRecorder_InvalidFile(t *testing.T) {
+	params := testServerParams
+	params.RecordFile = filepath.Join(t.TempDir(), "missing", "gamelift.jsonl")
+
+	_, err := newRecorder(&params, common.NewRealClock())
+
+	var gameLiftErr *common.GameLiftError
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.BadRequestException {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+// GIVEN the websocket client created by the first InitSDK WHEN InitSDK is called again after Destroy
+// THEN the client is reused and its transport and record file are not created again
+func TestInitSDK_AfterDestroy(t *testing.T) {
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	clientMock := mock.NewMockIWebSocketClient(ctrl)
+	manager = nil
+	wsClient = clientMock
+	t.Cleanup(func() { wsClient = nil })
+	params := testServerParams
+	// InitSDK fails if it opens the record file again
+	params.RecordFile = filepath.Join(t.TempDir(), "missing", "gamelift.jsonl")
+
+	// EXPECT
+	clientMock.EXPECT().Connect(gomock.Any(), nil).Times(2)
+	clientMock.EXPECT().AddHandler(gomock.Any(), gomock.Any()).Times(8)
+	clientMock.EXPECT().SetUnknownMessageHandler(gomock.Any()).Times(2)
+	clientMock.EXPECT().Close().Times(2)
+
+	// WHEN
+	for i := 0; i < 2; i++ {
+		if err := InitSDK(params); err != nil {
+			t.Fatal(err)
+		}
+		if err := Destroy(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// THEN
+	if wsClient != clientMock {
+		t.Fatal("the websocket client was created again")
+	}
+}
+
+// GIVEN a recording of a game session lifecycle WHEN it is replayed
+// THEN the callbacks receive the recorded messages in order without InitSDK
+func TestReplayRecording(t *testing.T) {
+	// GIVEN
+	recording := `{"time":"2023-01-02T03:04:05Z","direction":"connect","url":"wss://example.test?Authorization=REDACTED"}
+{"time":"2023-01-02T03:04:06Z","direction":"in","data":{"Action":"CreateGameSession","GameSessionId":"gsess-1","Port":7777}}
+{"time":"2023-01-02T03:04:06Z","direction":"out","data":{"Action":"ActivateGameSession","GameSessionId":"gsess-1"}}
+{"time":"2023-01-02T03:04:07Z","direction":"in","data":{"Action":"UpdateGameSession","GameSession":{"GameSessionId":"gsess-1"},"UpdateReason":"MATCHMAKING_DATA_UPDATED"}}
+{"time":"2023-01-02T03:04:08Z","direction":"in","data":{"Action":"RefreshConnection","RefreshConnectionEndpoint":"wss://example.test","AuthToken":"REDACTED"}}
+{"time":"2023-01-02T03:04:09Z","direction":"in","data":{"Action":"TerminateProcess","TerminationTime":1700000000000}}
+`
+	var mtx sync.Mutex
+	var callbacks []string
+	record := func(callback string) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		callbacks = append(callbacks, callback)
+	}
+	params := ProcessParameters{
+		OnStartGameSession: func(session model.GameSession) {
+			record("OnStartGameSession " + session.GameSessionID)
+		},
+		OnUpdateGameSession: func(update model.UpdateGameSession) {
+			record("OnUpdateGameSession " + update.UpdateReason.String())
+		},
+		OnProcessTerminate: func() {
+			record("OnProcessTerminate")
+		},
+	}
+
+	// WHEN
+	err := ReplayRecording(strings.NewReader(recording), params)
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The lifecycle messages of the game session are handled in order, TerminateProcess may be handled at any time
+	sort.Strings(callbacks)
+	expected := []string{"OnProcessTerminate", "OnStartGameSession gsess-1", "OnUpdateGameSession MATCHMAKING_DATA_UPDATED"}
+	if !reflect.DeepEqual(callbacks, expected) {
+		t.Fatalf("unexpected callbacks %q, want %q", callbacks, expected)
+	}
+}
+
+// GIVEN an invalid recording WHEN it is replayed THEN a BadRequestException error is returned
+func TestReplayRecording_Invalid(t *testing.T) {
+	err := ReplayRecording(strings.NewReader(`{"direction":`), ProcessParameters{})
+
+	if !errors.Is(err, common.ErrBadRequestException) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
 func TestDestroy(t *testing.T) {
 	// GIVEN
 	mockManager := newMockManager(t)
@@ -174,3 +347,243 @@ func TestDestroy(t *testing.T) {
 		t.Fatal("Server should be uninitialized")
 	}
 }
+
+// initReadyProcess initializes the SDK with a mock manager and marks the process as ready.
+func initReadyProcess(t *testing.T) *mock.MockIGameLiftManager {
+	mockManager := newMockManager(t)
+	mockSuccessfulConnect(mockManager, 1)
+	mockManager.EXPECT().Disconnect().Times(1)
+	if err := InitSDK(testServerParams); err != nil {
+		t.Fatal(err)
+	}
+	state.isReadyProcess.Store(true)
+	t.Cleanup(func() {
+		if err := Destroy(); err != nil {
+			t.Error(err)
+		}
+	})
+	return mockManager
+}
+
+// playerSessionsPage is an expected DescribePlayerSessions call and the page returned by it.
+type playerSessionsPage struct {
+	nextToken    string
+	limit        int
+	response     result.DescribePlayerSessionsResult
+	err          error
+	expectFilter string
+}
+
+func newPlayerSessions(ids ...string) []model.PlayerSession {
+	playerSessions := make([]model.PlayerSession, 0, len(ids))
+	for _, id := range ids {
+		playerSessions = append(playerSessions, model.PlayerSession{PlayerSessionID: id})
+	}
+	return playerSessions
+}
+
+// newGameSessionPlayerSessionsRequest returns a valid request of the player sessions of a game session.
+func newGameSessionPlayerSessionsRequest() request.DescribePlayerSessionsRequest {
+	req := request.NewDescribePlayerSessions()
+	req.GameSessionID = "test-game-session-id"
+	return req
+}
+
+// expectPlayerSessionsPages expects the DescribePlayerSessions calls in order
+// and checks that every call has its own request ID.
+func expectPlayerSessionsPages(t *testing.T, mockManager *mock.MockIGameLiftManager, pages ...playerSessionsPage) {
+	requestIDs := make(map[string]bool)
+	calls := make([]*gomock.Call, 0, len(pages))
+	for i := range pages {
+		page := pages[i]
+		call := mockManager.
+			EXPECT().
+			HandleRequest(gomock.Any(), gomock.Any(), common.ServiceCallTimeoutDefault).
+			DoAndReturn(func(req internal.MessageGetter, resp any, _ time.Duration) error {
+				r := req.(*request.DescribePlayerSessionsRequest)
+				// The manager validates the requests before sending them
+				if err := r.Validate(); err != nil {
+					t.Errorf("invalid page request: %s", err)
+				}
+				if r.NextToken != page.nextToken || r.Limit != page.limit {
+					t.Errorf("unexpected page request: NextToken=%q Limit=%d", r.NextToken, r.Limit)
+				}
+				if r.PlayerSessionStatusFilter != page.expectFilter {
+					t.Errorf("unexpected status filter: %q", r.PlayerSessionStatusFilter)
+				}
+				if requestIDs[r.RequestID] {
+					t.Errorf("request ID %s is reused", r.RequestID)
+				}
+				requestIDs[r.RequestID] = true
+				*resp.(*result.DescribePlayerSessionsResult) = page.response
+				return page.err
+			})
+		calls = append(calls, call)
+	}
+	gomock.InOrder(calls...)
+}
+
+func TestDescribePlayerSessionsPages(t *testing.T) {
+	// GIVEN
+	mockManager := initReadyProcess(t)
+	expectPlayerSessionsPages(t, mockManager,
+		playerSessionsPage{limit: 2, response: result.DescribePlayerSessionsResult{
+			NextToken: "token-1", PlayerSessions: newPlayerSessions("psess-1", "psess-2"),
+		}},
+		playerSessionsPage{nextToken: "token-1", limit: 2, response: result.DescribePlayerSessionsResult{
+			NextToken: "token-2", PlayerSessions: newPlayerSessions("psess-3", "psess-4"),
+		}},
+		playerSessionsPage{nextToken: "token-2", limit: 2, response: result.DescribePlayerSessionsResult{
+			PlayerSessions: newPlayerSessions("psess-5"),
+		}},
+	)
+	req := newGameSessionPlayerSessionsRequest()
+	req.Limit = 2
+
+	// WHEN
+	var ids []string
+	var lastPages []bool
+	err := DescribePlayerSessionsPages(req, func(page result.DescribePlayerSessionsResult, lastPage bool) bool {
+		for _, playerSession := range page.PlayerSessions {
+			ids = append(ids, playerSession.PlayerSessionID)
+		}
+		lastPages = append(lastPages, lastPage)
+		return true
+	})
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"psess-1", "psess-2", "psess-3", "psess-4", "psess-5"}; !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("Expected %v but got %v", expected, ids)
+	}
+	if expected := []bool{false, false, true}; !reflect.DeepEqual(lastPages, expected) {
+		t.Fatalf("Expected %v but got %v", expected, lastPages)
+	}
+}
+
+func TestDescribePlayerSessionsPages_Stop(t *testing.T) {
+	// GIVEN
+	mockManager := initReadyProcess(t)
+	expectPlayerSessionsPages(t, mockManager,
+		playerSessionsPage{response: result.DescribePlayerSessionsResult{
+			NextToken: "token-1", PlayerSessions: newPlayerSessions("psess-1"),
+		}},
+	)
+
+	// WHEN
+	pages := 0
+	err := DescribePlayerSessionsPages(newGameSessionPlayerSessionsRequest(), func(result.DescribePlayerSessionsResult, bool) bool {
+		pages++
+		return false
+	})
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, 1, pages)
+}
+
+func TestDescribeAllPlayerSessions_MaxResults(t *testing.T) {
+	// GIVEN
+	mockManager := initReadyProcess(t)
+	expectPlayerSessionsPages(t, mockManager,
+		playerSessionsPage{limit: 2, response: result.DescribePlayerSessionsResult{
+			NextToken: "token-1", PlayerSessions: newPlayerSessions("psess-1", "psess-2"),
+		}},
+		// The last page requests only the missing player sessions
+		playerSessionsPage{nextToken: "token-1", limit: 1, response: result.DescribePlayerSessionsResult{
+			NextToken: "token-2", PlayerSessions: newPlayerSessions("psess-3"),
+		}},
+	)
+	req := newGameSessionPlayerSessionsRequest()
+	req.Limit = 2
+
+	// WHEN
+	playerSessions, err := DescribeAllPlayerSessions(req, 3)
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := newPlayerSessions("psess-1", "psess-2", "psess-3"); !reflect.DeepEqual(playerSessions, expected) {
+		t.Fatalf("Expected %v but got %v", expected, playerSessions)
+	}
+}
+
+func TestDescribeAllPlayerSessions_MaxResultsAbovePageLimit(t *testing.T) {
+	// GIVEN
+	mockManager := initReadyProcess(t)
+	ids := make([]string, common.MaxPlayerSessions)
+	for i := range ids {
+		ids[i] = "psess-" + strconv.Itoa(i)
+	}
+	expectPlayerSessionsPages(t, mockManager,
+		playerSessionsPage{limit: common.MaxPlayerSessions, response: result.DescribePlayerSessionsResult{
+			NextToken: "token-1", PlayerSessions: newPlayerSessions(ids...),
+		}},
+		playerSessionsPage{nextToken: "token-1", limit: 476, response: result.DescribePlayerSessionsResult{
+			PlayerSessions: newPlayerSessions("psess-last"),
+		}},
+	)
+
+	// WHEN
+	playerSessions, err := DescribeAllPlayerSessions(newGameSessionPlayerSessionsRequest(), 1500)
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, common.MaxPlayerSessions+1, len(playerSessions))
+}
+
+func TestDescribeAllPlayerSessions_Error(t *testing.T) {
+	// GIVEN
+	mockManager := initReadyProcess(t)
+	expectedErr := common.NewGameLiftError(common.InternalServiceException, "", "")
+	expectPlayerSessionsPages(t, mockManager,
+		playerSessionsPage{response: result.DescribePlayerSessionsResult{
+			NextToken: "token-1", PlayerSessions: newPlayerSessions("psess-1"),
+		}},
+		playerSessionsPage{nextToken: "token-1", err: expectedErr},
+	)
+
+	// WHEN
+	_, err := DescribeAllPlayerSessions(newGameSessionPlayerSessionsRequest(), 0)
+
+	// THEN
+	if err != expectedErr {
+		t.Fatalf("Expected %v but got %v", expectedErr, err)
+	}
+}
+
+func TestListActivePlayerSessions(t *testing.T) {
+	// GIVEN
+	mockManager := initReadyProcess(t)
+	mockManager.
+		EXPECT().
+		HandleRequest(ignoreRequestID(&request.DescribePlayerSessionsRequest{
+			Message:                   request.NewDescribePlayerSessions().Message,
+			GameSessionID:             "test-game-session-id",
+			PlayerSessionStatusFilter: "ACTIVE",
+		}), gomock.Any(), common.ServiceCallTimeoutDefault).
+		DoAndReturn(func(_ internal.MessageGetter, resp any, _ time.Duration) error {
+			*resp.(*result.DescribePlayerSessionsResult) = result.DescribePlayerSessionsResult{
+				PlayerSessions: newPlayerSessions("psess-1", "psess-2"),
+			}
+			return nil
+		})
+
+	// WHEN
+	playerSessions, err := ListActivePlayerSessions("test-game-session-id")
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := newPlayerSessions("psess-1", "psess-2"); !reflect.DeepEqual(playerSessions, expected) {
+		t.Fatalf("Expected %v but got %v", expected, playerSessions)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package internal
+
+import (
+	"runtime/debug"
+	"sync"
+
+	"aws/amazon-gamelift-go-sdk/model/message"
+	"aws/amazon-gamelift-go-sdk/server/log"
+	"aws/amazon-gamelift-go-sdk/server/metrics"
+)
+
+// DispatcherConfig - limits of the inbound message dispatcher.
+//
+//   - Workers - max number of unordered messages, such as unknown messages, handled at the same time.
+//     The other unordered messages wait in memory for a free worker.
+//   - QueueSize - number of lifecycle messages of one game session waiting for the previous ones
+//     above which the queue is reported as blocked.
+//
+// The websocket reader never waits for the dispatcher: a handler may be waiting for a response
+// that only the reader can deliver, so the messages above the limits are queued and counted
+// with the DispatcherBlocked metric instead.
+type DispatcherConfig struct {
+	Workers   int
+	QueueSize int
+}
+
+// dispatcher - runs the handlers of the inbound messages.
+// Lifecycle messages of the same game session run sequentially in the order they were received,
+// while the other messages run on a bounded pool of goroutines.
+// A panic of a handler is recovered, logged and counted.
+//
+// Responses to requests are not dispatched, unlike the other messages: the reader passes them
+// to the waiting requests directly, because the handlers of lifecycle messages and the user callbacks
+// wait for them, and a response queued behind such a handler would never be delivered.
+type dispatcher struct {
+	log     log.ILogger
+	metrics metrics.IMetrics
+
+	queueSize int
+	workers   int
+
+	mtx   sync.Mutex
+	lanes map[string]*dispatcherLane
+	// busy - number of running workers, guarded by mtx
+	busy int
+	// backlog - unordered messages waiting for a free worker, guarded by mtx
+	backlog []func()
+
+	running sync.WaitGroup
+}
+
+// dispatcherLane - the queue of the lifecycle messages of one game session, drained by one goroutine.
+type dispatcherLane struct {
+	// queue - the running message followed by the waiting ones, guarded by dispatcher.mtx
+	queue []func()
+}
+
+func newDispatcher(l log.ILogger, m metrics.IMetrics, cfg DispatcherConfig) *dispatcher {
+	return &dispatcher{
+		log:       l,
+		metrics:   m,
+		queueSize: cfg.QueueSize,
+		workers:   cfg.Workers,
+		lanes:     make(map[string]*dispatcherLane),
+	}
+}
+
+// dispatchOrdered - runs handler after all previously dispatched handlers with the same key. Never blocks.
+func (d *dispatcher) dispatchOrdered(action message.MessageAction, key string, handler func()) {
+	d.running.Add(1)
+	task := func() { d.run(action, handler) }
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	lane, ok := d.lanes[key]
+	if !ok {
+		lane = &dispatcherLane{}
+		d.lanes[key] = lane
+		go d.drain(key, lane)
+	}
+	lane.queue = append(lane.queue, task)
+	d.metrics.Observe(metrics.DispatcherQueueDepth, float64(len(lane.queue)))
+	if waiting := len(lane.queue) - 1; waiting > d.queueSize {
+		d.metrics.IncrCounter(metrics.DispatcherBlocked, 1)
+		d.log.Debugf("Queue of %s messages for %q is full, %d messages are waiting", action, key, waiting)
+	}
+}
+
+// dispatch - runs handler on the pool. Never blocks, the handler waits for a free worker if all are busy.
+func (d *dispatcher) dispatch(action message.MessageAction, handler func()) {
+	d.running.Add(1)
+	task := func() { d.run(action, handler) }
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if d.busy < d.workers {
+		d.busy++
+		go d.work(task)
+		return
+	}
+	d.backlog = append(d.backlog, task)
+	d.metrics.IncrCounter(metrics.DispatcherBlocked, 1)
+	d.log.Debugf("All workers are busy, %s waits for a free worker", action)
+}
+
+// drain - runs the handlers of the lane one by one, and stops when no message is pending.
+func (d *dispatcher) drain(key string, lane *dispatcherLane) {
+	for {
+		d.mtx.Lock()
+		task := lane.queue[0]
+		d.mtx.Unlock()
+
+		task()
+
+		d.mtx.Lock()
+		lane.queue[0] = nil
+		lane.queue = lane.queue[1:]
+		if len(lane.queue) == 0 {
+			delete(d.lanes, key)
+			d.mtx.Unlock()
+			return
+		}
+		d.mtx.Unlock()
+	}
+}
+
+// work - runs task and then the waiting unordered messages, and stops when none is left.
+func (d *dispatcher) work(task func()) {
+	for task != nil {
+		task()
+
+		d.mtx.Lock()
+		task = nil
+		if len(d.backlog) > 0 {
+			task = d.backlog[0]
+			d.backlog[0] = nil
+			d.backlog = d.backlog[1:]
+		} else {
+			d.busy--
+		}
+		d.mtx.Unlock()
+	}
+}
+
+func (d *dispatcher) run(action message.MessageAction, handler func()) {
+	defer d.running.Done()
+	defer func() {
+		if r := recover(); r != nil {
+			d.metrics.IncrCounter(metrics.DispatcherPanics, 1)
+			d.log.Errorf("Recovered from a panic in the handler of %s: %v\n%s", action, r, debug.Stack())
+		}
+	}()
+	handler()
+}
+
+// wait - waits until all dispatched handlers return.
+func (d *dispatcher) wait() {
+	d.running.Wait()
+}
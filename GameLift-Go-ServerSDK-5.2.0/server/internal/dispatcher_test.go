@@ -0,0 +1,162 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package internal_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/goleak"
+
+	"aws/amazon-gamelift-go-sdk/model/message"
+	"aws/amazon-gamelift-go-sdk/server/internal"
+	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+	"aws/amazon-gamelift-go-sdk/server/metrics"
+)
+
+// GIVEN a slow handler of a game session WHEN more messages are dispatched
+// THEN the messages of the game session are handled in order and the other game sessions are not blocked
+func TestDispatcherOrdered(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	metricsMock := mock.NewMockIMetrics(ctrl)
+	metricsMock.EXPECT().Observe(metrics.DispatcherQueueDepth, gomock.Any()).AnyTimes()
+	d := internal.NewDispatcher(mock.NewTestLogger(t, ctrl), metricsMock, internal.TestDispatcherConfig)
+
+	var mtx sync.Mutex
+	var handled []string
+	record := func(name string) func() {
+		return func() {
+			mtx.Lock()
+			defer mtx.Unlock()
+			handled = append(handled, name)
+		}
+	}
+	release := make(chan struct{})
+	otherHandled := make(chan struct{})
+
+	// WHEN
+	d.DispatchOrdered(message.CreateGameSession, "gsess-1", func() {
+		<-release
+		record("create-1")()
+	})
+	d.DispatchOrdered(message.UpdateGameSession, "gsess-1", record("update-1"))
+	d.DispatchOrdered(message.CreateGameSession, "gsess-2", func() {
+		record("create-2")()
+		close(otherHandled)
+	})
+
+	// THEN gsess-2 is handled while gsess-1 waits for the release
+	<-otherHandled
+	close(release)
+	d.Wait()
+	expected := []string{"create-2", "create-1", "update-1"}
+	if !reflect.DeepEqual(handled, expected) {
+		t.Fatalf("unexpected order %v, want %v", handled, expected)
+	}
+}
+
+// GIVEN all workers busy WHEN a message is dispatched THEN the dispatch returns without waiting,
+// the message is counted and handled when a worker is free
+func TestDispatcherPoolBackpressure(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	metricsMock := mock.NewMockIMetrics(ctrl)
+	d := internal.NewDispatcher(mock.NewTestLogger(t, ctrl), metricsMock, internal.DispatcherConfig{Workers: 1, QueueSize: 1})
+
+	release := make(chan struct{})
+	d.Dispatch(message.DescribePlayerSessions, func() { <-release })
+
+	// EXPECT
+	metricsMock.EXPECT().IncrCounter(metrics.DispatcherBlocked, int64(1)).Times(2)
+
+	// WHEN
+	handled := make(chan int, 2)
+	d.Dispatch(message.DescribePlayerSessions, func() { handled <- 1 })
+	d.Dispatch(message.DescribePlayerSessions, func() { handled <- 2 })
+
+	// THEN the only worker is still busy, so the queued messages cannot have run
+	select {
+	case <-handled:
+		t.Fatal("the message was handled while all workers are busy")
+	default:
+	}
+	close(release)
+	d.Wait()
+	if first, second := <-handled, <-handled; first != 1 || second != 2 {
+		t.Fatalf("unexpected order %d, %d", first, second)
+	}
+}
+
+// GIVEN a game session with a blocked handler WHEN more messages than the queue size are dispatched
+// THEN the dispatch returns without waiting, the overflow is counted and all messages are handled in order
+func TestDispatcherLaneOverflow(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	metricsMock := mock.NewMockIMetrics(ctrl)
+	metricsMock.EXPECT().Observe(metrics.DispatcherQueueDepth, gomock.Any()).AnyTimes()
+	d := internal.NewDispatcher(mock.NewTestLogger(t, ctrl), metricsMock, internal.DispatcherConfig{Workers: 1, QueueSize: 1})
+
+	release := make(chan struct{})
+	var handled []int
+	d.DispatchOrdered(message.CreateGameSession, "gsess-1", func() { <-release })
+
+	// EXPECT
+	metricsMock.EXPECT().IncrCounter(metrics.DispatcherBlocked, int64(1)).Times(2)
+
+	// WHEN
+	for i := 1; i <= 3; i++ {
+		i := i
+		d.DispatchOrdered(message.UpdateGameSession, "gsess-1", func() { handled = append(handled, i) })
+	}
+
+	// THEN
+	close(release)
+	d.Wait()
+	expected := []int{1, 2, 3}
+	if !reflect.DeepEqual(handled, expected) {
+		t.Fatalf("unexpected order %v, want %v", handled, expected)
+	}
+}
+
+// GIVEN a panicking handler WHEN it is dispatched THEN the panic is recovered, logged and counted,
+// and the next messages of the game session are handled
+func TestDispatcherRecoversPanic(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	logger := mock.NewTestLogger(t, ctrl)
+	metricsMock := mock.NewMockIMetrics(ctrl)
+	d := internal.NewDispatcher(logger, metricsMock, internal.TestDispatcherConfig)
+
+	// EXPECT
+	metricsMock.EXPECT().Observe(metrics.DispatcherQueueDepth, gomock.Any()).AnyTimes()
+	metricsMock.EXPECT().IncrCounter(metrics.DispatcherPanics, int64(1)).Times(2)
+	logger.EXPECT().
+		Errorf("Recovered from a panic in the handler of %s: %v\n%s", gomock.Any(), "test panic", gomock.Any()).
+		Times(2)
+
+	// WHEN
+	handled := false
+	d.DispatchOrdered(message.CreateGameSession, "gsess-1", func() { panic("test panic") })
+	d.DispatchOrdered(message.UpdateGameSession, "gsess-1", func() { handled = true })
+	d.Dispatch(message.DescribePlayerSessions, func() { panic("test panic") })
+	d.Wait()
+
+	// THEN
+	if !handled {
+		t.Fatal("the message after the panic was not handled")
+	}
+}
@@ -6,18 +6,52 @@
 package internal
 
 import (
+	"aws/amazon-gamelift-go-sdk/model/message"
 	"aws/amazon-gamelift-go-sdk/server/internal/transport"
 	"aws/amazon-gamelift-go-sdk/server/log"
+	"aws/amazon-gamelift-go-sdk/server/metrics"
 )
 
 type WebsocketClient = websocketClient
 
 // Init expose access private init method for testing purposes
 func (c *WebsocketClient) Init(transport transport.ITransport, logger log.ILogger) {
-	c.init(transport, logger)
+	c.init(transport, logger, metrics.GetDefaultMetrics(), TestDispatcherConfig)
 }
 
-// RunReadHandler expose access private readHandler method for testing purposes
+// RunReadHandler expose access private readHandler method for testing purposes,
+// it returns when the message is handled.
 func (c *WebsocketClient) RunReadHandler(data []byte) {
 	c.readHandler(data)
+	c.dispatcher.wait()
+}
+
+// WaitHandlers waits until the handlers of the received messages return
+func (c *WebsocketClient) WaitHandlers() {
+	c.dispatcher.wait()
+}
+
+// TestDispatcherConfig - dispatcher limits of the tests.
+var TestDispatcherConfig = DispatcherConfig{Workers: 4, QueueSize: 4}
+
+type Dispatcher = dispatcher
+
+// NewDispatcher expose access private newDispatcher function for testing purposes
+func NewDispatcher(l log.ILogger, m metrics.IMetrics, cfg DispatcherConfig) *Dispatcher {
+	return newDispatcher(l, m, cfg)
+}
+
+// DispatchOrdered expose access private dispatchOrdered method for testing purposes
+func (d *Dispatcher) DispatchOrdered(action message.MessageAction, key string, handler func()) {
+	d.dispatchOrdered(action, key, handler)
+}
+
+// Dispatch expose access private dispatch method for testing purposes
+func (d *Dispatcher) Dispatch(action message.MessageAction, handler func()) {
+	d.dispatch(action, handler)
+}
+
+// Wait expose access private wait method for testing purposes
+func (d *Dispatcher) Wait() {
+	d.wait()
 }
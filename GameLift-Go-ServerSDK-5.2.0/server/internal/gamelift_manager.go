@@ -12,6 +12,7 @@ import (
 
 	"aws/amazon-gamelift-go-sdk/common"
 	"aws/amazon-gamelift-go-sdk/model/message"
+	"aws/amazon-gamelift-go-sdk/server/internal/transport"
 	"aws/amazon-gamelift-go-sdk/server/log"
 )
 
@@ -19,7 +20,8 @@ import (
 //
 //go:generate mockgen -destination ./mock/manager.go -package=mock . IGameLiftManager
 type IGameLiftManager interface {
-	Connect(websocketURL, processID, hostID, fleetID, authToken string, sigV4QueryParameters map[string]string) error
+	// Connect - connects with authToken if it is not empty, otherwise with the URL signed by signer on every connection attempt.
+	Connect(websocketURL, processID, hostID, fleetID, authToken string, signer transport.URLSigner) error
 	Disconnect() error
 	SendMessage(msg any) error
 	HandleRequest(request MessageGetter, response any, timeout time.Duration) error
@@ -29,26 +31,29 @@ type gameLiftManager struct {
 	handlers IGameLiftMessageHandler
 	client   IWebSocketClient
 	lg       log.ILogger
+	clock    common.Clock
 }
 
 func GetGameLiftManager(
 	handlers IGameLiftMessageHandler,
 	client IWebSocketClient,
 	lg log.ILogger,
+	clock common.Clock,
 ) IGameLiftManager {
 	gamelift := &gameLiftManager{
 		handlers: handlers,
 		client:   client,
 		lg:       lg,
+		clock:    clock,
 	}
 	return gamelift
 }
 
-func (manager *gameLiftManager) Connect(websocketURL, processID, hostID, fleetID, authToken string, sigV4QueryParameters map[string]string) error {
-	manager.lg.Debugf("Connecting to GameLift websocket server. Websocket URL: %s, processId: %s, hostId: %s, fleetId: %s", websocketURL, processID, hostID, fleetID)
+// NewConnectURL - returns the URL of the websocket connection to GameLift, with authToken if it is not empty.
+func NewConnectURL(websocketURL, processID, hostID, fleetID, authToken string) (*url.URL, error) {
 	connectURL, err := url.Parse(websocketURL)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	params := url.Values{}
 	params.Add(common.PidKey, processID)
@@ -58,23 +63,37 @@ func (manager *gameLiftManager) Connect(websocketURL, processID, hostID, fleetID
 	params.Add(common.FleetIDKey, fleetID)
 	if authToken != "" {
 		params.Add(common.AuthTokenKey, authToken)
-	} else if sigV4QueryParameters != nil {
-		for key, value := range sigV4QueryParameters {
-			params.Add(key, value)
-		}
 	}
 	connectURL.RawQuery = params.Encode()
+	return connectURL, nil
+}
+
+func (manager *gameLiftManager) Connect(websocketURL, processID, hostID, fleetID, authToken string, signer transport.URLSigner) error {
+	manager.lg.Debugf("Connecting to GameLift websocket server. Websocket URL: %s, processId: %s, hostId: %s, fleetId: %s", websocketURL, processID, hostID, fleetID)
+	connectURL, err := NewConnectURL(websocketURL, processID, hostID, fleetID, authToken)
+	if err != nil {
+		return err
+	}
+	if authToken != "" {
+		signer = nil
+	}
 
-	if err := manager.client.Connect(connectURL); err != nil {
+	if err := manager.client.Connect(connectURL, signer); err != nil {
 		return err
 	}
 
+	manager.addHandlers()
+
+	return nil
+}
+
+// addHandlers - passes the messages of GameLift received by the client to the handlers.
+func (manager *gameLiftManager) addHandlers() {
 	manager.client.AddHandler(message.CreateGameSession, manager.onStartGameSession)
 	manager.client.AddHandler(message.UpdateGameSession, manager.onUpdateGameSession)
 	manager.client.AddHandler(message.RefreshConnection, manager.onRefreshConnection)
 	manager.client.AddHandler(message.TerminateProcess, manager.onTerminateProcess)
-
-	return nil
+	manager.client.SetUnknownMessageHandler(manager.onUnknownMessage)
 }
 
 func (manager *gameLiftManager) Disconnect() error {
@@ -84,24 +103,32 @@ func (manager *gameLiftManager) Disconnect() error {
 	return nil
 }
 
+// SendMessage - validates the message if it is a message.Validator and sends it without waiting for a response.
+// Returns a common.BadRequestException error without sending if it is invalid.
 func (manager *gameLiftManager) SendMessage(msg any) error {
+	if err := manager.validateRequest(msg); err != nil {
+		return err
+	}
 	return manager.client.SendMessage(msg)
 }
 
 // HandleRequest - send a request wait the response and parse it
-// return error if timeout was expired or send request failed or can not parse answer.
+// return error if the request is invalid, timeout was expired or send request failed or can not parse answer.
 func (manager *gameLiftManager) HandleRequest(request MessageGetter, response any, timeout time.Duration) error {
+	if err := manager.validateRequest(request); err != nil {
+		return err
+	}
 	respData := make(chan common.Outcome, 1)
 	if err := manager.client.SendRequest(request, respData); err != nil {
 		return err
 	}
 
-	expire := time.After(timeout)
+	expire := manager.clock.After(timeout)
 	select {
 	case <-expire:
 		manager.client.CancelRequest(request.GetMessage().RequestID)
 		manager.lg.Errorf("Response not received within time limit for request: %s", request.GetMessage().RequestID)
-		return common.NewGameLiftError(common.ServiceCallFailed, "", "")
+		return common.NewGameLiftErrorWithCause(common.ServiceCallFailed, "", "", common.ErrResponseTimeout)
 	case resultData := <-respData:
 		if resultData.Error != nil {
 			return resultData.Error
@@ -109,18 +136,44 @@ func (manager *gameLiftManager) HandleRequest(request MessageGetter, response an
 
 		if err := json.Unmarshal(resultData.Data, response); err != nil {
 			manager.lg.Errorf("Failed when try parse response data: %s", err.Error())
-			return common.NewGameLiftError(common.InternalServiceException, "", "")
+			return common.NewGameLiftErrorWithCause(common.InternalServiceException, "", "", err)
 		}
 		return nil
 	}
 }
 
+// validateRequest - returns the error of an outbound request which is a message.Validator.
+func (manager *gameLiftManager) validateRequest(msg any) error {
+	validator, ok := msg.(message.Validator)
+	if !ok {
+		return nil
+	}
+	if err := validator.Validate(); err != nil {
+		manager.lg.Warnf("Rejected request before sending: %s", err)
+		return err
+	}
+	return nil
+}
+
+// validMessage - returns false and logs the problems if a message from GameLift is invalid.
+// The message itself is not logged, as it may contain an auth token.
+func (manager *gameLiftManager) validMessage(msg message.Validator) bool {
+	if err := msg.Validate(); err != nil {
+		manager.lg.Warnf("Rejected malformed message from GameLift: %s", err)
+		return false
+	}
+	return true
+}
+
 func (manager *gameLiftManager) onStartGameSession(data []byte) {
 	var gameSession message.CreateGameSessionMessage
 	if err := json.Unmarshal(data, &gameSession); err != nil {
 		manager.lg.Warnf("Failed when try parse start game session message: %s", err.Error())
 		return
 	}
+	if !manager.validMessage(gameSession) {
+		return
+	}
 	manager.handlers.OnStartGameSession(message.NewGameSession(&gameSession))
 }
 
@@ -130,6 +183,9 @@ func (manager *gameLiftManager) onUpdateGameSession(data []byte) {
 		manager.lg.Warnf("Failed when try parse update game session message: %s", err.Error())
 		return
 	}
+	if !manager.validMessage(updateGameSession) {
+		return
+	}
 	manager.handlers.OnUpdateGameSession(
 		&updateGameSession.GameSession,
 		updateGameSession.UpdateReason,
@@ -143,6 +199,9 @@ func (manager *gameLiftManager) onTerminateProcess(data []byte) {
 		manager.lg.Warnf("Failed when try parse terminate process message: %s", err.Error())
 		return
 	}
+	if !manager.validMessage(terminateProcess) {
+		return
+	}
 	manager.handlers.OnTerminateProcess(terminateProcess.TerminationTime)
 }
 
@@ -152,5 +211,16 @@ func (manager *gameLiftManager) onRefreshConnection(data []byte) {
 		manager.lg.Warnf("Failed when try parse refresh connection message: %s", err.Error())
 		return
 	}
+	if !manager.validMessage(refreshConnection) {
+		return
+	}
 	manager.handlers.OnRefreshConnection(refreshConnection.RefreshConnectionEndpoint, refreshConnection.AuthToken)
 }
+
+// onUnknownMessage - passes the message to the handler. Its payload is logged at the debug level
+// with the known secrets redacted, as it may contain secrets of a newer GameLift service.
+func (manager *gameLiftManager) onUnknownMessage(action message.MessageAction, data []byte) {
+	manager.lg.Warnf("Received message with unknown action %q of %d bytes", action, len(data))
+	manager.lg.Debugf("Payload of the message with unknown action %q: %s", action, transport.Redact(data))
+	manager.handlers.OnUnknownMessage(action, data)
+}
@@ -20,6 +20,7 @@ import (
 	"aws/amazon-gamelift-go-sdk/model/result"
 	"aws/amazon-gamelift-go-sdk/server/internal"
 	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+	"aws/amazon-gamelift-go-sdk/server/internal/transport"
 
 	"github.com/golang/mock/gomock"
 	"go.uber.org/goleak"
@@ -43,8 +44,9 @@ func TestGameliftManagerHandleRequest_AuthTokenPassed(t *testing.T) {
 	gameliftMessageHandlerMock := mock.NewMockIGameLiftMessageHandler(ctrl)
 	websocketClientMock := mock.NewMockIWebSocketClient(ctrl)
 	logger := mock.NewTestLogger(t, ctrl)
+	clock := mock.NewFakeClock(time.Now())
 
-	gm := internal.GetGameLiftManager(gameliftMessageHandlerMock, websocketClientMock, logger)
+	gm := internal.GetGameLiftManager(gameliftMessageHandlerMock, websocketClientMock, logger, clock)
 
 	connectURL, err := url.Parse(websocketURL)
 	if err != nil {
@@ -62,13 +64,16 @@ func TestGameliftManagerHandleRequest_AuthTokenPassed(t *testing.T) {
 
 	websocketClientMock.
 		EXPECT().
-		Connect(connectURL)
+		Connect(connectURL, nil)
 
 	for _, actions := range []message.MessageAction{message.CreateGameSession, message.UpdateGameSession, message.RefreshConnection, message.TerminateProcess} {
 		websocketClientMock.
 			EXPECT().
 			AddHandler(actions, gomock.Not(gomock.Nil()))
 	}
+	websocketClientMock.
+		EXPECT().
+		SetUnknownMessageHandler(gomock.Not(gomock.Nil()))
 
 	if err := gm.Connect(websocketURL, processID, hostID, fleetID, authToken, nil); err != nil {
 		t.Fatal(err)
@@ -87,7 +92,6 @@ func TestGameliftManagerHandleRequest_AuthTokenPassed(t *testing.T) {
 			Action:    message.DescribePlayerSessions,
 			RequestID: "test-request-id",
 		},
-		PlayerID:        "test-player-id",
 		PlayerSessionID: "test-player-session-id",
 		NextToken:       "test-next-token",
 		Limit:           1,
@@ -150,10 +154,7 @@ func TestGameliftManagerHandleRequest_AuthTokenPassed(t *testing.T) {
 	websocketClientMock.
 		EXPECT().
 		SendRequest(req, gomock.Any()).
-		Do(func(req internal.MessageGetter, resp chan<- common.Outcome) error {
-			time.Sleep(time.Millisecond * 5)
-			return nil
-		})
+		Return(nil)
 
 	logger.
 		EXPECT().
@@ -164,6 +165,7 @@ func TestGameliftManagerHandleRequest_AuthTokenPassed(t *testing.T) {
 		EXPECT().
 		CancelRequest(req.RequestID)
 
+	expireAfter(clock, timeDuration)
 	err = gm.HandleRequest(req, &resp, timeDuration)
 	if err == nil {
 		t.Fatal(err)
@@ -186,8 +188,9 @@ func TestGameliftManagerHandleRequest_SigV4QueryParametersPassed(t *testing.T) {
 	gameliftMessageHandlerMock := mock.NewMockIGameLiftMessageHandler(ctrl)
 	websocketClientMock := mock.NewMockIWebSocketClient(ctrl)
 	logger := mock.NewTestLogger(t, ctrl)
+	clock := mock.NewFakeClock(time.Now())
 
-	gm := internal.GetGameLiftManager(gameliftMessageHandlerMock, websocketClientMock, logger)
+	gm := internal.GetGameLiftManager(gameliftMessageHandlerMock, websocketClientMock, logger, clock)
 
 	connectURL, err := url.Parse(websocketURL)
 	if err != nil {
@@ -209,22 +212,44 @@ func TestGameliftManagerHandleRequest_SigV4QueryParametersPassed(t *testing.T) {
 	params.Add(common.PidKey, processID)
 	params.Add(common.SdkLanguageKey, common.SdkLanguage)
 	params.Add(common.SdkVersionKey, common.SdkVersion)
-	for key, value := range sigV4QueryParameters {
-		params.Add(key, value)
-	}
 	connectURL.RawQuery = params.Encode()
+	signer := func(u *url.URL) (*url.URL, error) {
+		signed := *u
+		query := signed.Query()
+		for key, value := range sigV4QueryParameters {
+			query.Add(key, value)
+		}
+		signed.RawQuery = query.Encode()
+		return &signed, nil
+	}
 
 	websocketClientMock.
 		EXPECT().
-		Connect(connectURL)
+		Connect(connectURL, gomock.Not(gomock.Nil())).
+		DoAndReturn(func(u *url.URL, s transport.URLSigner) error {
+			// The signer is passed to the client, so every connection attempt is signed again
+			signed, err := s(u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, value := range sigV4QueryParameters {
+				if signed.Query().Get(key) != value {
+					t.Errorf("expected %s=%s in the signed URL %s", key, value, signed)
+				}
+			}
+			return nil
+		})
 
 	for _, actions := range []message.MessageAction{message.CreateGameSession, message.UpdateGameSession, message.RefreshConnection, message.TerminateProcess} {
 		websocketClientMock.
 			EXPECT().
 			AddHandler(actions, gomock.Not(gomock.Nil()))
 	}
+	websocketClientMock.
+		EXPECT().
+		SetUnknownMessageHandler(gomock.Not(gomock.Nil()))
 
-	if err := gm.Connect(websocketURL, processID, hostID, fleetID, "", sigV4QueryParameters); err != nil {
+	if err := gm.Connect(websocketURL, processID, hostID, fleetID, "", signer); err != nil {
 		t.Fatal(err)
 	}
 
@@ -241,7 +266,6 @@ func TestGameliftManagerHandleRequest_SigV4QueryParametersPassed(t *testing.T) {
 			Action:    message.DescribePlayerSessions,
 			RequestID: "test-request-id",
 		},
-		PlayerID:        "test-player-id",
 		PlayerSessionID: "test-player-session-id",
 		NextToken:       "test-next-token",
 		Limit:           1,
@@ -304,10 +328,7 @@ func TestGameliftManagerHandleRequest_SigV4QueryParametersPassed(t *testing.T) {
 	websocketClientMock.
 		EXPECT().
 		SendRequest(req, gomock.Any()).
-		Do(func(req internal.MessageGetter, resp chan<- common.Outcome) error {
-			time.Sleep(time.Millisecond * 5)
-			return nil
-		})
+		Return(nil)
 
 	logger.
 		EXPECT().
@@ -318,6 +339,7 @@ func TestGameliftManagerHandleRequest_SigV4QueryParametersPassed(t *testing.T) {
 		EXPECT().
 		CancelRequest(req.RequestID)
 
+	expireAfter(clock, timeDuration)
 	err = gm.HandleRequest(req, &resp, timeDuration)
 	if err == nil {
 		t.Fatal(err)
@@ -340,21 +362,19 @@ func TestGameliftManagerHandleRequest_AuthTokenAndSigV4QueryParametersPassed(t *
 	gameliftMessageHandlerMock := mock.NewMockIGameLiftMessageHandler(ctrl)
 	websocketClientMock := mock.NewMockIWebSocketClient(ctrl)
 	logger := mock.NewTestLogger(t, ctrl)
+	clock := mock.NewFakeClock(time.Now())
 
-	gm := internal.GetGameLiftManager(gameliftMessageHandlerMock, websocketClientMock, logger)
+	gm := internal.GetGameLiftManager(gameliftMessageHandlerMock, websocketClientMock, logger, clock)
 
 	connectURL, err := url.Parse(websocketURL)
 	if err != nil {
 		t.Fatalf("parse url: %s", err)
 	}
 
-	sigV4QueryParameters := map[string]string{
-		"Authorization":        "SigV4",
-		"X-Amz-Algorithm":      "AWS4-HMAC-SHA256",
-		"X-Amz-Credential":     "testAccessKey/20240805/us-east-1/gamelift/aws4_request",
-		"X-Amz-Date":           "20240805T100000Z",
-		"X-Amz-Signature":      "2601fe291f4b43a63f6ffb0e1d9085a1edbaa2a866c96511e153af3408bfe771",
-		"X-Amz-Security-Token": "testSessionToken",
+	// The auth token takes precedence over SigV4
+	signer := func(u *url.URL) (*url.URL, error) {
+		t.Error("the URL must not be signed when an auth token is passed")
+		return u, nil
 	}
 
 	params := url.Values{}
@@ -369,15 +389,18 @@ func TestGameliftManagerHandleRequest_AuthTokenAndSigV4QueryParametersPassed(t *
 
 	websocketClientMock.
 		EXPECT().
-		Connect(connectURL)
+		Connect(connectURL, nil)
 
 	for _, actions := range []message.MessageAction{message.CreateGameSession, message.UpdateGameSession, message.RefreshConnection, message.TerminateProcess} {
 		websocketClientMock.
 			EXPECT().
 			AddHandler(actions, gomock.Not(gomock.Nil()))
 	}
+	websocketClientMock.
+		EXPECT().
+		SetUnknownMessageHandler(gomock.Not(gomock.Nil()))
 
-	if err := gm.Connect(websocketURL, processID, hostID, fleetID, authToken, sigV4QueryParameters); err != nil {
+	if err := gm.Connect(websocketURL, processID, hostID, fleetID, authToken, signer); err != nil {
 		t.Fatal(err)
 	}
 
@@ -394,7 +417,6 @@ func TestGameliftManagerHandleRequest_AuthTokenAndSigV4QueryParametersPassed(t *
 			Action:    message.DescribePlayerSessions,
 			RequestID: "test-request-id",
 		},
-		PlayerID:        "test-player-id",
 		PlayerSessionID: "test-player-session-id",
 		NextToken:       "test-next-token",
 		Limit:           1,
@@ -457,10 +479,7 @@ func TestGameliftManagerHandleRequest_AuthTokenAndSigV4QueryParametersPassed(t *
 	websocketClientMock.
 		EXPECT().
 		SendRequest(req, gomock.Any()).
-		Do(func(req internal.MessageGetter, resp chan<- common.Outcome) error {
-			time.Sleep(time.Millisecond * 5)
-			return nil
-		})
+		Return(nil)
 
 	logger.
 		EXPECT().
@@ -471,6 +490,7 @@ func TestGameliftManagerHandleRequest_AuthTokenAndSigV4QueryParametersPassed(t *
 		EXPECT().
 		CancelRequest(req.RequestID)
 
+	expireAfter(clock, timeDuration)
 	err = gm.HandleRequest(req, &resp, timeDuration)
 	if err == nil {
 		t.Fatal(err)
@@ -493,15 +513,15 @@ func TestGameliftManagerHandleRequestError(t *testing.T) {
 	gameliftMessageHandlerMock := mock.NewMockIGameLiftMessageHandler(ctrl)
 	websocketClientMock := mock.NewMockIWebSocketClient(ctrl)
 	logger := mock.NewTestLogger(t, ctrl)
+	clock := mock.NewFakeClock(time.Now())
 
-	gm := internal.GetGameLiftManager(gameliftMessageHandlerMock, websocketClientMock, logger)
+	gm := internal.GetGameLiftManager(gameliftMessageHandlerMock, websocketClientMock, logger, clock)
 
 	req := &request.DescribePlayerSessionsRequest{
 		Message: message.Message{
 			Action:    message.DescribePlayerSessions,
 			RequestID: "test-request-id",
 		},
-		PlayerID:        "test-player-id",
 		PlayerSessionID: "test-player-session-id",
 		NextToken:       "test-next-token",
 		Limit:           1,
@@ -533,31 +553,26 @@ func TestGameliftManagerHandleRequest_Timeout_ReturnError(t *testing.T) {
 	gameliftMessageHandlerMock := mock.NewMockIGameLiftMessageHandler(ctrl)
 	websocketClientMock := mock.NewMockIWebSocketClient(ctrl)
 	logger := mock.NewTestLogger(t, ctrl)
-	gm := internal.GetGameLiftManager(gameliftMessageHandlerMock, websocketClientMock, logger)
+	clock := mock.NewFakeClock(time.Now())
+	gm := internal.GetGameLiftManager(gameliftMessageHandlerMock, websocketClientMock, logger, clock)
 
 	req := &request.DescribePlayerSessionsRequest{
 		Message: message.Message{
 			Action:    message.DescribePlayerSessions,
 			RequestID: "test-request-id",
 		},
-		PlayerID:        "test-player-id",
 		PlayerSessionID: "test-player-session-id",
 		NextToken:       "test-next-token",
 		Limit:           1,
 	}
 
 	// GIVEN
-	expectedError := common.NewGameLiftError(common.ServiceCallFailed, "", "")
 	const DesiredRequestTimeout = time.Duration(1) * time.Millisecond
-	const MockDelayInResponse = time.Duration(5) * time.Millisecond
 
 	websocketClientMock.
 		EXPECT().
 		SendRequest(req, gomock.Any()).
-		Do(func(req internal.MessageGetter, resp chan<- common.Outcome) error {
-			time.Sleep(MockDelayInResponse)
-			return nil
-		})
+		Return(nil)
 
 	websocketClientMock.
 		EXPECT().
@@ -569,10 +584,124 @@ func TestGameliftManagerHandleRequest_Timeout_ReturnError(t *testing.T) {
 		Do(func(format string, args ...any) { t.Logf(format, args...) })
 
 	// WHEN
+	expireAfter(clock, DesiredRequestTimeout)
 	err := gm.HandleRequest(req, nil, DesiredRequestTimeout)
 
 	// THEN
-	if err.Error() != expectedError.Error() {
-		t.Fatalf("unexpected error %s, want %s", err, expectedError)
+	if !errors.Is(err, common.ErrServiceCallFailed) || !errors.Is(err, common.ErrResponseTimeout) || !common.IsTimeout(err) {
+		t.Fatalf("unexpected error %s, want a ServiceCallFailed timeout", err)
 	}
 }
+
+// GIVEN an invalid request WHEN it is sent THEN a BadRequestException error is returned and nothing is sent
+func TestGameliftManager_InvalidRequest_NotSent(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	websocketClientMock := mock.NewMockIWebSocketClient(ctrl)
+	gm := internal.GetGameLiftManager(
+		mock.NewMockIGameLiftMessageHandler(ctrl),
+		websocketClientMock,
+		mock.NewTestLogger(t, ctrl),
+		mock.NewFakeClock(time.Now()),
+	)
+	req := request.NewDescribePlayerSessions()
+	req.GameSessionID = "gsess-1"
+	req.Limit = 2000
+
+	// WHEN
+	handleErr := gm.HandleRequest(&req, nil, time.Second)
+	sendErr := gm.SendMessage(request.NewActivateGameSession(""))
+
+	// THEN
+	for _, err := range []error{handleErr, sendErr} {
+		var gameLiftErr *common.GameLiftError
+		if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.BadRequestException {
+			t.Fatalf("unexpected error %v, want a BadRequestException", err)
+		}
+	}
+}
+
+// GIVEN a connected manager WHEN GameLift sends a CreateGameSession message without GameSessionId
+// THEN the message is rejected, and the next valid message is handled
+func TestGameliftManager_MalformedMessage_Rejected(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	gameliftMessageHandlerMock := mock.NewMockIGameLiftMessageHandler(ctrl)
+	websocketClientMock := mock.NewMockIWebSocketClient(ctrl)
+	gm := internal.GetGameLiftManager(
+		gameliftMessageHandlerMock,
+		websocketClientMock,
+		mock.NewTestLogger(t, ctrl),
+		mock.NewFakeClock(time.Now()),
+	)
+
+	handlers := make(map[message.MessageAction]func([]byte))
+	websocketClientMock.EXPECT().Connect(gomock.Any(), nil)
+	websocketClientMock.EXPECT().
+		AddHandler(gomock.Any(), gomock.Not(gomock.Nil())).
+		Do(func(action message.MessageAction, handler func([]byte)) { handlers[action] = handler }).
+		Times(4)
+	websocketClientMock.EXPECT().SetUnknownMessageHandler(gomock.Not(gomock.Nil()))
+	if err := gm.Connect(websocketURL, processID, hostID, fleetID, authToken, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// EXPECT
+	gameliftMessageHandlerMock.EXPECT().
+		OnStartGameSession(gomock.Any()).
+		Do(func(gameSession *model.GameSession) {
+			if gameSession.GameSessionID != "gsess-1" {
+				t.Errorf("unexpected game session %s", gameSession.GameSessionID)
+			}
+		})
+
+	// WHEN
+	handlers[message.CreateGameSession]([]byte(`{"Action":"CreateGameSession","Port":1122}`))
+	handlers[message.CreateGameSession]([]byte(`{"Action":"CreateGameSession","GameSessionId":"gsess-1","Port":1122}`))
+}
+
+// GIVEN a connected manager WHEN GameLift sends a message with an unknown action
+// THEN it is passed to the handler, and its action, size and payload with the secrets redacted are logged
+func TestGameliftManager_UnknownMessage_Logged(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	gameliftMessageHandlerMock := mock.NewMockIGameLiftMessageHandler(ctrl)
+	websocketClientMock := mock.NewMockIWebSocketClient(ctrl)
+	logger := mock.NewMockILogger(ctrl)
+	gm := internal.GetGameLiftManager(gameliftMessageHandlerMock, websocketClientMock, logger, mock.NewFakeClock(time.Now()))
+
+	var unknownHandler func(message.MessageAction, []byte)
+	websocketClientMock.EXPECT().Connect(gomock.Any(), nil)
+	websocketClientMock.EXPECT().AddHandler(gomock.Any(), gomock.Not(gomock.Nil())).AnyTimes()
+	websocketClientMock.EXPECT().
+		SetUnknownMessageHandler(gomock.Not(gomock.Nil())).
+		Do(func(handler func(message.MessageAction, []byte)) { unknownHandler = handler })
+	logger.EXPECT().Debugf(gomock.Any(), websocketURL, processID, hostID, fleetID)
+	if err := gm.Connect(websocketURL, processID, hostID, fleetID, authToken, nil); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(`{"Action":"FutureNotification","AuthToken":"test-secret"}`)
+
+	// EXPECT
+	logger.EXPECT().Warnf("Received message with unknown action %q of %d bytes", message.MessageAction("FutureNotification"), len(data))
+	logger.EXPECT().Debugf("Payload of the message with unknown action %q: %s", message.MessageAction("FutureNotification"),
+		[]byte(`{"Action":"FutureNotification","AuthToken":"REDACTED"}`))
+	gameliftMessageHandlerMock.EXPECT().OnUnknownMessage(message.MessageAction("FutureNotification"), data)
+
+	// WHEN
+	unknownHandler("FutureNotification", data)
+}
+
+// expireAfter advances the clock by d as soon as HandleRequest starts waiting for the response.
+func expireAfter(clock *mock.FakeClock, d time.Duration) {
+	go func() {
+		clock.BlockUntil(1)
+		clock.Advance(d)
+	}()
+}
@@ -11,6 +11,7 @@ import (
 
 	"aws/amazon-gamelift-go-sdk/common"
 	"aws/amazon-gamelift-go-sdk/model/message"
+	"aws/amazon-gamelift-go-sdk/server/internal/transport"
 )
 
 // IWebSocketClient - interface that manages a weboscket connection.
@@ -19,10 +20,13 @@ import (
 //go:generate mockgen -destination ./mock/client.go -package=mock . IWebSocketClient
 type IWebSocketClient interface {
 	io.Closer
-	Connect(url *url.URL) error
+	Connect(url *url.URL, signer transport.URLSigner) error
 	SendMessage(msg any) error
 	SendRequest(req MessageGetter, resp chan<- common.Outcome) error
 	AddHandler(action message.MessageAction, handler func([]byte))
+	// SetUnknownMessageHandler - sets the handler of the messages whose action is unknown to the SDK
+	// and which are not a response to a pending request.
+	SetUnknownMessageHandler(handler func(action message.MessageAction, data []byte))
 	CancelRequest(requestID string)
 }
 
@@ -7,6 +7,7 @@ package internal
 
 import (
 	"aws/amazon-gamelift-go-sdk/model"
+	"aws/amazon-gamelift-go-sdk/model/message"
 )
 
 // IGameLiftMessageHandler - async messages handlers from GameLift server or APIG.
@@ -21,4 +22,5 @@ type IGameLiftMessageHandler interface {
 	)
 	OnTerminateProcess(terminationTime int64)
 	OnRefreshConnection(refreshConnectionEndpoint, authToken string)
+	OnUnknownMessage(action message.MessageAction, data []byte)
 }
@@ -8,6 +8,7 @@ import (
 	common "aws/amazon-gamelift-go-sdk/common"
 	message "aws/amazon-gamelift-go-sdk/model/message"
 	internal "aws/amazon-gamelift-go-sdk/server/internal"
+	transport "aws/amazon-gamelift-go-sdk/server/internal/transport"
 	url "net/url"
 	reflect "reflect"
 
@@ -76,17 +77,17 @@ func (mr *MockIWebSocketClientMockRecorder) Close() *gomock.Call {
 }
 
 // Connect mocks base method.
-func (m *MockIWebSocketClient) Connect(arg0 *url.URL) error {
+func (m *MockIWebSocketClient) Connect(arg0 *url.URL, arg1 transport.URLSigner) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Connect", arg0)
+	ret := m.ctrl.Call(m, "Connect", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Connect indicates an expected call of Connect.
-func (mr *MockIWebSocketClientMockRecorder) Connect(arg0 interface{}) *gomock.Call {
+func (mr *MockIWebSocketClientMockRecorder) Connect(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*MockIWebSocketClient)(nil).Connect), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*MockIWebSocketClient)(nil).Connect), arg0, arg1)
 }
 
 // SendMessage mocks base method.
@@ -116,3 +117,15 @@ func (mr *MockIWebSocketClientMockRecorder) SendRequest(arg0, arg1 interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendRequest", reflect.TypeOf((*MockIWebSocketClient)(nil).SendRequest), arg0, arg1)
 }
+
+// SetUnknownMessageHandler mocks base method.
+func (m *MockIWebSocketClient) SetUnknownMessageHandler(arg0 func(message.MessageAction, []byte)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetUnknownMessageHandler", arg0)
+}
+
+// SetUnknownMessageHandler indicates an expected call of SetUnknownMessageHandler.
+func (mr *MockIWebSocketClientMockRecorder) SetUnknownMessageHandler(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUnknownMessageHandler", reflect.TypeOf((*MockIWebSocketClient)(nil).SetUnknownMessageHandler), arg0)
+}
@@ -6,6 +6,7 @@ package mock
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -89,6 +90,58 @@ func (mr *MockConnMockRecorder) SetCloseHandler(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCloseHandler", reflect.TypeOf((*MockConn)(nil).SetCloseHandler), arg0)
 }
 
+// SetPongHandler mocks base method.
+func (m *MockConn) SetPongHandler(arg0 func(string) error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPongHandler", arg0)
+}
+
+// SetPongHandler indicates an expected call of SetPongHandler.
+func (mr *MockConnMockRecorder) SetPongHandler(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPongHandler", reflect.TypeOf((*MockConn)(nil).SetPongHandler), arg0)
+}
+
+// SetReadDeadline mocks base method.
+func (m *MockConn) SetReadDeadline(arg0 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReadDeadline", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReadDeadline indicates an expected call of SetReadDeadline.
+func (mr *MockConnMockRecorder) SetReadDeadline(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReadDeadline", reflect.TypeOf((*MockConn)(nil).SetReadDeadline), arg0)
+}
+
+// SetReadLimit mocks base method.
+func (m *MockConn) SetReadLimit(arg0 int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetReadLimit", arg0)
+}
+
+// SetReadLimit indicates an expected call of SetReadLimit.
+func (mr *MockConnMockRecorder) SetReadLimit(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReadLimit", reflect.TypeOf((*MockConn)(nil).SetReadLimit), arg0)
+}
+
+// WriteControl mocks base method.
+func (m *MockConn) WriteControl(arg0 int, arg1 []byte, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteControl", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteControl indicates an expected call of WriteControl.
+func (mr *MockConnMockRecorder) WriteControl(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteControl", reflect.TypeOf((*MockConn)(nil).WriteControl), arg0, arg1, arg2)
+}
+
 // WriteMessage mocks base method.
 func (m *MockConn) WriteMessage(arg0 int, arg1 []byte) error {
 	m.ctrl.T.Helper()
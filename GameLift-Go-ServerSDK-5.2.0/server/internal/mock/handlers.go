@@ -6,6 +6,7 @@ package mock
 
 import (
 	model "aws/amazon-gamelift-go-sdk/model"
+	message "aws/amazon-gamelift-go-sdk/model/message"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -70,6 +71,18 @@ func (mr *MockIGameLiftMessageHandlerMockRecorder) OnTerminateProcess(arg0 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnTerminateProcess", reflect.TypeOf((*MockIGameLiftMessageHandler)(nil).OnTerminateProcess), arg0)
 }
 
+// OnUnknownMessage mocks base method.
+func (m *MockIGameLiftMessageHandler) OnUnknownMessage(arg0 message.MessageAction, arg1 []byte) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnUnknownMessage", arg0, arg1)
+}
+
+// OnUnknownMessage indicates an expected call of OnUnknownMessage.
+func (mr *MockIGameLiftMessageHandlerMockRecorder) OnUnknownMessage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnUnknownMessage", reflect.TypeOf((*MockIGameLiftMessageHandler)(nil).OnUnknownMessage), arg0, arg1)
+}
+
 // OnUpdateGameSession mocks base method.
 func (m *MockIGameLiftMessageHandler) OnUpdateGameSession(arg0 *model.GameSession, arg1 *model.UpdateReason, arg2 string) {
 	m.ctrl.T.Helper()
@@ -6,6 +6,7 @@ package mock
 
 import (
 	internal "aws/amazon-gamelift-go-sdk/server/internal"
+	transport "aws/amazon-gamelift-go-sdk/server/internal/transport"
 	reflect "reflect"
 	time "time"
 
@@ -36,7 +37,7 @@ func (m *MockIGameLiftManager) EXPECT() *MockIGameLiftManagerMockRecorder {
 }
 
 // Connect mocks base method.
-func (m *MockIGameLiftManager) Connect(arg0, arg1, arg2, arg3, arg4 string, arg5 map[string]string) error {
+func (m *MockIGameLiftManager) Connect(arg0, arg1, arg2, arg3, arg4 string, arg5 transport.URLSigner) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Connect", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(error)
@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: aws/amazon-gamelift-go-sdk/server/metrics (interfaces: IMetrics)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIMetrics is a mock of IMetrics interface.
+type MockIMetrics struct {
+	ctrl     *gomock.Controller
+	recorder *MockIMetricsMockRecorder
+}
+
+// MockIMetricsMockRecorder is the mock recorder for MockIMetrics.
+type MockIMetricsMockRecorder struct {
+	mock *MockIMetrics
+}
+
+// NewMockIMetrics creates a new mock instance.
+func NewMockIMetrics(ctrl *gomock.Controller) *MockIMetrics {
+	mock := &MockIMetrics{ctrl: ctrl}
+	mock.recorder = &MockIMetricsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIMetrics) EXPECT() *MockIMetricsMockRecorder {
+	return m.recorder
+}
+
+// IncrCounter mocks base method.
+func (m *MockIMetrics) IncrCounter(arg0 string, arg1 int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncrCounter", arg0, arg1)
+}
+
+// IncrCounter indicates an expected call of IncrCounter.
+func (mr *MockIMetricsMockRecorder) IncrCounter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrCounter", reflect.TypeOf((*MockIMetrics)(nil).IncrCounter), arg0, arg1)
+}
+
+// Observe mocks base method.
+func (m *MockIMetrics) Observe(arg0 string, arg1 float64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Observe", arg0, arg1)
+}
+
+// Observe indicates an expected call of Observe.
+func (mr *MockIMetricsMockRecorder) Observe(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Observe", reflect.TypeOf((*MockIMetrics)(nil).Observe), arg0, arg1)
+}
@@ -50,17 +50,17 @@ func (mr *MockITransportMockRecorder) Close() *gomock.Call {
 }
 
 // Connect mocks base method.
-func (m *MockITransport) Connect(arg0 *url.URL) error {
+func (m *MockITransport) Connect(arg0 *url.URL, arg1 transport.URLSigner) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Connect", arg0)
+	ret := m.ctrl.Call(m, "Connect", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Connect indicates an expected call of Connect.
-func (mr *MockITransportMockRecorder) Connect(arg0 interface{}) *gomock.Call {
+func (mr *MockITransportMockRecorder) Connect(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*MockITransport)(nil).Connect), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*MockITransport)(nil).Connect), arg0, arg1)
 }
 
 // Reconnect mocks base method.
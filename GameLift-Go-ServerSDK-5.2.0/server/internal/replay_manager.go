@@ -0,0 +1,66 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package internal
+
+import (
+	"io"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/server/internal/transport"
+	"aws/amazon-gamelift-go-sdk/server/log"
+	"aws/amazon-gamelift-go-sdk/server/metrics"
+)
+
+// replayManager - an IGameLiftManager which replays a recording instead of connecting to GameLift.
+type replayManager struct {
+	*gameLiftManager
+	client   *websocketClient
+	replayed common.AtomicBool
+}
+
+// NewReplayManager - returns an IGameLiftManager whose first Connect passes the inbound messages of a recording
+// written by transport.Recorder to handlers, in the order they were received, and returns when they are handled.
+// The next calls of Connect, e.g. on RefreshConnection, do nothing. Sent messages are discarded.
+// Returns a common.BadRequestException error if the recording is invalid.
+func NewReplayManager(
+	r io.Reader,
+	handlers IGameLiftMessageHandler,
+	lg log.ILogger,
+	m metrics.IMetrics,
+	clock common.Clock,
+) (IGameLiftManager, error) {
+	replayTransport, err := transport.NewReplayTransport(r)
+	if err != nil {
+		return nil, err
+	}
+	client := new(websocketClient)
+	client.init(replayTransport, lg, m, DispatcherConfig{
+		Workers:   common.DispatcherWorkersDefault,
+		QueueSize: common.DispatcherQueueSizeDefault,
+	})
+	return &replayManager{
+		gameLiftManager: &gameLiftManager{
+			handlers: handlers,
+			client:   client,
+			lg:       lg,
+			clock:    clock,
+		},
+		client: client,
+	}, nil
+}
+
+func (manager *replayManager) Connect(string, string, string, string, string, transport.URLSigner) error {
+	if manager.replayed.Swap(true) {
+		return nil
+	}
+	// Unlike a connection to GameLift, the messages are replayed by Connect, so the handlers are added before
+	manager.addHandlers()
+	if err := manager.client.Connect(nil, nil); err != nil {
+		return err
+	}
+	manager.client.dispatcher.wait()
+	return nil
+}
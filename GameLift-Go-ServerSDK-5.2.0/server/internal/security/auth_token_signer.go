@@ -0,0 +1,40 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package security
+
+import (
+	"fmt"
+	"net/url"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/server/internal/transport"
+)
+
+// AuthTokenProvider is the interface that returns the auth token added to the connect URL.
+// AuthToken is called on every connection attempt, so implementations can rotate the token.
+type AuthTokenProvider interface {
+	AuthToken() (string, error)
+}
+
+// NewAuthTokenURLSigner returns a transport.URLSigner which adds the auth token of the provider to the connect URL.
+// Returns common.CredentialsFetchFailed errors.
+func NewAuthTokenURLSigner(provider AuthTokenProvider) transport.URLSigner {
+	return func(u *url.URL) (*url.URL, error) {
+		authToken, err := provider.AuthToken()
+		if err != nil {
+			return nil, common.NewGameLiftErrorWithCause(common.CredentialsFetchFailed, "",
+				fmt.Sprintf("failed to retrieve the auth token: %s", err), err)
+		}
+		if authToken == "" {
+			return nil, common.NewGameLiftError(common.CredentialsFetchFailed, "", "failed to retrieve the auth token: the token is empty")
+		}
+		query := u.Query()
+		query.Set(common.AuthTokenKey, authToken)
+		signed := *u
+		signed.RawQuery = query.Encode()
+		return &signed, nil
+	}
+}
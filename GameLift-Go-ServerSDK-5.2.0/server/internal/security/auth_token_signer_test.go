@@ -0,0 +1,74 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package security
+
+import (
+	"errors"
+	"testing"
+
+	"aws/amazon-gamelift-go-sdk/common"
+)
+
+type authTokenProviderFunc func() (string, error)
+
+func (f authTokenProviderFunc) AuthToken() (string, error) {
+	return f()
+}
+
+// GIVEN auth token signer WHEN the URL is signed twice THEN the current token of the provider is added each time
+func TestAuthTokenURLSigner_AddsCurrentToken(t *testing.T) {
+	// GIVEN
+	tokens := []string{"first-token", "second-token"}
+	signer := NewAuthTokenURLSigner(authTokenProviderFunc(func() (string, error) {
+		token := tokens[0]
+		tokens = tokens[1:]
+		return token, nil
+	}))
+	connectURL := newConnectURL(t)
+
+	// WHEN
+	first, err := signer(connectURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := signer(connectURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN
+	if got := first.Query().Get(common.AuthTokenKey); got != "first-token" {
+		t.Errorf("unexpected first token: %s", got)
+	}
+	if got := second.Query().Get(common.AuthTokenKey); got != "second-token" {
+		t.Errorf("unexpected second token: %s", got)
+	}
+	if got := second.Query().Get(common.FleetIDKey); got != "test-fleet-id" {
+		t.Errorf("unexpected fleet id: %s", got)
+	}
+	if connectURL.Query().Has(common.AuthTokenKey) {
+		t.Errorf("the original URL was modified: %s", connectURL)
+	}
+}
+
+// GIVEN failing or empty auth token provider WHEN the URL is signed THEN an error is returned
+func TestAuthTokenURLSigner_ProviderError(t *testing.T) {
+	for name, provider := range map[string]authTokenProviderFunc{
+		"error": func() (string, error) { return "", errors.New("test error") },
+		"empty": func() (string, error) { return "", nil },
+	} {
+		t.Run(name, func(t *testing.T) {
+			// WHEN
+			signed, err := NewAuthTokenURLSigner(provider)(newConnectURL(t))
+
+			// THEN
+			var gameLiftErr *common.GameLiftError
+			if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.CredentialsFetchFailed || signed != nil {
+				t.Errorf("expected CredentialsFetchFailed, got %v, %v", signed, err)
+			}
+		})
+	}
+}
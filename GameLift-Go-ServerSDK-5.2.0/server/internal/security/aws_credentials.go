@@ -5,9 +5,13 @@
 
 package security
 
+import "time"
+
 // Holds the AWS credentials.
 type AwsCredentials struct {
 	AccessKey    string `json:"AccessKeyId"`
 	SecretKey    string `json:"SecretAccessKey"`
 	SessionToken string `json:"Token"`
+	// Expiration is zero for credentials that don't expire.
+	Expiration time.Time `json:"Expiration"`
 }
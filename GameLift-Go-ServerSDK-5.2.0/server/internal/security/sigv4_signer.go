@@ -0,0 +1,56 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package security
+
+import (
+	"fmt"
+	"net/url"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/server/internal/transport"
+)
+
+// CredentialsProvider is the interface that returns the AWS credentials used to sign the connect URL.
+// Retrieve is called on every connection attempt, so implementations can rotate the credentials.
+// The server package implements it with a credentials.Provider, which can't be used here directly
+// because the credentials package depends on this one.
+type CredentialsProvider interface {
+	Retrieve() (AwsCredentials, error)
+}
+
+// NewSigV4URLSigner returns a transport.URLSigner which adds SigV4 query parameters to the connect URL.
+// Every call retrieves the credentials from the provider and signs the URL with the current time.
+// Returns common.CredentialsFetchFailed or common.RequestSigningFailed errors.
+func NewSigV4URLSigner(awsRegion string, provider CredentialsProvider, clock common.Clock) transport.URLSigner {
+	return func(u *url.URL) (*url.URL, error) {
+		awsCredentials, err := provider.Retrieve()
+		if err != nil {
+			return nil, common.NewGameLiftErrorWithCause(common.CredentialsFetchFailed, "",
+				fmt.Sprintf("failed to retrieve AWS credentials: %s", err), err)
+		}
+		query := u.Query()
+		sigV4QueryParameters, err := GenerateSigV4QueryParameters(SigV4Parameters{
+			AwsRegion:      awsRegion,
+			AwsCredentials: awsCredentials,
+			QueryParams: map[string]string{
+				common.ComputeIDKey: query.Get(common.ComputeIDKey),
+				common.FleetIDKey:   query.Get(common.FleetIDKey),
+				common.PidKey:       query.Get(common.PidKey),
+			},
+			RequestTime: clock.Now().UTC(),
+		})
+		if err != nil {
+			return nil, common.NewGameLiftErrorWithCause(common.RequestSigningFailed, "",
+				fmt.Sprintf("failed to generate SigV4 query parameters: %s", err), err)
+		}
+		for key, value := range sigV4QueryParameters {
+			query.Set(key, value)
+		}
+		signed := *u
+		signed.RawQuery = query.Encode()
+		return &signed, nil
+	}
+}
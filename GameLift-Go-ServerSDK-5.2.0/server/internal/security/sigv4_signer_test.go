@@ -0,0 +1,126 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package security
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+)
+
+type staticCredentialsProvider struct {
+	credentials AwsCredentials
+}
+
+func (p *staticCredentialsProvider) Retrieve() (AwsCredentials, error) {
+	return p.credentials, nil
+}
+
+type failingCredentialsProvider struct{}
+
+func (failingCredentialsProvider) Retrieve() (AwsCredentials, error) {
+	return AwsCredentials{}, errors.New("test error")
+}
+
+func newConnectURL(t *testing.T) *url.URL {
+	u, err := url.Parse("wss://example.test?pID=test-process-id&ComputeId=test-host-id&FleetId=test-fleet-id")
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+	return u
+}
+
+// GIVEN SigV4 signer WHEN the URL is signed THEN SigV4 query parameters of the current time and credentials are added
+func TestSigV4URLSigner_SignsWithCurrentTimeAndCredentials(t *testing.T) {
+	// GIVEN
+	clock := mock.NewFakeClock(time.Date(2024, 8, 5, 10, 0, 0, 0, time.UTC))
+	provider := &staticCredentialsProvider{AwsCredentials{AccessKey: "testAccessKey", SecretKey: "testSecretKey"}}
+	signer := NewSigV4URLSigner("us-east-1", provider, clock)
+	connectURL := newConnectURL(t)
+
+	// WHEN
+	first, err := signer(connectURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	clock.Advance(time.Hour)
+	provider.credentials = AwsCredentials{AccessKey: "rotatedAccessKey", SecretKey: "rotatedSecretKey", SessionToken: "rotatedToken"}
+	second, err := signer(connectURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN
+	expected, _ := GenerateSigV4QueryParameters(SigV4Parameters{
+		AwsRegion:      "us-east-1",
+		AwsCredentials: AwsCredentials{AccessKey: "testAccessKey", SecretKey: "testSecretKey"},
+		QueryParams: map[string]string{
+			common.ComputeIDKey: "test-host-id",
+			common.FleetIDKey:   "test-fleet-id",
+			common.PidKey:       "test-process-id",
+		},
+		RequestTime: time.Date(2024, 8, 5, 10, 0, 0, 0, time.UTC),
+	})
+	for k, v := range expected {
+		if first.Query().Get(k) != v {
+			t.Errorf("unexpected value for %s: got %s, want %s", k, first.Query().Get(k), v)
+		}
+	}
+	if first.Query().Get(common.PidKey) != "test-process-id" {
+		t.Errorf("the original query parameters are lost: %s", first)
+	}
+	if connectURL.Query().Get(AmzSignatureKey) != "" {
+		t.Errorf("the original URL was modified: %s", connectURL)
+	}
+	if got := second.Query().Get(AmzDateKey); got != "20240805T110000Z" {
+		t.Errorf("unexpected value for %s: got %s, want 20240805T110000Z", AmzDateKey, got)
+	}
+	if got := second.Query().Get(AmzCredentialKey); !strings.HasPrefix(got, "rotatedAccessKey/") {
+		t.Errorf("unexpected value for %s: got %s", AmzCredentialKey, got)
+	}
+	if got := second.Query().Get(AmzSecurityTokenHeadersKey); got != "rotatedToken" {
+		t.Errorf("unexpected value for %s: got %s, want rotatedToken", AmzSecurityTokenHeadersKey, got)
+	}
+}
+
+// GIVEN failing credentials provider WHEN the URL is signed THEN an error should be returned
+func TestSigV4URLSigner_CredentialsError(t *testing.T) {
+	// GIVEN
+	signer := NewSigV4URLSigner("us-east-1", failingCredentialsProvider{}, mock.NewFakeClock(time.Now()))
+
+	// WHEN
+	_, err := signer(newConnectURL(t))
+
+	// THEN
+	var gameLiftErr *common.GameLiftError
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.CredentialsFetchFailed {
+		t.Fatalf("expected CredentialsFetchFailed, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "failed to retrieve AWS credentials") {
+		t.Fatalf("expected credentials error, got %v", err)
+	}
+}
+
+// GIVEN credentials without secret key WHEN the URL is signed THEN RequestSigningFailed should be returned
+func TestSigV4URLSigner_SigningError(t *testing.T) {
+	// GIVEN
+	provider := &staticCredentialsProvider{AwsCredentials{AccessKey: "testAccessKey"}}
+	signer := NewSigV4URLSigner("us-east-1", provider, mock.NewFakeClock(time.Now()))
+
+	// WHEN
+	signed, err := signer(newConnectURL(t))
+
+	// THEN
+	var gameLiftErr *common.GameLiftError
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.RequestSigningFailed || signed != nil {
+		t.Fatalf("expected RequestSigningFailed, got %v, %v", signed, err)
+	}
+}
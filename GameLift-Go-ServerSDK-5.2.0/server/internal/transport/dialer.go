@@ -8,17 +8,49 @@ package transport
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"net"
 	"net/http"
 	"net/http/httptrace"
+	"net/url"
+	"os"
 	"strings"
 
 	"aws/amazon-gamelift-go-sdk/common"
 	"aws/amazon-gamelift-go-sdk/server/log"
 
 	"github.com/gorilla/websocket"
+	"golang.org/x/net/http/httpproxy"
 )
 
+// DialerConfig - network settings used to establish the websocket connection.
+//
+//   - ProxyURL - URL of the proxy to connect through. If empty, the HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+//     environment variables are used.
+//   - RootCAFile - path to a PEM file with the root certificates to trust instead of the system certificate pool.
+//   - ClientCertFile - path to a PEM file with the client certificate chain, for example the path returned by GetComputeCertificate.
+//   - ClientKeyFile - path to a PEM file with the client private key. If empty, the key is read from ClientCertFile.
+//   - MinTLSVersion - minimum TLS version: "1.0", "1.1", "1.2" or "1.3". If empty, the crypto/tls default is used.
+//   - EnableCompression - negotiate permessage-deflate compression (RFC 7692) with the server.
+//   - BufferSize - size in bytes of the read and write buffers. If zero, the gorilla/websocket default is used.
+type DialerConfig struct {
+	ProxyURL          string
+	RootCAFile        string
+	ClientCertFile    string
+	ClientKeyFile     string
+	MinTLSVersion     string
+	EnableCompression bool
+	BufferSize        int
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
 // websocketDialer is gorilla/websocket implementation of Dialer interface.
 type websocketDialer struct {
 	d  *websocket.Dialer
@@ -55,9 +87,22 @@ func (w *websocketDialer) Dial(url string, requestHeader http.Header) (conn Conn
 }
 
 // NewDialer create default Dialer instance.
-func NewDialer(lg log.ILogger) Dialer {
+//
+// Returns an error if the proxy URL, the TLS version or one of the certificate files is invalid.
+func NewDialer(lg log.ILogger, cfg DialerConfig) (Dialer, error) {
+	proxy, err := newProxyFunc(cfg.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
 	return &websocketDialer{
 		d: &websocket.Dialer{
+			Proxy:             proxy,
+			TLSClientConfig:   tlsConfig,
+			EnableCompression: cfg.EnableCompression,
 			NetDial: func(network, addr string) (net.Conn, error) {
 				if lg != nil {
 					lg.Debugf("Try connect to the network: %s by addr: %s", network, addr)
@@ -74,9 +119,67 @@ func NewDialer(lg log.ILogger) Dialer {
 				}
 				return con, err
 			},
-			ReadBufferSize:  common.GetEnvIntOrDefault(common.ServiceBufferSize, common.ServiceBufferSizeDefault, nil),
-			WriteBufferSize: common.GetEnvIntOrDefault(common.ServiceBufferSize, common.ServiceBufferSizeDefault, nil),
+			ReadBufferSize:  cfg.BufferSize,
+			WriteBufferSize: cfg.BufferSize,
 		},
 		lg: lg,
+	}, nil
+}
+
+// newProxyFunc returns a function that selects the proxy for a request.
+// An explicit proxy URL is used for every request, otherwise the environment is consulted.
+func newProxyFunc(proxyURL string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Host == "" {
+			return nil, common.NewGameLiftError(common.BadRequestException, "", fmt.Sprintf("invalid proxy URL %q", proxyURL))
+		}
+		return http.ProxyURL(u), nil
+	}
+	// httpproxy is used instead of http.ProxyFromEnvironment, which reads the environment only once per process
+	proxyFunc := httpproxy.FromEnvironment().ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
+
+// newTLSConfig builds the TLS client configuration. It returns nil if no TLS setting is specified.
+func newTLSConfig(cfg DialerConfig) (*tls.Config, error) {
+	if cfg.RootCAFile == "" && cfg.ClientCertFile == "" && cfg.MinTLSVersion == "" {
+		return nil, nil
+	}
+	//nolint:gosec // MinVersion is set below when specified, otherwise the crypto/tls default is used
+	tlsConfig := &tls.Config{}
+	if cfg.MinTLSVersion != "" {
+		version, ok := tlsVersions[cfg.MinTLSVersion]
+		if !ok {
+			return nil, common.NewGameLiftError(common.BadRequestException, "",
+				fmt.Sprintf("unsupported minimum TLS version %q", cfg.MinTLSVersion))
+		}
+		tlsConfig.MinVersion = version
+	}
+	if cfg.RootCAFile != "" {
+		pem, err := os.ReadFile(cfg.RootCAFile)
+		if err != nil {
+			return nil, common.NewGameLiftErrorWithCause(common.BadRequestException, "", fmt.Sprintf("failed to read root CA file: %s", err), err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, common.NewGameLiftError(common.BadRequestException, "",
+				fmt.Sprintf("no certificates found in root CA file %s", cfg.RootCAFile))
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.ClientCertFile != "" {
+		keyFile := cfg.ClientKeyFile
+		if keyFile == "" {
+			keyFile = cfg.ClientCertFile
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertFile, keyFile)
+		if err != nil {
+			return nil, common.NewGameLiftErrorWithCause(common.BadRequestException, "", fmt.Sprintf("failed to load client certificate: %s", err), err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
+	return tlsConfig, nil
 }
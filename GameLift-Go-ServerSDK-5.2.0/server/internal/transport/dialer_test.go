@@ -0,0 +1,283 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package transport_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/goleak"
+
+	"aws/amazon-gamelift-go-sdk/server/internal/transport"
+)
+
+const testHost = "gamelift.test"
+
+// writeCertificate generates a self-signed certificate for localhost and testHost
+// and writes the certificate and its private key into a single PEM file.
+func writeCertificate(t *testing.T, name string) (tls.Certificate, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{"localhost", testHost},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+
+	path := filepath.Join(t.TempDir(), name+".pem")
+	if err = os.WriteFile(path, append(certPem, keyPem...), 0o600); err != nil {
+		t.Fatalf("write certificate: %v", err)
+	}
+	cert, err := tls.X509KeyPair(certPem, keyPem)
+	if err != nil {
+		t.Fatalf("load certificate: %v", err)
+	}
+	return cert, path
+}
+
+// startWebsocketServer starts a TLS websocket server that accepts and immediately closes connections.
+func startWebsocketServer(t *testing.T, cfg *tls.Config) *httptest.Server {
+	var upgrader websocket.Upgrader
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+	srv.TLS = cfg
+	srv.StartTLS()
+	return srv
+}
+
+// startProxy starts an HTTP CONNECT proxy that tunnels every connection to backendAddr
+// and reports the requested host names.
+func startProxy(backendAddr string) (*httptest.Server, <-chan string) {
+	requested := make(chan string, 10)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		requested <- r.Host
+		backend, err := net.Dial("tcp", backendAddr)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		client, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			backend.Close()
+			return
+		}
+		_, _ = client.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		go func() {
+			_, _ = io.Copy(backend, client)
+			backend.Close()
+		}()
+		_, _ = io.Copy(client, backend)
+		client.Close()
+	}))
+	return proxy, requested
+}
+
+func dial(t *testing.T, cfg transport.DialerConfig, url string) error {
+	dialer, err := transport.NewDialer(nil, cfg)
+	if err != nil {
+		t.Fatalf("create dialer: %v", err)
+	}
+	conn, _, err := dialer.Dial(url, http.Header{})
+	if err == nil {
+		conn.Close()
+	}
+	return err
+}
+
+func wssURL(srv *httptest.Server) string {
+	return "wss://" + srv.Listener.Addr().String()
+}
+
+func TestDialerRootCA(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	serverCert, serverCertFile := writeCertificate(t, "server")
+	srv := startWebsocketServer(t, &tls.Config{Certificates: []tls.Certificate{serverCert}})
+	defer srv.Close()
+
+	// WHEN
+	trustedErr := dial(t, transport.DialerConfig{RootCAFile: serverCertFile}, wssURL(srv))
+	untrustedErr := dial(t, transport.DialerConfig{}, wssURL(srv))
+
+	// THEN
+	if trustedErr != nil {
+		t.Fatalf("dial with custom root CA: %v", trustedErr)
+	}
+	if untrustedErr == nil {
+		t.Fatalf("dial without custom root CA should fail")
+	}
+}
+
+func TestDialerClientCertificate(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	serverCert, serverCertFile := writeCertificate(t, "server")
+	clientCert, clientCertFile := writeCertificate(t, "client")
+	leaf, err := x509.ParseCertificate(clientCert.Certificate[0])
+	if err != nil {
+		t.Fatalf("parse client certificate: %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(leaf)
+	srv := startWebsocketServer(t, &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	})
+	defer srv.Close()
+
+	// WHEN
+	withCertErr := dial(t, transport.DialerConfig{RootCAFile: serverCertFile, ClientCertFile: clientCertFile}, wssURL(srv))
+	withoutCertErr := dial(t, transport.DialerConfig{RootCAFile: serverCertFile}, wssURL(srv))
+
+	// THEN
+	if withCertErr != nil {
+		t.Fatalf("dial with client certificate: %v", withCertErr)
+	}
+	if withoutCertErr == nil {
+		t.Fatalf("dial without client certificate should fail")
+	}
+}
+
+func TestDialerMinTLSVersion(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	serverCert, serverCertFile := writeCertificate(t, "server")
+	srv := startWebsocketServer(t, &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		MaxVersion:   tls.VersionTLS12,
+	})
+	defer srv.Close()
+
+	// WHEN
+	tls12Err := dial(t, transport.DialerConfig{RootCAFile: serverCertFile, MinTLSVersion: "1.2"}, wssURL(srv))
+	tls13Err := dial(t, transport.DialerConfig{RootCAFile: serverCertFile, MinTLSVersion: "1.3"}, wssURL(srv))
+
+	// THEN
+	if tls12Err != nil {
+		t.Fatalf("dial with TLS 1.2: %v", tls12Err)
+	}
+	if tls13Err == nil {
+		t.Fatalf("dial with minimum TLS 1.3 should fail")
+	}
+}
+
+func TestNewDialerInvalidConfig(t *testing.T) {
+	_, certFile := writeCertificate(t, "cert")
+	emptyFile := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(emptyFile, nil, 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  transport.DialerConfig
+	}{
+		{name: "invalid proxy URL", cfg: transport.DialerConfig{ProxyURL: "://proxy"}},
+		{name: "unknown TLS version", cfg: transport.DialerConfig{MinTLSVersion: "2.0"}},
+		{name: "missing root CA file", cfg: transport.DialerConfig{RootCAFile: filepath.Join(t.TempDir(), "missing.pem")}},
+		{name: "root CA file without certificates", cfg: transport.DialerConfig{RootCAFile: emptyFile}},
+		{name: "client key file without key", cfg: transport.DialerConfig{ClientCertFile: certFile, ClientKeyFile: emptyFile}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := transport.NewDialer(nil, test.cfg); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestDialerProxy(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	serverCert, serverCertFile := writeCertificate(t, "server")
+	srv := startWebsocketServer(t, &tls.Config{Certificates: []tls.Certificate{serverCert}})
+	defer srv.Close()
+	proxy, requested := startProxy(srv.Listener.Addr().String())
+	defer proxy.Close()
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	target := "wss://" + net.JoinHostPort(testHost, port)
+
+	t.Run("explicit proxy URL", func(t *testing.T) {
+		t.Setenv("HTTPS_PROXY", "")
+		err := dial(t, transport.DialerConfig{ProxyURL: proxy.URL, RootCAFile: serverCertFile}, target)
+		if err != nil {
+			t.Fatalf("dial through proxy: %v", err)
+		}
+		if host := <-requested; !strings.HasPrefix(host, testHost) {
+			t.Fatalf("unexpected proxy target: %s", host)
+		}
+	})
+
+	t.Run("HTTPS_PROXY", func(t *testing.T) {
+		t.Setenv("HTTPS_PROXY", proxy.URL)
+		t.Setenv("NO_PROXY", "")
+		err := dial(t, transport.DialerConfig{RootCAFile: serverCertFile}, target)
+		if err != nil {
+			t.Fatalf("dial through proxy: %v", err)
+		}
+		if host := <-requested; !strings.HasPrefix(host, testHost) {
+			t.Fatalf("unexpected proxy target: %s", host)
+		}
+	})
+
+	t.Run("NO_PROXY", func(t *testing.T) {
+		t.Setenv("HTTPS_PROXY", proxy.URL)
+		t.Setenv("NO_PROXY", testHost)
+		// The test host can't be resolved without the proxy
+		if err := dial(t, transport.DialerConfig{RootCAFile: serverCertFile}, target); err == nil {
+			t.Fatalf("dial should bypass the proxy")
+		}
+		if len(requested) != 0 {
+			t.Fatalf("proxy was used for a host from NO_PROXY")
+		}
+	})
+}
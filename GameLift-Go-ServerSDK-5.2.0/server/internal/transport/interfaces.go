@@ -12,16 +12,23 @@ package transport
 import (
 	"net/http"
 	"net/url"
+	"time"
 )
 
 // ReadHandler is a callback function that is called when incoming messages are received.
+// It is called from the read goroutine in the order of the messages, so it must return quickly.
 type ReadHandler func([]byte)
 
+// URLSigner returns a signed copy of the connect URL.
+// It is called before every connection attempt, so the signature and the credentials never age out on reconnects.
+type URLSigner func(u *url.URL) (*url.URL, error)
+
 // ITransport is the interface that manages input/output operations on the underlying connection.
 type ITransport interface {
 	// Connect creates a websocket connection with the specified address.
+	// If signer is not nil, every connection attempt uses the address signed by it.
 	// All Write calls before Connect call will return an error.
-	Connect(url *url.URL) error
+	Connect(url *url.URL, signer URLSigner) error
 
 	// Write sends message to underlying connection.
 	Write([]byte) error
@@ -33,7 +40,7 @@ type ITransport interface {
 	// All Write calls after Close call will return an error.
 	Close() error
 
-	// Reconnect reconnects to the previous url with synchronization
+	// Reconnect reconnects to the previous url with synchronization, signing it again if it was connected with a signer
 	Reconnect() error
 }
 
@@ -45,6 +52,20 @@ type Conn interface {
 	// WriteMessage is a method for writing the message to connection.
 	WriteMessage(messageType int, data []byte) error
 
+	// WriteControl writes a control message (ping, pong or close) with the given deadline.
+	// It is safe to call concurrently with the other write methods.
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+
+	// SetReadDeadline sets the deadline for future ReadMessage calls. A zero value means ReadMessage will not time out.
+	SetReadDeadline(t time.Time) error
+
+	// SetPongHandler sets the handler for pong messages received from the peer.
+	SetPongHandler(h func(appData string) error)
+
+	// SetReadLimit sets the maximum size in bytes for a message read from the peer.
+	// If a message exceeds the limit, the connection sends a close message to the peer and ReadMessage returns an error.
+	SetReadLimit(limit int64)
+
 	// CloseHandler returns the current close handler.
 	CloseHandler() func(code int, text string) error
 
@@ -0,0 +1,320 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/server/log"
+)
+
+// FrameDirection - the kind of a recorded frame.
+type FrameDirection string
+
+const (
+	// FrameInbound - a message received from GameLift.
+	FrameInbound FrameDirection = "in"
+	// FrameOutbound - a message sent to GameLift.
+	FrameOutbound FrameDirection = "out"
+	// FrameConnect - an attempt to connect to the URL of the frame.
+	FrameConnect FrameDirection = "connect"
+)
+
+// redacted - replaces the secrets in recorded frames.
+const redacted = "REDACTED"
+
+// secretKeys - lower case JSON keys and URL query parameters whose values are redacted:
+// the auth token, the SigV4 query parameters and the AWS credentials of GetFleetRoleCredentials.
+var secretKeys = map[string]bool{
+	"authtoken":            true,
+	"authorization":        true,
+	"x-amz-credential":     true,
+	"x-amz-signature":      true,
+	"x-amz-security-token": true,
+	"accesskeyid":          true,
+	"secretaccesskey":      true,
+	"sessiontoken":         true,
+}
+
+// RecordedFrame - a line of a recording.
+//
+//   - Data - the message, compacted. Messages which are not JSON are recorded as a JSON string.
+//   - URL - the connect URL of FrameConnect frames.
+//   - Error - the error of a failed write or connection.
+type RecordedFrame struct {
+	Time      time.Time       `json:"time"`
+	Direction FrameDirection  `json:"direction"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	URL       string          `json:"url,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// Message - returns the recorded message as it was sent or received, except for the whitespace and the redacted secrets.
+func (f *RecordedFrame) Message() ([]byte, error) {
+	if len(f.Data) > 0 && f.Data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(f.Data, &text); err != nil {
+			return nil, err
+		}
+		return []byte(text), nil
+	}
+	return f.Data, nil
+}
+
+// Recorder - writes every connection attempt, inbound and outbound message to GameLift with its time
+// to a writer, one RecordedFrame JSON per line. Auth tokens, SigV4 signatures and AWS credentials are redacted.
+// Failing to record a frame is logged and doesn't affect the connection.
+type Recorder struct {
+	log   log.ILogger
+	clock common.Clock
+
+	mtx sync.Mutex
+	w   io.Writer
+}
+
+// NewRecorder - returns a Recorder writing to w, see Recorder.Dialer and Recorder.Transport.
+func NewRecorder(w io.Writer, l log.ILogger, clock common.Clock) *Recorder {
+	return &Recorder{
+		log:   l,
+		clock: clock,
+		w:     w,
+	}
+}
+
+// Dialer - wraps the specified dialer by recording every connection attempt as a FrameConnect frame,
+// including the retries and the reconnects of the transport using it.
+func (r *Recorder) Dialer(next Dialer) Dialer {
+	return &recorderDialer{Dialer: next, recorder: r}
+}
+
+// Transport - wraps the specified transport by recording every inbound and outbound message.
+func (r *Recorder) Transport(next ITransport) ITransport {
+	return &recorderTransport{ITransport: next, recorder: r}
+}
+
+func (r *Recorder) record(frame *RecordedFrame) {
+	frame.Time = r.clock.Now().UTC()
+	line, err := json.Marshal(frame)
+	if err != nil {
+		r.log.Warnf("Failed to record %s frame: %s", frame.Direction, err)
+		return
+	}
+	line = append(line, '\n')
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if _, err := r.w.Write(line); err != nil {
+		r.log.Warnf("Failed to record %s frame: %s", frame.Direction, err)
+	}
+}
+
+type recorderDialer struct {
+	Dialer
+	recorder *Recorder
+}
+
+func (d *recorderDialer) Dial(urlStr string, requestHeader http.Header) (Conn, *http.Response, error) {
+	conn, resp, err := d.Dialer.Dial(urlStr, requestHeader)
+	frame := RecordedFrame{Direction: FrameConnect}
+	if u, parseErr := url.Parse(urlStr); parseErr == nil {
+		frame.URL = redactURL(u)
+	}
+	if err != nil {
+		frame.Error = err.Error()
+	}
+	d.recorder.record(&frame)
+	return conn, resp, err
+}
+
+type recorderTransport struct {
+	ITransport
+	recorder *Recorder
+}
+
+func (r *recorderTransport) Write(data []byte) error {
+	err := r.ITransport.Write(data)
+	frame := RecordedFrame{Direction: FrameOutbound, Data: recordedData(data)}
+	if err != nil {
+		frame.Error = err.Error()
+	}
+	r.recorder.record(&frame)
+	return err
+}
+
+func (r *recorderTransport) SetReadHandler(handler ReadHandler) {
+	if handler == nil {
+		r.ITransport.SetReadHandler(nil)
+		return
+	}
+	r.ITransport.SetReadHandler(func(data []byte) {
+		r.recorder.record(&RecordedFrame{Direction: FrameInbound, Data: recordedData(data)})
+		handler(data)
+	})
+}
+
+// Redact - returns the message with the secrets redacted as in a recording, so it can be logged.
+// A message which is not JSON is returned as a JSON string.
+func Redact(data []byte) []byte {
+	return recordedData(data)
+}
+
+// recordedData - returns the redacted message if it is JSON, otherwise the message as a JSON string.
+func recordedData(data []byte) json.RawMessage {
+	if !json.Valid(data) {
+		text, _ := json.Marshal(string(data))
+		return text
+	}
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Keeps the numbers as received instead of converting them to float64
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil || !redactValue(value) {
+		// Nothing is redacted, the message is recorded as is
+		return append(json.RawMessage(nil), data...)
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		text, _ := json.Marshal(redacted)
+		return text
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n")
+}
+
+// redactValue - replaces the values of the secret keys in the decoded JSON, returns true if any was replaced.
+func redactValue(value any) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if secretKeys[strings.ToLower(key)] {
+				if item != nil && item != "" {
+					v[key] = redacted
+					changed = true
+				}
+				continue
+			}
+			changed = redactValue(item) || changed
+		}
+	case []any:
+		for _, item := range v {
+			changed = redactValue(item) || changed
+		}
+	}
+	return changed
+}
+
+// redactURL - returns the URL with the values of the secret query parameters replaced.
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	query := u.Query()
+	for key := range query {
+		if secretKeys[strings.ToLower(key)] {
+			query.Set(key, redacted)
+		}
+	}
+	redactedURL := *u
+	redactedURL.RawQuery = query.Encode()
+	return redactedURL.String()
+}
+
+// ReadRecording - reads the frames written by a Recorder.
+func ReadRecording(r io.Reader) ([]RecordedFrame, error) {
+	var frames []RecordedFrame
+	decoder := json.NewDecoder(r)
+	for {
+		var frame RecordedFrame
+		if err := decoder.Decode(&frame); err == io.EOF {
+			return frames, nil
+		} else if err != nil {
+			return nil, common.NewGameLiftErrorWithCause(common.BadRequestException, "", fmt.Sprintf("invalid recording: %s", err), err)
+		}
+		frames = append(frames, frame)
+	}
+}
+
+// Replay - calls handler with every inbound message of the recording, in the order they were received.
+func Replay(r io.Reader, handler ReadHandler) error {
+	frames, err := ReadRecording(r)
+	if err != nil {
+		return err
+	}
+	return replayFrames(frames, handler)
+}
+
+func replayFrames(frames []RecordedFrame, handler ReadHandler) error {
+	for i := range frames {
+		if frames[i].Direction != FrameInbound {
+			continue
+		}
+		data, err := frames[i].Message()
+		if err != nil {
+			return common.NewGameLiftErrorWithCause(common.BadRequestException, "",
+				fmt.Sprintf("invalid recorded message: %s", err), err)
+		}
+		handler(data)
+	}
+	return nil
+}
+
+// replayTransport - an ITransport which replays the inbound messages of a recording instead of connecting.
+type replayTransport struct {
+	mtx         sync.Mutex
+	frames      []RecordedFrame
+	readHandler ReadHandler
+}
+
+// NewReplayTransport - returns an ITransport whose Connect replays the inbound messages of the recording
+// to the read handler, in order, before it returns. Written messages are discarded.
+func NewReplayTransport(r io.Reader) (ITransport, error) {
+	frames, err := ReadRecording(r)
+	if err != nil {
+		return nil, err
+	}
+	return &replayTransport{frames: frames}, nil
+}
+
+func (t *replayTransport) Connect(*url.URL, URLSigner) error {
+	t.mtx.Lock()
+	handler := t.readHandler
+	frames := t.frames
+	t.frames = nil
+	t.mtx.Unlock()
+	if handler == nil {
+		return nil
+	}
+	return replayFrames(frames, handler)
+}
+
+func (t *replayTransport) Write([]byte) error {
+	return nil
+}
+
+func (t *replayTransport) SetReadHandler(handler ReadHandler) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.readHandler = handler
+}
+
+func (t *replayTransport) Close() error {
+	return nil
+}
+
+func (t *replayTransport) Reconnect() error {
+	return nil
+}
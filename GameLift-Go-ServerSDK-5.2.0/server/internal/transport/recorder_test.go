@@ -0,0 +1,196 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package transport_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/goleak"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+	"aws/amazon-gamelift-go-sdk/server/internal/transport"
+)
+
+const (
+	recordedRefreshConnection = `{"Action":"RefreshConnection","RefreshConnectionEndpoint":"wss://example.test","AuthToken":"secret-auth-token"}`
+	recordedCredentials       = `{"Action":"GetFleetRoleCredentials","RequestId":"1","AccessKeyId":"secret-access-key-id",` +
+		`"SecretAccessKey":"secret-access-key","SessionToken":"secret-session-token","Expiration":1700000000000}`
+)
+
+// GIVEN a recorder WHEN the dialer connects and the transport writes and reads messages
+// THEN every frame is recorded in order with its time, and the secrets are redacted
+func TestRecorderRecordsFrames(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	transportMock := mock.NewMockITransport(ctrl)
+	dialerMock := mock.NewMockDialer(ctrl)
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	var buf bytes.Buffer
+	recorder := transport.NewRecorder(&buf, mock.NewTestLogger(t, ctrl), mock.NewFakeClock(now))
+	recordedDialer := recorder.Dialer(dialerMock)
+	recordedTransport := recorder.Transport(transportMock)
+
+	const connectURL = "wss://example.test?pID=process-1&Authorization=secret-auth-token&X-Amz-Signature=secret-signature"
+	var readHandler transport.ReadHandler
+	var received [][]byte
+
+	// EXPECT
+	transportMock.EXPECT().
+		SetReadHandler(gomock.Not(gomock.Nil())).
+		Do(func(handler transport.ReadHandler) { readHandler = handler })
+	dialerMock.EXPECT().Dial(connectURL, nil)
+	transportMock.EXPECT().Write([]byte(testMessage))
+	transportMock.EXPECT().Write([]byte("not json")).Return(testError)
+
+	// WHEN
+	recordedTransport.SetReadHandler(func(data []byte) { received = append(received, data) })
+	if _, _, err := recordedDialer.Dial(connectURL, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := recordedTransport.Write([]byte(testMessage)); err != nil {
+		t.Fatal(err)
+	}
+	if err := recordedTransport.Write([]byte("not json")); !errors.Is(err, testError) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	readHandler([]byte(recordedRefreshConnection))
+	readHandler([]byte(recordedCredentials))
+
+	// THEN
+	if len(received) != 2 || string(received[0]) != recordedRefreshConnection {
+		t.Fatalf("the read handler received %q", received)
+	}
+	if strings.Contains(buf.String(), "secret") {
+		t.Fatalf("the recording contains secrets:\n%s", buf.String())
+	}
+	if strings.Count(buf.String(), "\n") != 5 {
+		t.Fatalf("expected one line per frame:\n%s", buf.String())
+	}
+
+	frames, err := transport.ReadRecording(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	directions := make([]transport.FrameDirection, 0, len(frames))
+	for i := range frames {
+		directions = append(directions, frames[i].Direction)
+		if !frames[i].Time.Equal(now) {
+			t.Errorf("unexpected time %s of frame %d", frames[i].Time, i)
+		}
+	}
+	expectedDirections := []transport.FrameDirection{
+		transport.FrameConnect, transport.FrameOutbound, transport.FrameOutbound, transport.FrameInbound, transport.FrameInbound,
+	}
+	if !reflect.DeepEqual(directions, expectedDirections) {
+		t.Fatalf("unexpected directions %v, want %v", directions, expectedDirections)
+	}
+	if frames[0].URL != "wss://example.test?Authorization=REDACTED&X-Amz-Signature=REDACTED&pID=process-1" {
+		t.Errorf("unexpected URL %s", frames[0].URL)
+	}
+	assertMessage(t, &frames[1], `{"key":"value"}`)
+	assertMessage(t, &frames[2], "not json")
+	if frames[2].Error != testError.Error() {
+		t.Errorf("unexpected error %q", frames[2].Error)
+	}
+	assertMessage(t, &frames[3],
+		`{"Action":"RefreshConnection","AuthToken":"REDACTED","RefreshConnectionEndpoint":"wss://example.test"}`)
+	assertMessage(t, &frames[4], `{"AccessKeyId":"REDACTED","Action":"GetFleetRoleCredentials",`+
+		`"Expiration":1700000000000,"RequestId":"1","SecretAccessKey":"REDACTED","SessionToken":"REDACTED"}`)
+}
+
+// GIVEN a recorded dialer WHEN a connection attempt fails and the next one, e.g. a reconnect, succeeds
+// THEN both attempts are recorded, the failed one with its error
+func TestRecorderRecordsConnectionAttempts(t *testing.T) {
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	dialerMock := mock.NewMockDialer(ctrl)
+	var buf bytes.Buffer
+	recorder := transport.NewRecorder(&buf, mock.NewTestLogger(t, ctrl), mock.NewFakeClock(time.Now()))
+	recordedDialer := recorder.Dialer(dialerMock)
+
+	// EXPECT
+	gomock.InOrder(
+		dialerMock.EXPECT().Dial("wss://example.test", nil).Return(nil, nil, testError),
+		dialerMock.EXPECT().Dial("wss://example.test", nil),
+	)
+
+	// WHEN
+	if _, _, err := recordedDialer.Dial("wss://example.test", nil); !errors.Is(err, testError) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, _, err := recordedDialer.Dial("wss://example.test", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN
+	frames, err := transport.ReadRecording(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 2 || frames[0].Direction != transport.FrameConnect || frames[1].Direction != transport.FrameConnect {
+		t.Fatalf("unexpected frames %+v", frames)
+	}
+	if frames[0].Error != testError.Error() || frames[1].Error != "" {
+		t.Fatalf("unexpected errors %q, %q", frames[0].Error, frames[1].Error)
+	}
+}
+
+// GIVEN a recording WHEN it is replayed THEN the handler is called with the inbound messages in order
+func TestReplay(t *testing.T) {
+	// GIVEN
+	recording := `{"time":"2023-01-02T03:04:05Z","direction":"connect","url":"wss://example.test"}
+{"time":"2023-01-02T03:04:05Z","direction":"in","data":{"Action":"CreateGameSession"}}
+{"time":"2023-01-02T03:04:05Z","direction":"out","data":{"Action":"ActivateGameSession"}}
+{"time":"2023-01-02T03:04:06Z","direction":"in","data":"not json"}
+`
+	var received []string
+
+	// WHEN
+	err := transport.Replay(strings.NewReader(recording), func(data []byte) {
+		received = append(received, string(data))
+	})
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{`{"Action":"CreateGameSession"}`, "not json"}
+	if !reflect.DeepEqual(received, expected) {
+		t.Fatalf("unexpected messages %q, want %q", received, expected)
+	}
+}
+
+// GIVEN an invalid recording WHEN it is replayed THEN a BadRequestException error is returned
+func TestReplayInvalidRecording(t *testing.T) {
+	err := transport.Replay(strings.NewReader(`{"direction":`), func([]byte) {
+		t.Fatal("unexpected message")
+	})
+
+	var gameLiftErr *common.GameLiftError
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.BadRequestException {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func assertMessage(t *testing.T, frame *transport.RecordedFrame, expected string) {
+	t.Helper()
+	data, err := frame.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != expected {
+		t.Errorf("unexpected message %s, want %s", data, expected)
+	}
+}
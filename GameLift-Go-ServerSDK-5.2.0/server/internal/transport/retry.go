@@ -15,19 +15,31 @@ import (
 type retryTransport struct {
 	ITransport
 	log      log.ILogger
+	clock    common.Clock
 	attempt  int
 	factor   int
 	interval time.Duration
 }
 
+// RetryConfig - retries of the failed writes.
+//
+//   - MaxRetry - max number of write attempts.
+//   - Factor, Interval - the attempt n waits n * Factor * Interval before the next one.
+type RetryConfig struct {
+	MaxRetry int
+	Factor   int
+	Interval time.Duration
+}
+
 // WithRetry wraps the specified transport by adding a retry mechanism to the Write method.
-func WithRetry(next ITransport, l log.ILogger) ITransport {
+func WithRetry(next ITransport, l log.ILogger, clock common.Clock, cfg RetryConfig) ITransport {
 	return &retryTransport{
 		ITransport: next,
 		log:        l,
-		factor:     common.GetEnvIntOrDefault(common.RetryFactor, common.RetryFactorDefault, l),
-		attempt:    common.GetEnvIntOrDefault(common.MaxRetry, common.MaxRetryDefault, l),
-		interval:   common.GetEnvDurationOrDefault(common.RetryInterval, common.RetryIntervalDefault, l),
+		clock:      clock,
+		factor:     cfg.Factor,
+		attempt:    cfg.MaxRetry,
+		interval:   cfg.Interval,
 	}
 }
 
@@ -38,7 +50,7 @@ func (r *retryTransport) Write(data []byte) error {
 			return nil
 		}
 		r.log.Debugf("Call Failed: %s. Retrying attempt: %d of %d", err.Error(), i+1, r.attempt)
-		time.Sleep(time.Duration((i+1)*r.factor) * r.interval)
+		r.clock.Sleep(time.Duration((i+1)*r.factor) * r.interval)
 	}
 
 	return common.NewGameLiftError(
@@ -8,6 +8,7 @@ package transport_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"go.uber.org/goleak"
@@ -19,6 +20,12 @@ import (
 
 var testError = errors.New("test error")
 
+var testRetryConfig = transport.RetryConfig{
+	MaxRetry: common.MaxRetryDefault,
+	Factor:   common.RetryFactorDefault,
+	Interval: common.RetryIntervalDefault,
+}
+
 func TestRetryTransportWrite(t *testing.T) {
 	defer goleak.VerifyNone(t)
 
@@ -41,11 +48,18 @@ func TestRetryTransportWrite(t *testing.T) {
 		Write([]byte(testMessage)).
 		Return(nil)
 
-	retryTransport := transport.WithRetry(transportMock, logger)
+	clock := mock.NewFakeClock(time.Now())
+	retryTransport := transport.WithRetry(transportMock, logger, clock, testRetryConfig)
+
+	result := make(chan error)
+	go func() {
+		result <- retryTransport.Write([]byte(testMessage))
+	}()
 
-	t.Logf("Tests are running, please wait")
+	clock.BlockUntil(1)
+	clock.Advance(common.RetryFactorDefault * common.RetryIntervalDefault)
 
-	err := retryTransport.Write([]byte(testMessage))
+	err := <-result
 	if err != nil {
 		t.Fatalf("fall to write to retry transport: %v", err)
 	}
@@ -70,11 +84,21 @@ func TestRetryTransportWriteMaxAttempts(t *testing.T) {
 			Debugf("Call Failed: %s. Retrying attempt: %d of %d", testError.Error(), i+1, common.MaxRetryDefault)
 	}
 
-	retryTransport := transport.WithRetry(transportMock, logger)
+	clock := mock.NewFakeClock(time.Now())
+	retryTransport := transport.WithRetry(transportMock, logger, clock, testRetryConfig)
 
-	t.Logf("Tests are running, please wait")
+	result := make(chan error)
+	go func() {
+		result <- retryTransport.Write([]byte(testMessage))
+	}()
+
+	// Each attempt waits (attempt * factor * interval) before the next one
+	for i := 0; i < common.MaxRetryDefault; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Duration((i+1)*common.RetryFactorDefault) * common.RetryIntervalDefault)
+	}
 
-	err := retryTransport.Write([]byte(testMessage))
+	err := <-result
 	if err == nil || err.Error() != "[GameLiftError: ErrorType={25}, ErrorName={Failed write retry}, ErrorMessage={write attempt overflow}]" {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -6,9 +6,10 @@
 package transport
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"sync"
@@ -16,21 +17,42 @@ import (
 
 	"aws/amazon-gamelift-go-sdk/common"
 	"aws/amazon-gamelift-go-sdk/server/log"
+	"aws/amazon-gamelift-go-sdk/server/metrics"
 
 	"github.com/gorilla/websocket"
 	"github.com/sethvargo/go-retry"
 )
 
+// WebsocketConfig - tunables of the websocket connection.
+//
+//   - PingInterval - interval between keepalive pings. Zero disables pings and read deadlines.
+//   - PongTimeout - time to wait for a pong (or any other message) after a ping before the connection is considered dead.
+//   - ReadLimit - max size in bytes of an incoming message. Zero means no limit.
+//     A message exceeding the limit breaks the connection, which is then reestablished.
+//   - LargeMessageSize - size in bytes starting from which sent and received messages are logged. Zero disables logging.
+type WebsocketConfig struct {
+	PingInterval     time.Duration
+	PongTimeout      time.Duration
+	ReadLimit        int64
+	LargeMessageSize int
+}
+
 // websocketTransport - implement ITransport interface for websocket connection.
 type websocketTransport struct {
-	log    log.ILogger
-	dialer Dialer
+	log     log.ILogger
+	metrics metrics.IMetrics
+	dialer  Dialer
+	clock   common.Clock
+	cfg     WebsocketConfig
 
+	// connMtx guards conn, which is replaced by Connect while the read goroutine and Close use it
+	connMtx      sync.Mutex
 	conn         Conn
 	isConnected  common.AtomicBool
 	reconnecting common.AtomicBool
 	writeMtx     sync.Mutex
 	connectURL   url.URL
+	signer       URLSigner
 
 	readHandlerMu sync.RWMutex
 	readHandler   ReadHandler
@@ -38,6 +60,7 @@ type websocketTransport struct {
 	readRetries  int
 	writeRetries int
 
+	// readGoroutineCount is only changed by Connect, under writeMtx
 	readGoroutineCount int
 }
 
@@ -47,11 +70,20 @@ func isAbnormalCloseError(err error) bool {
 		websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure)
 }
 
+// isTimeoutError returns true if the error was caused by an expired read or write deadline
+func isTimeoutError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
 // Websocket creates a new instance of the ITransport implementation.
-func Websocket(logger log.ILogger, dialer Dialer) ITransport {
+func Websocket(logger log.ILogger, m metrics.IMetrics, dialer Dialer, clock common.Clock, cfg WebsocketConfig) ITransport {
 	return &websocketTransport{
-		log:    logger,
-		dialer: dialer,
+		log:     logger,
+		metrics: m,
+		dialer:  dialer,
+		clock:   clock,
+		cfg:     cfg,
 	}
 }
 
@@ -64,7 +96,7 @@ func (tr *websocketTransport) handleNetworkInterrupt(e error) error {
 	return nil
 }
 
-func (tr *websocketTransport) Connect(u *url.URL) error {
+func (tr *websocketTransport) Connect(u *url.URL, signer URLSigner) error {
 	tr.writeMtx.Lock()
 	defer tr.writeMtx.Unlock()
 	// always set reconnecting to true so other goroutines can check whether a new connection is being set up
@@ -74,7 +106,6 @@ func (tr *websocketTransport) Connect(u *url.URL) error {
 	}
 	tr.log.Debugf("Establishing websocket connection")
 
-	ctx := context.Background()
 	// Exponential doubles the interval between retries
 	backOff := retry.NewExponential(common.ConnectRetryInterval)
 	// We are adding two because we skip the first two retries until the initial duration is 4
@@ -83,38 +114,73 @@ func (tr *websocketTransport) Connect(u *url.URL) error {
 	backOff.Next()
 	backOff.Next()
 
-	if err := retry.Do(ctx, backOff, func(ctx context.Context) error {
-		//nolint:bodyclose // The response body may not contain the entire response and does not need to be closed by the application
-		conn, resp, dialErr := tr.dialer.Dial(u.String(), http.Header{"User-Agent": []string{"gamelift-go-sdk/1.0"}})
-		if dialErr != nil {
-			var reason string
-			if resp != nil {
-				reason = resp.Status
-				b, _ := io.ReadAll(resp.Body)
-				tr.log.Debugf("Response header is: %v", resp.Header)
-				tr.log.Debugf("Response body is: %s", b)
-			}
-			return retry.RetryableError(
-				common.NewGameLiftError(common.WebsocketConnectFailure,
-					"",
-					fmt.Sprintf("connection error %s:%s", reason, dialErr.Error()),
-				),
-			)
+	for {
+		err := tr.signAndDial(u, signer)
+		if err == nil {
+			break
 		}
-		tr.conn = conn
-		return nil
-	}); err != nil {
-		return err
+		next, stop := backOff.Next()
+		if stop {
+			return err
+		}
+		tr.clock.Sleep(next)
 	}
 
-	tr.setCloseHandler()
+	connection := tr.getConn()
+	tr.setCloseHandler(connection)
 	tr.connectURL = *u
+	tr.signer = signer
 	tr.isConnected.Store(true)
 	tr.reconnecting.Store(false)
-	go tr.readProcess()
+	index := tr.readGoroutineCount
+	tr.readGoroutineCount++
+	go tr.readProcess(connection, index)
 	return nil
 }
 
+// signAndDial signs the address if signer is not nil and makes a single attempt to connect to it.
+func (tr *websocketTransport) signAndDial(u *url.URL, signer URLSigner) error {
+	if signer == nil {
+		return tr.dial(u)
+	}
+	signed, err := signer(u)
+	if err != nil {
+		tr.log.Warnf("Failed to sign websocket URL: %s", err)
+		return err
+	}
+	return tr.dial(signed)
+}
+
+// dial makes a single attempt to establish a websocket connection with the specified address.
+func (tr *websocketTransport) dial(u *url.URL) error {
+	//nolint:bodyclose // The response body may not contain the entire response and does not need to be closed by the application
+	conn, resp, dialErr := tr.dialer.Dial(u.String(), http.Header{"User-Agent": []string{"gamelift-go-sdk/1.0"}})
+	if dialErr != nil {
+		var reason string
+		if resp != nil {
+			reason = resp.Status
+			b, _ := io.ReadAll(resp.Body)
+			tr.log.Debugf("Response header is: %v", resp.Header)
+			tr.log.Debugf("Response body is: %s", b)
+		}
+		return common.NewGameLiftErrorWithCause(common.WebsocketConnectFailure,
+			"",
+			fmt.Sprintf("connection error %s:%s", reason, dialErr.Error()),
+			dialErr,
+		)
+	}
+	tr.connMtx.Lock()
+	tr.conn = conn
+	tr.connMtx.Unlock()
+	return nil
+}
+
+func (tr *websocketTransport) getConn() Conn {
+	tr.connMtx.Lock()
+	defer tr.connMtx.Unlock()
+	return tr.conn
+}
+
 // Reconnect - blocks until ongoing reconnect succeeds or initiates and finishes a new reconnect.
 func (tr *websocketTransport) Reconnect() error {
 	if tr.reconnecting.Swap(true) {
@@ -122,15 +188,15 @@ func (tr *websocketTransport) Reconnect() error {
 		defer tr.writeMtx.Unlock()
 		return nil
 	}
-	err := tr.Connect(&tr.connectURL)
+	err := tr.Connect(&tr.connectURL, tr.signer)
 	tr.reconnecting.Store(false)
 	return err
 }
 
-func (tr *websocketTransport) setCloseHandler() {
+func (tr *websocketTransport) setCloseHandler(connection Conn) {
 	// wraps a default handler that correctly implements the protocol specification.
-	currentHandler := tr.conn.CloseHandler()
-	tr.conn.SetCloseHandler(func(code int, text string) error {
+	currentHandler := connection.CloseHandler()
+	connection.SetCloseHandler(func(code int, text string) error {
 		tr.log.Debugf("Socket disconnected. Code is %d. Reason is %s", code, text)
 		tr.isConnected.Store(false)
 		err := tr.Close()
@@ -141,11 +207,16 @@ func (tr *websocketTransport) setCloseHandler() {
 	})
 }
 
-func (tr *websocketTransport) readProcess() {
-	index := tr.readGoroutineCount
-	tr.readGoroutineCount++
-	connection := tr.conn
+func (tr *websocketTransport) readProcess(connection Conn, index int) {
 	defer connection.Close()
+	if tr.cfg.ReadLimit > 0 {
+		connection.SetReadLimit(tr.cfg.ReadLimit)
+	}
+	if tr.cfg.PingInterval > 0 {
+		stop := make(chan struct{})
+		defer close(stop)
+		tr.startKeepAlive(connection, stop)
+	}
 	for {
 		// ReadMessage will read all message from the NextReader
 		// The returned messageType is either TextMessage or BinaryMessage.
@@ -156,6 +227,14 @@ func (tr *websocketTransport) readProcess() {
 		t, msg, err := connection.ReadMessage()
 
 		if err != nil {
+			if errors.Is(err, websocket.ErrReadLimit) {
+				tr.metrics.IncrCounter(metrics.ReadLimitExceeded, 1)
+				tr.log.Errorf("read goroutine %d: Received message exceeds the read limit of %d bytes", index, tr.cfg.ReadLimit)
+			}
+			if isTimeoutError(err) {
+				tr.log.Warnf("read goroutine %d: No message received within %s, connection is considered dead",
+					index, tr.cfg.PingInterval+tr.cfg.PongTimeout)
+			}
 			if isAbnormalCloseError(err) {
 				if !tr.reconnecting.Load() {
 					tr.log.Errorf("read goroutine %d: Websocket readProcess failed: %v", index, err)
@@ -175,18 +254,64 @@ func (tr *websocketTransport) readProcess() {
 			break
 		}
 
+		if tr.cfg.PingInterval > 0 {
+			tr.extendReadDeadline(connection)
+		}
+
+		tr.metrics.Observe(metrics.MessageReceivedBytes, float64(len(msg)))
+		if tr.isLargeMessage(msg) {
+			tr.log.Warnf("read goroutine %d: Received large message of %d bytes", index, len(msg))
+		}
+
 		if t != websocket.TextMessage {
 			tr.log.Warnf("read goroutine %d: Unknown Data received. Data type is not a text message", index)
 			continue // Skip all non text messages
 		}
 
+		// Called in the order of the messages, the handler dispatches them without blocking unless it is saturated
 		if handler := tr.getReadHandler(); handler != nil {
-			go handler(msg)
+			handler(msg)
 		}
 	}
 	tr.log.Debugf("read goroutine %d: ending", index)
 }
 
+// startKeepAlive arms the read deadline of the connection and starts sending periodic pings until stop is closed.
+// Every pong or message received extends the deadline, so a silent peer makes ReadMessage fail with a timeout
+// and the read goroutine goes through the usual network interruption handling.
+func (tr *websocketTransport) startKeepAlive(connection Conn, stop <-chan struct{}) {
+	connection.SetPongHandler(func(string) error {
+		tr.extendReadDeadline(connection)
+		return nil
+	})
+	tr.extendReadDeadline(connection)
+	go tr.pingProcess(connection, stop)
+}
+
+func (tr *websocketTransport) extendReadDeadline(connection Conn) {
+	if err := connection.SetReadDeadline(tr.clock.Now().Add(tr.cfg.PingInterval + tr.cfg.PongTimeout)); err != nil {
+		tr.log.Debugf("Failed to set read deadline: %v", err)
+	}
+}
+
+func (tr *websocketTransport) pingProcess(connection Conn, stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-tr.clock.After(tr.cfg.PingInterval):
+		}
+		// A failed ping is not handled here: the read deadline expires and the read goroutine reconnects
+		if err := connection.WriteControl(websocket.PingMessage, nil, tr.clock.Now().Add(tr.cfg.PongTimeout)); err != nil {
+			tr.log.Debugf("Failed to send ping: %v", err)
+		}
+	}
+}
+
+func (tr *websocketTransport) isLargeMessage(data []byte) bool {
+	return tr.cfg.LargeMessageSize > 0 && len(data) >= tr.cfg.LargeMessageSize
+}
+
 func (tr *websocketTransport) SetReadHandler(handler ReadHandler) {
 	tr.readHandlerMu.Lock()
 	defer tr.readHandlerMu.Unlock()
@@ -205,9 +330,9 @@ func (tr *websocketTransport) Close() error {
 	// Set isConnected to false and close connection only if previously isConnected value was true.
 	if tr.isConnected.CompareAndSwap(true, false) {
 		tr.log.Debugf("Close websocket connection")
-		if tr.conn != nil {
-			if err := tr.conn.Close(); err != nil {
-				return common.NewGameLiftError(common.WebsocketClosingError, "", err.Error())
+		if conn := tr.getConn(); conn != nil {
+			if err := conn.Close(); err != nil {
+				return common.NewGameLiftErrorWithCause(common.WebsocketClosingError, "", "", err)
 			}
 		}
 	}
@@ -221,10 +346,14 @@ func (tr *websocketTransport) Write(data []byte) error {
 		tr.writeMtx.Unlock()
 		return common.NewGameLiftError(common.GameLiftServerNotInitialized, "", "")
 	}
+	tr.metrics.Observe(metrics.MessageSentBytes, float64(len(data)))
+	if tr.isLargeMessage(data) {
+		tr.log.Warnf("Sending large message of %d bytes", len(data))
+	}
 	tr.writeRetries = 0
 	var err error
 	for ; tr.writeRetries < common.MaxReadWriteRetry; tr.writeRetries++ {
-		if err = tr.conn.WriteMessage(websocket.TextMessage, data); err != nil && isAbnormalCloseError(err) {
+		if err = tr.getConn().WriteMessage(websocket.TextMessage, data); err != nil && isAbnormalCloseError(err) {
 			if tr.writeRetries == common.ReconnectOnReadWriteFailureNumber {
 				tr.writeMtx.Unlock()
 				if err = tr.handleNetworkInterrupt(err); err == nil {
@@ -233,7 +362,7 @@ func (tr *websocketTransport) Write(data []byte) error {
 				tr.writeMtx.Lock()
 			} else {
 				tr.log.Debugf("Failed to write message: %v, retrying...", err)
-				time.Sleep(time.Second)
+				tr.clock.Sleep(time.Second)
 			}
 		} else {
 			tr.writeMtx.Unlock()
@@ -241,5 +370,5 @@ func (tr *websocketTransport) Write(data []byte) error {
 		}
 	}
 	tr.writeMtx.Unlock()
-	return common.NewGameLiftError(common.WebsocketSendMessageFailure, "Failed write data", err.Error())
+	return common.NewGameLiftErrorWithCause(common.WebsocketSendMessageFailure, "Failed write data", "", err)
 }
@@ -10,10 +10,16 @@ import (
 	"aws/amazon-gamelift-go-sdk/server/internal/mock"
 	"bytes"
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -22,6 +28,7 @@ import (
 	"go.uber.org/goleak"
 
 	"aws/amazon-gamelift-go-sdk/server/internal/transport"
+	"aws/amazon-gamelift-go-sdk/server/metrics"
 )
 
 var retryableErrorTypes = [...]error{&websocket.CloseError{Code: websocket.CloseAbnormalClosure}, errors.New("example propogated error")}
@@ -47,13 +54,23 @@ func expectCloseTimes(times int, logger *mock.MockILogger, conn *mock.MockConn)
 }
 
 func createMockWebsocket(t *testing.T) (transport.ITransport, *mock.MockDialer, *mock.MockConn, *mock.MockILogger) {
+	tr, dialer, conn, logger, _ := createMockWebsocketWithClock(t)
+	return tr, dialer, conn, logger
+}
+
+func createMockWebsocketWithClock(t *testing.T) (transport.ITransport, *mock.MockDialer, *mock.MockConn, *mock.MockILogger, *mock.FakeClock) {
+	return createMockWebsocketWithConfig(t, transport.WebsocketConfig{})
+}
+
+func createMockWebsocketWithConfig(t *testing.T, cfg transport.WebsocketConfig) (transport.ITransport, *mock.MockDialer, *mock.MockConn, *mock.MockILogger, *mock.FakeClock) {
 	ctrl := gomock.NewController(t)
 	dialer := mock.NewMockDialer(ctrl)
 	conn := mock.NewMockConn(ctrl)
 	logger := mock.NewMockILogger(ctrl)
-	tr := transport.Websocket(logger, dialer)
+	clock := mock.NewFakeClock(time.Now())
+	tr := transport.Websocket(logger, metrics.GetDefaultMetrics(), dialer, clock, cfg)
 	logger.EXPECT().Debugf("read goroutine %d: ending", gomock.Any()).AnyTimes()
-	return tr, dialer, conn, logger
+	return tr, dialer, conn, logger, clock
 }
 
 func expectConnectTimes(times int, logger *mock.MockILogger, dialer *mock.MockDialer, conn *mock.MockConn) {
@@ -108,9 +125,9 @@ func TestWebsocketWriteRefreshConnection(t *testing.T) {
 	var connectionsRefreshedWaitGroup sync.WaitGroup
 	refreshConnection := func(t *testing.T, tr transport.ITransport) {
 		defer connectionsRefreshedWaitGroup.Done()
-		err := tr.Connect(addr)
+		err := tr.Connect(addr, nil)
 		if err != nil {
-			t.Fatalf("websocket connect: %v", err)
+			t.Errorf("websocket connect: %v", err)
 		}
 	}
 
@@ -155,12 +172,9 @@ func TestWebsocketTransportRead(t *testing.T) {
 			ReadMessage().
 			Return(-1, nil, &websocket.CloseError{Code: websocket.CloseNormalClosure}),
 	)
-	var handlerCalled common.AtomicBool
+	received := make(chan []byte, 1)
 	tr.SetReadHandler(func(data []byte) {
-		handlerCalled.Store(true)
-		if string(data) != testMessage {
-			t.Fatalf("unexpected message: %s", data)
-		}
+		received <- data
 	})
 
 	// EXPECT
@@ -168,19 +182,19 @@ func TestWebsocketTransportRead(t *testing.T) {
 	expectCloseTimes(1, logger, conn)
 
 	// WHEN
-	err = tr.Connect(addr)
+	err = tr.Connect(addr, nil)
 	if err != nil {
 		t.Fatalf("websocket connect: %v", err)
 	}
-	time.Sleep(time.Millisecond) // wait for read handler
+	data := <-received // wait for read handler
 	err = tr.Close()
 	if err != nil {
 		t.Fatalf("websocket close connection: %v", err)
 	}
 
 	// THEN
-	if !handlerCalled.Load() {
-		t.Fatalf("handler was not called")
+	if string(data) != testMessage {
+		t.Fatalf("unexpected message: %s", data)
 	}
 }
 
@@ -207,7 +221,7 @@ func TestWebsocketTransportWrite(t *testing.T) {
 		WriteMessage(websocket.TextMessage, []byte(testMessage))
 
 	// WHEN
-	err = tr.Connect(addr)
+	err = tr.Connect(addr, nil)
 	if err != nil {
 		t.Fatalf("websocket connect: %v", err)
 	}
@@ -228,7 +242,7 @@ func TestWebsocketRetryConnection(t *testing.T) {
 	if err != nil {
 		t.Fatalf("parse url: %s", err)
 	}
-	tr, dialer, conn, logger := createMockWebsocket(t)
+	tr, dialer, conn, logger, clock := createMockWebsocketWithClock(t)
 
 	// Mock failed connection attempt
 	errorResponse := new(http.Response)
@@ -275,7 +289,15 @@ func TestWebsocketRetryConnection(t *testing.T) {
 		Debugf("Response body is: %s", gomock.Any())
 
 	// WHEN
-	err = tr.Connect(addr)
+	result := make(chan error)
+	go func() {
+		result <- tr.Connect(addr, nil)
+	}()
+	// The first two backoff intervals are skipped, so the first retry happens after 4 * ConnectRetryInterval
+	clock.BlockUntil(1)
+	clock.Advance(4 * common.ConnectRetryInterval)
+
+	err = <-result
 	if err != nil {
 		t.Fatalf("websocket connect: %v", err)
 	}
@@ -300,6 +322,8 @@ func TestWebsocketReconnectOnClose_Read(t *testing.T) {
 		tr.SetReadHandler(func(data []byte) {
 			handlerCalled.Store(true)
 		})
+		var closed sync.WaitGroup
+		closed.Add(3)
 
 		// Mock network interrupt on read
 		gomock.InOrder(
@@ -314,15 +338,16 @@ func TestWebsocketReconnectOnClose_Read(t *testing.T) {
 		logger.EXPECT().Errorf("read goroutine %d: Websocket readProcess failed: %v", gomock.Any(), gomock.Any())
 		logger.EXPECT().Warnf("Detected network interruption %s! Reconnecting...", gomock.Any())
 		logger.EXPECT().Debugf("Close websocket connection").Times(1)
-		conn.EXPECT().Close().Times(3)
+		conn.EXPECT().Close().Do(closed.Done).Times(3)
 
 		// WHEN
-		err = tr.Connect(addr)
+		err = tr.Connect(addr, nil)
 		if err != nil {
 			t.Fatalf("websocket connect: %v", err)
 		}
 
-		time.Sleep(5 * time.Second)
+		// Both read goroutines and the reconnect close the underlying connection
+		closed.Wait()
 
 		// THEN
 		if handlerCalled.Load() {
@@ -340,7 +365,7 @@ func TestWebsocketReconnectOnClose_Write(t *testing.T) {
 		if err != nil {
 			t.Fatalf("parse url: %s", err)
 		}
-		tr, dialer, conn, logger := createMockWebsocket(t)
+		tr, dialer, conn, logger, clock := createMockWebsocketWithClock(t)
 
 		// Mock network interrupt on write
 		gomock.InOrder(
@@ -362,14 +387,24 @@ func TestWebsocketReconnectOnClose_Write(t *testing.T) {
 		logger.EXPECT().Debugf("Failed to write message: %v, retrying...", gomock.Any()).Times(common.ReconnectOnReadWriteFailureNumber)
 
 		// WHEN
-		err = tr.Connect(addr)
+		err = tr.Connect(addr, nil)
 		if err != nil {
 			t.Fatalf("websocket connect: %v", err)
 		}
 
 		conn.Close()
 
-		err = tr.Write([]byte(testMessage))
+		result := make(chan error)
+		go func() {
+			result <- tr.Write([]byte(testMessage))
+		}()
+		// Write waits one second between failed attempts until it falls back to reconnect
+		for i := 0; i < common.ReconnectOnReadWriteFailureNumber; i++ {
+			clock.BlockUntil(1)
+			clock.Advance(time.Second)
+		}
+
+		err = <-result
 		if err != nil {
 			t.Fatalf("fail to write to transport: %v", err)
 		}
@@ -406,7 +441,7 @@ func TestWebsocketWriteDoesNotReconnectForNormalClosure(t *testing.T) {
 	expectCloseTimes(1, logger, conn)
 
 	// WHEN
-	err = tr.Connect(addr)
+	err = tr.Connect(addr, nil)
 	if err != nil {
 		t.Fatalf("websocket connect: %v", err)
 	}
@@ -432,10 +467,15 @@ func TestWebsocketReadDoesNotReconnectForNormalClosure(t *testing.T) {
 	tr, dialer, conn, logger := createMockWebsocket(t)
 
 	// Mock websocket closed on read
+	read := make(chan struct{})
+	var readOnce sync.Once
 	conn.
 		EXPECT().
 		ReadMessage().
-		Return(-1, nil, &websocket.CloseError{Code: websocket.CloseNormalClosure}).
+		DoAndReturn(func() (int, []byte, error) {
+			readOnce.Do(func() { close(read) })
+			return -1, nil, &websocket.CloseError{Code: websocket.CloseNormalClosure}
+		}).
 		AnyTimes()
 
 	// EXPECT
@@ -443,11 +483,11 @@ func TestWebsocketReadDoesNotReconnectForNormalClosure(t *testing.T) {
 	expectCloseTimes(1, logger, conn)
 
 	// WHEN
-	err = tr.Connect(addr)
+	err = tr.Connect(addr, nil)
 	if err != nil {
 		t.Fatalf("websocket connect: %v", err)
 	}
-	time.Sleep(10 * time.Second) // wait for read handler
+	<-read // wait for read handler
 
 	err = tr.Close()
 	if err != nil {
@@ -491,7 +531,7 @@ func TestWebsocketConcurrentReconnectConnectsOnce(t *testing.T) {
 	expectCloseTimes(2, logger, conn)
 
 	// WHEN
-	err = tr.Connect(addr)
+	err = tr.Connect(addr, nil)
 	if err != nil {
 		t.Fatalf("websocket connect: %v", err)
 	}
@@ -504,5 +544,320 @@ func TestWebsocketConcurrentReconnectConnectsOnce(t *testing.T) {
 	if err != nil {
 		t.Fatalf("websocket close connection: %v", err)
 	}
-	time.Sleep(2 * time.Second)
+}
+
+func TestWebsocketReconnectSignsURLAgain(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	addr, err := url.Parse(rawAddr)
+	if err != nil {
+		t.Fatalf("parse url: %s", err)
+	}
+	tr, dialer, conn, logger := createMockWebsocket(t)
+	var signatures int
+	signer := func(u *url.URL) (*url.URL, error) {
+		signatures++
+		signed := *u
+		signed.RawQuery = fmt.Sprintf("signature=%d", signatures)
+		return &signed, nil
+	}
+
+	// EXPECT
+	gomock.InOrder(
+		dialer.EXPECT().
+			Dial(rawAddr+"?signature=1", http.Header{"User-Agent": []string{"gamelift-go-sdk/1.0"}}).
+			Return(conn, new(http.Response), error(nil)),
+		dialer.EXPECT().
+			Dial(rawAddr+"?signature=2", http.Header{"User-Agent": []string{"gamelift-go-sdk/1.0"}}).
+			Return(conn, new(http.Response), error(nil)),
+	)
+	conn.EXPECT().CloseHandler().Return(noopCloseHandler).Times(2)
+	conn.EXPECT().SetCloseHandler(gomock.Any()).Times(2)
+	logger.EXPECT().Debugf("Establishing websocket connection").Times(2)
+	conn.
+		EXPECT().
+		ReadMessage().
+		Return(-1, nil, &websocket.CloseError{Code: websocket.CloseNormalClosure}).
+		AnyTimes()
+	expectCloseTimes(2, logger, conn)
+
+	// WHEN
+	if err = tr.Connect(addr, signer); err != nil {
+		t.Fatalf("websocket connect: %v", err)
+	}
+	if err = tr.Reconnect(); err != nil {
+		t.Fatalf("Reconnect failed: %v", err)
+	}
+
+	// THEN
+	if err = tr.Close(); err != nil {
+		t.Fatalf("websocket close connection: %v", err)
+	}
+}
+
+var keepAliveConfig = transport.WebsocketConfig{
+	PingInterval: 30 * time.Second,
+	PongTimeout:  10 * time.Second,
+}
+
+func TestWebsocketKeepAliveSendsPings(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	addr, err := url.Parse(rawAddr)
+	if err != nil {
+		t.Fatalf("parse url: %s", err)
+	}
+	tr, dialer, conn, logger, clock := createMockWebsocketWithConfig(t, keepAliveConfig)
+	start := clock.Now()
+	deadline := keepAliveConfig.PingInterval + keepAliveConfig.PongTimeout
+
+	pongHandlers := make(chan func(string) error, 1)
+	pinged := make(chan struct{})
+	release := make(chan struct{})
+
+	// EXPECT
+	expectConnectTimes(1, logger, dialer, conn)
+	expectCloseTimes(1, logger, conn)
+	conn.
+		EXPECT().
+		SetPongHandler(gomock.Any()).
+		Do(func(h func(string) error) { pongHandlers <- h })
+	gomock.InOrder(
+		conn.EXPECT().SetReadDeadline(start.Add(deadline)).Return(nil),
+		conn.EXPECT().SetReadDeadline(start.Add(keepAliveConfig.PingInterval+deadline)).Return(nil),
+	)
+	conn.
+		EXPECT().
+		WriteControl(websocket.PingMessage, nil, start.Add(deadline)).
+		Do(func(int, []byte, time.Time) { close(pinged) }).
+		Return(nil)
+	conn.
+		EXPECT().
+		ReadMessage().
+		DoAndReturn(func() (int, []byte, error) {
+			<-release
+			return -1, nil, &websocket.CloseError{Code: websocket.CloseNormalClosure}
+		})
+
+	// WHEN
+	err = tr.Connect(addr, nil)
+	if err != nil {
+		t.Fatalf("websocket connect: %v", err)
+	}
+	pongHandler := <-pongHandlers
+	clock.BlockUntil(1)
+	clock.Advance(keepAliveConfig.PingInterval)
+	<-pinged
+
+	// THEN
+	if err = pongHandler(""); err != nil {
+		t.Fatalf("pong handler failed: %v", err)
+	}
+	close(release)
+	err = tr.Close()
+	if err != nil {
+		t.Fatalf("websocket close connection: %v", err)
+	}
+}
+
+func TestWebsocketKeepAliveReconnectsOnSilentPeer(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	addr, err := url.Parse(rawAddr)
+	if err != nil {
+		t.Fatalf("parse url: %s", err)
+	}
+	tr, dialer, conn, logger, _ := createMockWebsocketWithConfig(t, keepAliveConfig)
+	var closed sync.WaitGroup
+	closed.Add(3)
+
+	// Mock expired read deadline
+	gomock.InOrder(
+		conn.EXPECT().ReadMessage().Return(-1, nil, &net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}),
+		conn.
+			EXPECT().
+			ReadMessage().
+			Return(-1, nil, &websocket.CloseError{Code: websocket.CloseNormalClosure}).AnyTimes())
+
+	// EXPECT
+	expectConnectTimes(2, logger, dialer, conn)
+	conn.EXPECT().SetPongHandler(gomock.Any()).Times(2)
+	conn.EXPECT().SetReadDeadline(gomock.Any()).Return(nil).Times(2)
+	logger.EXPECT().Warnf("read goroutine %d: No message received within %s, connection is considered dead",
+		gomock.Any(), keepAliveConfig.PingInterval+keepAliveConfig.PongTimeout)
+	logger.EXPECT().Errorf("read goroutine %d: Websocket readProcess failed: %v", gomock.Any(), gomock.Any())
+	logger.EXPECT().Warnf("Detected network interruption %s! Reconnecting...", gomock.Any())
+	logger.EXPECT().Debugf("Close websocket connection").Times(1)
+	conn.EXPECT().Close().Do(closed.Done).Times(3)
+
+	// WHEN
+	err = tr.Connect(addr, nil)
+	if err != nil {
+		t.Fatalf("websocket connect: %v", err)
+	}
+
+	// THEN
+	// Both read goroutines and the reconnect close the underlying connection
+	closed.Wait()
+}
+
+// startEchoServer starts a websocket server that sends every received message back.
+// The extensions requested by the clients are reported to the returned channel.
+func startEchoServer() (*httptest.Server, <-chan string) {
+	extensions := make(chan string, 10)
+	upgrader := websocket.Upgrader{EnableCompression: true}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		extensions <- r.Header.Get("Sec-Websocket-Extensions")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			t, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err = conn.WriteMessage(t, msg); err != nil {
+				return
+			}
+		}
+	}))
+	return srv, extensions
+}
+
+// largePayload returns a JSON message of approximately the specified size.
+func largePayload(size int) []byte {
+	var b bytes.Buffer
+	b.WriteString(`{"PlayerSessions":[`)
+	for i := 0; b.Len() < size; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"PlayerSessionId":"psess-%08d","PlayerId":"player-%08d","Status":"ACTIVE"}`, i, i)
+	}
+	b.WriteString("]}")
+	return b.Bytes()
+}
+
+func TestWebsocketLargeMessageRoundTrip(t *testing.T) {
+	for _, compression := range []bool{false, true} {
+		t.Run(fmt.Sprintf("compression=%t", compression), func(t *testing.T) {
+			// GIVEN
+			defer goleak.VerifyNone(t)
+			srv, extensions := startEchoServer()
+			defer srv.Close()
+			addr, err := url.Parse("ws://" + srv.Listener.Addr().String())
+			if err != nil {
+				t.Fatalf("parse url: %s", err)
+			}
+			payload := largePayload(4 * 1024 * 1024)
+
+			ctrl := gomock.NewController(t)
+			logger := mock.NewMockILogger(ctrl)
+			logger.EXPECT().Debugf(gomock.Any(), gomock.Any()).AnyTimes()
+			metricsMock := mock.NewMockIMetrics(ctrl)
+			dialer, err := transport.NewDialer(nil, transport.DialerConfig{EnableCompression: compression})
+			if err != nil {
+				t.Fatalf("create dialer: %v", err)
+			}
+			tr := transport.Websocket(logger, metricsMock, dialer, common.NewRealClock(), transport.WebsocketConfig{
+				ReadLimit:        8 * 1024 * 1024,
+				LargeMessageSize: 1024 * 1024,
+			})
+			received := make(chan []byte, 1)
+			tr.SetReadHandler(func(data []byte) {
+				received <- data
+			})
+
+			// EXPECT
+			metricsMock.EXPECT().Observe(metrics.MessageSentBytes, float64(len(payload)))
+			metricsMock.EXPECT().Observe(metrics.MessageReceivedBytes, float64(len(payload)))
+			logger.EXPECT().Warnf("Sending large message of %d bytes", len(payload))
+			logger.EXPECT().Warnf("read goroutine %d: Received large message of %d bytes", gomock.Any(), len(payload))
+			// Reading from the closed connection fails at the end of the test
+			logger.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+
+			// WHEN
+			if err = tr.Connect(addr, nil); err != nil {
+				t.Fatalf("websocket connect: %v", err)
+			}
+			if err = tr.Write(payload); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+			data := <-received
+			if err = tr.Close(); err != nil {
+				t.Fatalf("websocket close connection: %v", err)
+			}
+
+			// THEN
+			if !bytes.Equal(data, payload) {
+				t.Fatalf("received message differs from the sent one: %d bytes instead of %d", len(data), len(payload))
+			}
+			if negotiated := strings.Contains(<-extensions, "permessage-deflate"); negotiated != compression {
+				t.Fatalf("unexpected compression negotiation: %t", negotiated)
+			}
+		})
+	}
+}
+
+func TestWebsocketReadLimitExceededReconnects(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	var connections int32
+	connected := make(chan struct{}, 2)
+	var upgrader websocket.Upgrader
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		connected <- struct{}{}
+		// Only the first connection receives a message that is too large
+		if atomic.AddInt32(&connections, 1) == 1 {
+			_ = conn.WriteMessage(websocket.TextMessage, largePayload(2*1024*1024))
+		}
+		for {
+			if _, _, err = conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+	addr, err := url.Parse("ws://" + srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("parse url: %s", err)
+	}
+
+	ctrl := gomock.NewController(t)
+	logger := mock.NewTestLogger(t, ctrl)
+	metricsMock := mock.NewMockIMetrics(ctrl)
+	dialer, err := transport.NewDialer(nil, transport.DialerConfig{})
+	if err != nil {
+		t.Fatalf("create dialer: %v", err)
+	}
+	tr := transport.Websocket(logger, metricsMock, dialer, common.NewRealClock(), transport.WebsocketConfig{ReadLimit: 1024 * 1024})
+
+	// EXPECT
+	metricsMock.EXPECT().IncrCounter(metrics.ReadLimitExceeded, int64(1))
+	metricsMock.EXPECT().Observe(metrics.MessageSentBytes, gomock.Any())
+	logger.EXPECT().Errorf("read goroutine %d: Received message exceeds the read limit of %d bytes", gomock.Any(), int64(1024*1024))
+	logger.EXPECT().Errorf("read goroutine %d: Websocket readProcess failed: %v", gomock.Any(), gomock.Any()).MinTimes(1)
+
+	// WHEN
+	if err = tr.Connect(addr, nil); err != nil {
+		t.Fatalf("websocket connect: %v", err)
+	}
+	<-connected
+	<-connected
+
+	// THEN
+	// Write waits for the ongoing reconnect to finish
+	if err = tr.Write([]byte(testMessage)); err != nil {
+		t.Fatalf("write after reconnect failed: %v", err)
+	}
+	if err = tr.Close(); err != nil {
+		t.Fatalf("websocket close connection: %v", err)
+	}
 }
@@ -15,6 +15,7 @@ import (
 	"aws/amazon-gamelift-go-sdk/model/message"
 	"aws/amazon-gamelift-go-sdk/server/internal/transport"
 	"aws/amazon-gamelift-go-sdk/server/log"
+	"aws/amazon-gamelift-go-sdk/server/metrics"
 )
 
 var (
@@ -31,32 +32,56 @@ type websocketClient struct {
 	handleMtx     sync.RWMutex
 	responses     map[string]chan<- common.Outcome
 	asyncHandlers map[message.MessageAction]func([]byte)
+	// unknownHandler - handler of the messages with an unknown action, nil if not set.
+	unknownHandler func(action message.MessageAction, data []byte)
+	dispatcher     *dispatcher
+}
+
+// inboundMessage - the fields of an inbound message used to route it.
+type inboundMessage struct {
+	message.ResponseMessage
+	GameSessionID string `json:"GameSessionId"`
+	GameSession   struct {
+		GameSessionID string `json:"GameSessionId"`
+	} `json:"GameSession"`
+}
+
+// orderingKey - returns the ID of the game session of the message, or an empty string for process-wide messages.
+func (m *inboundMessage) orderingKey() string {
+	if m.GameSessionID != "" {
+		return m.GameSessionID
+	}
+	return m.GameSession.GameSessionID
 }
 
 // GetWebsocketClient - return an implementation of IWebSocketClient.
 func GetWebsocketClient(
 	iTransport transport.ITransport,
 	l log.ILogger,
+	m metrics.IMetrics,
+	cfg DispatcherConfig,
 ) IWebSocketClient {
 	initWebsocketOnce.Do(func() {
-		gameliftWebsocket.init(iTransport, l)
+		gameliftWebsocket.init(iTransport, l, m, cfg)
 	})
 
 	return &gameliftWebsocket
 }
 
-func (c *websocketClient) init(iTransport transport.ITransport, l log.ILogger) {
+func (c *websocketClient) init(iTransport transport.ITransport, l log.ILogger, m metrics.IMetrics, cfg DispatcherConfig) {
 	c.iTransport = iTransport
 	c.log = l
+	c.dispatcher = newDispatcher(l, m, cfg)
 	c.responses = make(map[string]chan<- common.Outcome)
 	c.asyncHandlers = make(map[message.MessageAction]func([]byte))
-	c.iTransport.SetReadHandler(gameliftWebsocket.readHandler)
+	c.iTransport.SetReadHandler(c.readHandler)
 }
 
 // Connect creates a websocket connection with the specified address.
+// If signer is not nil, the address is signed again on every connection attempt, including reconnects.
 // All Send calls before Connect call will return an error.
-func (c *websocketClient) Connect(connectURL *url.URL) error {
-	if err := c.iTransport.Connect(connectURL); err != nil {
+func (c *websocketClient) Connect(connectURL *url.URL, signer transport.URLSigner) error {
+	if err := c.iTransport.Connect(connectURL, signer); err != nil {
 		return err
 	}
 	c.log.Debugf("Connected to GameLift API Gateway.")
@@ -90,10 +115,10 @@ func (c *websocketClient) SendRequest(req MessageGetter, resp chan<- common.Outc
 func (c *websocketClient) SendMessage(msg any) error {
 	data, err := json.Marshal(msg)
 	if err != nil {
-		return common.NewGameLiftError(common.ServiceCallFailed, "Failed serialize data", err.Error())
+		return common.NewGameLiftErrorWithCause(common.ServiceCallFailed, "Failed serialize data", "", err)
 	}
 	if err = c.iTransport.Write(data); err != nil {
-		return common.NewGameLiftError(common.ServiceCallFailed, "Failed write data", err.Error())
+		return common.NewGameLiftErrorWithCause(common.ServiceCallFailed, "Failed write data", "", err)
 	}
 	return nil
 }
@@ -105,6 +130,14 @@ func (c *websocketClient) AddHandler(action message.MessageAction, handler func(
 	c.asyncHandlers[action] = handler
 }
 
+// SetUnknownMessageHandler allows to register the handler of incoming messages whose action is unknown to the SDK
+// and which are not a response to a pending request.
+func (c *websocketClient) SetUnknownMessageHandler(handler func(action message.MessageAction, data []byte)) {
+	c.handleMtx.Lock()
+	defer c.handleMtx.Unlock()
+	c.unknownHandler = handler
+}
+
 // CancelRequest allows to cancel request if the request time duration was expire.
 func (c *websocketClient) CancelRequest(requestID string) {
 	c.sendResponse(requestID, nil, nil)
@@ -129,16 +162,44 @@ func (c *websocketClient) getHandlerByAction(action message.MessageAction) (func
 	return handler, ok
 }
 
+// readHandler - dispatches an inbound message. Messages with a handler, such as CreateGameSession, are handled
+// in the order they were received per game session, and unknown messages are handled concurrently.
+// Responses are passed to the pending requests without waiting for the dispatcher, so handlers waiting for
+// a response are not blocked by the dispatcher limits.
 func (c *websocketClient) readHandler(data []byte) {
 	// Try to find Action and RequestId in received data
-	var resp message.ResponseMessage
-	if err := json.Unmarshal(data, &resp); err != nil {
+	var msg inboundMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
 		c.log.Warnf("Failed %s when try deserialize response", err.Error())
 		return
 	}
 
-	c.log.Debugf("Received %s for GameLift with status %d.", resp.Action, resp.StatusCode)
+	c.log.Debugf("Received %s for GameLift with status %d.", msg.Action, msg.StatusCode)
+
+	if msg.StatusCode == http.StatusOK || msg.RequestID == "" {
+		if handler, ok := c.getHandlerByAction(msg.Action); ok {
+			c.dispatcher.dispatchOrdered(msg.Action, msg.orderingKey(), func() { handler(data) })
+			return
+		}
+	}
+	if c.handleResponse(&msg.ResponseMessage, data) {
+		return
+	}
 
+	// Neither a handled message nor a response to a pending request, e.g. a message of a newer GameLift service
+	if msg.Action.IsKnown() {
+		return
+	}
+	c.handleMtx.RLock()
+	unknownHandler := c.unknownHandler
+	c.handleMtx.RUnlock()
+	if unknownHandler != nil {
+		c.dispatcher.dispatch(msg.Action, func() { unknownHandler(msg.Action, data) })
+	}
+}
+
+// handleResponse - passes the response to the pending request, returns false if there is no such request.
+func (c *websocketClient) handleResponse(resp *message.ResponseMessage, data []byte) bool {
 	if resp.StatusCode != http.StatusOK && resp.RequestID != "" {
 		c.log.Warnf(
 			"Received unsuccessful status code %d for request %s with message %q",
@@ -147,16 +208,9 @@ func (c *websocketClient) readHandler(data []byte) {
 			resp.ErrorMessage,
 		)
 		err := common.NewGameLiftErrorFromStatusCode(resp.StatusCode, resp.ErrorMessage)
-		c.sendResponse(resp.RequestID, data, err)
-		return
-	}
-
-	if handler, ok := c.getHandlerByAction(resp.Action); ok {
-		handler(data)
-		return
+		return c.sendResponse(resp.RequestID, data, err)
 	}
-
-	c.sendResponse(resp.RequestID, data, nil)
+	return c.sendResponse(resp.RequestID, data, nil)
 }
 
 func (c *websocketClient) storeResponse(requestID string, resp chan<- common.Outcome) error {
@@ -170,17 +224,20 @@ func (c *websocketClient) storeResponse(requestID string, resp chan<- common.Out
 	return nil
 }
 
-func (c *websocketClient) sendResponse(requestID string, data []byte, err error) {
+// sendResponse - passes the response to the pending request, returns false if there is no such request.
+// It doesn't block, as the channels of the requests are buffered and receive at most one response.
+func (c *websocketClient) sendResponse(requestID string, data []byte, err error) bool {
 	c.respMtx.Lock()
 	defer c.respMtx.Unlock()
 	resp, ok := c.responses[requestID]
 	if !ok {
 		c.log.Debugf("Response received for message with ID: %s", requestID)
-		return
+		return false
 	}
 	if data != nil {
 		resp <- common.Outcome{Data: data, Error: err}
 	}
 	close(resp)
 	delete(c.responses, requestID)
+	return true
 }
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -21,6 +22,7 @@ import (
 	"aws/amazon-gamelift-go-sdk/model/request"
 	"aws/amazon-gamelift-go-sdk/server/internal"
 	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+	"aws/amazon-gamelift-go-sdk/server/internal/transport"
 )
 
 const rawAddr = "https://example.test"
@@ -60,7 +62,7 @@ func TestWebsocketClientSendRequest(t *testing.T) {
 
 	transportMock.
 		EXPECT().
-		Connect(addr)
+		Connect(addr, nil)
 
 	transportMock.
 		EXPECT().
@@ -70,7 +72,7 @@ func TestWebsocketClientSendRequest(t *testing.T) {
 		EXPECT().
 		Close()
 
-	if err := c.Connect(addr); err != nil {
+	if err := c.Connect(addr, nil); err != nil {
 		t.Fatal(err)
 	}
 
@@ -137,7 +139,7 @@ func TestWebsocketClientHandler(t *testing.T) {
 
 	transportMock.
 		EXPECT().
-		Connect(addr)
+		Connect(addr, nil)
 
 	const (
 		createGameSessionRequest = `{"Action": "CreateGameSession"}`
@@ -166,7 +168,7 @@ func TestWebsocketClientHandler(t *testing.T) {
 		EXPECT().
 		Close()
 
-	if err := c.Connect(addr); err != nil {
+	if err := c.Connect(addr, nil); err != nil {
 		t.Fatal(err)
 	}
 
@@ -227,3 +229,218 @@ func TestWebsocketClientHandlerError(t *testing.T) {
 		t.Fatalf("unexpected error %s, want %s", result.Error, expectedError)
 	}
 }
+
+// GIVEN messages with unknown and known actions WHEN they are read
+// THEN only unknown actions which are not responses to pending requests are passed to the unknown message handler
+func TestWebsocketClientUnknownMessageHandler(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctrl := gomock.NewController(t)
+
+	logger := mock.NewTestLogger(t, ctrl)
+	transportMock := mock.NewMockITransport(ctrl)
+
+	c := new(internal.WebsocketClient)
+	transportMock.
+		EXPECT().
+		SetReadHandler(gomock.Not(gomock.Nil())) // we can't compare functions
+
+	c.Init(transportMock, logger)
+
+	var unknownActions []message.MessageAction
+	var unknownData []string
+	c.SetUnknownMessageHandler(func(action message.MessageAction, data []byte) {
+		unknownActions = append(unknownActions, action)
+		unknownData = append(unknownData, string(data))
+	})
+
+	transportMock.
+		EXPECT().
+		Write([]byte(testRequestJSON))
+
+	respCh := make(chan common.Outcome, 1)
+	if err := c.SendRequest(testRequest, respCh); err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		unknownMessage     = `{"Action":"DrainGameSession","GameSessionId":"test-game-session-id"}`
+		lateResponse       = `{"Action":"DescribePlayerSessions","RequestId":"late-request-id"}`
+		unknownResponse    = `{"Action":"FutureResponse","RequestId":"test-request-id"}`
+		unknownWithRequest = `{"Action":"FutureNotification","RequestId":"unknown-request-id"}`
+	)
+
+	c.RunReadHandler([]byte(unknownMessage))
+	c.RunReadHandler([]byte(lateResponse))
+	c.RunReadHandler([]byte(unknownResponse))
+	c.RunReadHandler([]byte(unknownWithRequest))
+
+	if result := <-respCh; string(result.Data) != unknownResponse {
+		t.Fatalf("unexpected response %s", result.Data)
+	}
+	expectedActions := []message.MessageAction{"DrainGameSession", "FutureNotification"}
+	if !reflect.DeepEqual(unknownActions, expectedActions) {
+		t.Fatalf("unexpected unknown actions %v, want %v", unknownActions, expectedActions)
+	}
+	expectedData := []string{unknownMessage, unknownWithRequest}
+	if !reflect.DeepEqual(unknownData, expectedData) {
+		t.Fatalf("unexpected unknown messages %v, want %v", unknownData, expectedData)
+	}
+}
+
+// GIVEN all workers busy with unknown messages WHEN a response is read
+// THEN it is passed to the pending request without waiting for a free worker
+func TestWebsocketClientResponseWithBusyWorkers(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	logger := mock.NewTestLogger(t, ctrl)
+	transportMock := mock.NewMockITransport(ctrl)
+
+	var read transport.ReadHandler
+	transportMock.
+		EXPECT().
+		SetReadHandler(gomock.Not(gomock.Nil())).
+		Do(func(handler transport.ReadHandler) { read = handler })
+	transportMock.
+		EXPECT().
+		Write([]byte(testRequestJSON))
+
+	c := new(internal.WebsocketClient)
+	c.Init(transportMock, logger)
+	release := make(chan struct{})
+	c.SetUnknownMessageHandler(func(message.MessageAction, []byte) { <-release })
+	for i := 0; i < internal.TestDispatcherConfig.Workers; i++ {
+		read([]byte(`{"Action":"DrainGameSession"}`))
+	}
+
+	respCh := make(chan common.Outcome, 1)
+	if err := c.SendRequest(testRequest, respCh); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	const response = `{"Action":"DescribePlayerSessions","RequestId":"test-request-id","StatusCode":200}`
+	read([]byte(response))
+
+	// THEN
+	result := <-respCh
+	close(release)
+	c.WaitHandlers()
+	if string(result.Data) != response {
+		t.Fatalf("unexpected response %s", result.Data)
+	}
+}
+
+// GIVEN a lifecycle handler waiting for a response WHEN more messages of its game session than the queue size
+// and then the response are read THEN the reader is not blocked, the response is delivered and all messages are handled
+func TestWebsocketClientResponseWithFullQueue(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	logger := mock.NewTestLogger(t, ctrl)
+	transportMock := mock.NewMockITransport(ctrl)
+
+	var read transport.ReadHandler
+	transportMock.
+		EXPECT().
+		SetReadHandler(gomock.Not(gomock.Nil())).
+		Do(func(handler transport.ReadHandler) { read = handler })
+	transportMock.
+		EXPECT().
+		Write([]byte(testRequestJSON))
+
+	c := new(internal.WebsocketClient)
+	c.Init(transportMock, logger)
+
+	respCh := make(chan common.Outcome, 1)
+	if err := c.SendRequest(testRequest, respCh); err != nil {
+		t.Fatal(err)
+	}
+	var result common.Outcome
+	handled := 0
+	c.AddHandler(message.UpdateGameSession, func([]byte) {
+		if handled == 0 {
+			result = <-respCh
+		}
+		handled++
+	})
+
+	// WHEN
+	const update = `{"Action":"UpdateGameSession","GameSessionId":"gsess-1"}`
+	messages := internal.TestDispatcherConfig.QueueSize + 2
+	for i := 0; i < messages; i++ {
+		read([]byte(update))
+	}
+	const response = `{"Action":"DescribePlayerSessions","RequestId":"test-request-id","StatusCode":200}`
+	read([]byte(response))
+
+	// THEN
+	c.WaitHandlers()
+	if string(result.Data) != response {
+		t.Fatalf("unexpected response %s", result.Data)
+	}
+	if handled != messages {
+		t.Fatalf("handled %d messages, want %d", handled, messages)
+	}
+}
+
+// GIVEN a recording of a game session lifecycle WHEN it is replayed to the websocket client
+// THEN the handlers receive the recorded messages in order, with the secrets redacted
+func TestWebsocketClientReplayRecording(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	logger := mock.NewTestLogger(t, ctrl)
+	createGameSessionHandler := mock.NewMockMessageHandler(ctrl)
+	updateGameSessionHandler := mock.NewMockMessageHandler(ctrl)
+	refreshConnectionHandler := mock.NewMockMessageHandler(ctrl)
+
+	const (
+		createGameSessionMessage = `{"Action":"CreateGameSession","GameSession":{"GameSessionId":"gsess-1"}}`
+		updateGameSessionMessage = `{"Action":"UpdateGameSession","GameSession":{"GameSessionId":"gsess-1"}}`
+		refreshConnectionMessage = `{"Action":"RefreshConnection","AuthToken":"REDACTED","RefreshConnectionEndpoint":"wss://example.test"}`
+	)
+	recording := `{"time":"2023-01-02T03:04:05Z","direction":"connect","url":"wss://example.test?Authorization=REDACTED"}
+{"time":"2023-01-02T03:04:05Z","direction":"out","data":{"Action":"ActivateServerProcess","RequestId":"1"}}
+{"time":"2023-01-02T03:04:06Z","direction":"in","data":` + createGameSessionMessage + `}
+{"time":"2023-01-02T03:04:07Z","direction":"in","data":` + updateGameSessionMessage + `}
+{"time":"2023-01-02T03:04:08Z","direction":"in","data":` + refreshConnectionMessage + `}
+`
+	replayTransport, err := transport.NewReplayTransport(strings.NewReader(recording))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := new(internal.WebsocketClient)
+	c.Init(replayTransport, logger)
+	c.AddHandler(message.CreateGameSession, createGameSessionHandler.OnMessage)
+	c.AddHandler(message.UpdateGameSession, updateGameSessionHandler.OnMessage)
+	c.AddHandler(message.RefreshConnection, refreshConnectionHandler.OnMessage)
+
+	addr, err := url.Parse(rawAddr)
+	if err != nil {
+		t.Fatalf("parse url: %s", err)
+	}
+
+	// EXPECT
+	gomock.InOrder(
+		createGameSessionHandler.EXPECT().OnMessage([]byte(createGameSessionMessage)),
+		updateGameSessionHandler.EXPECT().OnMessage([]byte(updateGameSessionMessage)),
+	)
+	refreshConnectionHandler.EXPECT().OnMessage([]byte(refreshConnectionMessage))
+
+	// WHEN
+	if err := c.Connect(addr, nil); err != nil {
+		t.Fatal(err)
+	}
+	c.WaitHandlers()
+
+	// THEN
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
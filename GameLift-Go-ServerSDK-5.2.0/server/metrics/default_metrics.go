@@ -0,0 +1,17 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package metrics
+
+type noopMetrics struct{}
+
+func (noopMetrics) IncrCounter(string, int64) {}
+
+func (noopMetrics) Observe(string, float64) {}
+
+// GetDefaultMetrics - returns a default metrics implementation that discards all metrics.
+func GetDefaultMetrics() IMetrics {
+	return noopMetrics{}
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+//go:generate mockgen -destination ../internal/mock/metrics.go -package=mock . IMetrics
+package metrics
+
+// IMetrics - interface that describes the metrics sink used by the GameLift SDK.
+//
+// To inject a custom implementation of this interface to the SDK please use server.SetMetricsInterface function.
+type IMetrics interface {
+	// IncrCounter - increments the counter with the specified name by delta.
+	IncrCounter(name string, delta int64)
+	// Observe - records a single observation of the metric with the specified name, e.g. the size of a message.
+	Observe(name string, value float64)
+}
+
+// Metric names reported by the GameLift SDK.
+const (
+	// MessageReceivedBytes - size of every message received from GameLift.
+	MessageReceivedBytes = "gamelift.websocket.message_received_bytes"
+	// MessageSentBytes - size of every message sent to GameLift.
+	MessageSentBytes = "gamelift.websocket.message_sent_bytes"
+	// ReadLimitExceeded - number of incoming messages that exceeded the configured read limit.
+	ReadLimitExceeded = "gamelift.websocket.read_limit_exceeded"
+	// UnknownMessages - number of received messages with an action unknown to the SDK.
+	UnknownMessages = "gamelift.websocket.unknown_messages"
+	// DispatcherQueueDepth - number of pending lifecycle messages of the game session when a new one is queued.
+	DispatcherQueueDepth = "gamelift.dispatcher.queue_depth"
+	// DispatcherBlocked - number of inbound messages queued above the limits of the dispatcher.
+	DispatcherBlocked = "gamelift.dispatcher.blocked"
+	// DispatcherPanics - number of panics recovered in the SDK handlers of inbound messages, see CallbackPanics.
+	DispatcherPanics = "gamelift.dispatcher.panics"
+	// CallbackPanics - number of panics recovered in the callbacks of ProcessParameters.
+	CallbackPanics = "gamelift.callback.panics"
+	// RequestAttempts - number of attempts of every idempotent request, including the first one.
+	RequestAttempts = "gamelift.request.attempts"
+	// RequestRetries - number of retries of idempotent requests.
+	RequestRetries = "gamelift.request.retries"
+)
@@ -6,7 +6,12 @@
 package server
 
 import (
+	"time"
+
 	"aws/amazon-gamelift-go-sdk/model"
+	"aws/amazon-gamelift-go-sdk/model/message"
+	"aws/amazon-gamelift-go-sdk/server/authtoken"
+	"aws/amazon-gamelift-go-sdk/server/credentials"
 )
 
 // ServerParameters - object communicating the following information about the server
@@ -18,10 +23,38 @@ import (
 //   - HostID - the ID of the compute hosting your game server processes.
 //   - FleetID - the ID of the GameLift fleet containing your Anywhere compute.
 //   - AuthToken - the authorization token generated by the GameLift operation.
+//   - AuthTokenProvider - the source of the authorization token if AuthToken is empty, e.g. authtoken.NewFileProvider(path).
+//     It is called before every connect and reconnect, so expired tokens are replaced.
 //   - AwsRegion - the AWS Region of the Game Server.
 //   - AccessKey - the AWS AccessKey of the AWS Credentials with GameLift Access.
 //   - SecretKey - the AWS SecretKey of the AWS Credentials with GameLift Access.
 //   - SessionToken - the AWS Token of the AWS Credentials with GameLift Access if using temporary credentials.
+//   - CredentialsProvider - the source of the AWS Credentials with GameLift Access if AccessKey and SecretKey are empty,
+//     e.g. credentials.NewDefaultChain(). Requires AwsRegion.
+//     On CONTAINER compute, AccessKey and SecretKey are ignored and the ECS container credentials are used
+//     unless CredentialsProvider is set.
+//   - ProxyURL - the URL of the proxy used to connect to GameLift. If empty, HTTPS_PROXY and NO_PROXY are used.
+//   - RootCAFile - the path to a PEM file with the root certificates to trust instead of the system ones.
+//   - ClientCertFile - the path to a PEM file with the client certificate, e.g. the path returned by GetComputeCertificate.
+//   - ClientKeyFile - the path to a PEM file with the client private key, if it is not stored in ClientCertFile.
+//   - MinTLSVersion - the minimum TLS version of the connection: "1.0", "1.1", "1.2" or "1.3".
+//   - EnableCompression - negotiate permessage-deflate compression of websocket messages with GameLift,
+//     sets Config.WebsocketCompression.
+//   - RecordFile - the path to a file to which every message sent to and received from GameLift is appended
+//     with its time, one JSON per line, to investigate misbehaving servers. Auth tokens, SigV4 signatures
+//     and AWS credentials are redacted. Recording is disabled if empty. The recording can be replayed in
+//     unit tests with ReplayRecording.
+//   - PlayerSessionCache - settings of the optional cache of DescribePlayerSessions lookups,
+//     set Config.PlayerSessionCacheTTL and Config.PlayerSessionCacheMaxEntries if non-zero.
+//   - RequestTimeouts - timeouts of the requests by action, e.g. longer for message.StartMatchBackfill.
+//     Defaults to 6 seconds for ActivateServerProcess and SERVICE_CALL_TIMEOUT for the others.
+//     Merged into Config.RequestTimeouts, which the REQUEST_TIMEOUTS environment variable overrides,
+//     e.g. "StartMatchBackfill=1m,HeartbeatServerProcess=5s".
+//   - RetryPolicies - retry policies of the idempotent requests by action, overriding the default policy.
+//     Only DescribePlayerSessions, GetComputeCertificate, GetFleetRoleCredentials and HeartbeatServerProcess
+//     are retried. A heartbeat is retried only if the retry can end before the next heartbeat.
+//   - Config - tuning of the SDK, DefaultConfig if nil. The GAMELIFT_SDK_CONFIG_FILE file and
+//     the environment variables override it, see Config.
 type ServerParameters struct {
 	WebSocketURL string
 	ProcessID    string
@@ -32,6 +65,50 @@ type ServerParameters struct {
 	AccessKey    string
 	SecretKey    string
 	SessionToken string
+
+	AuthTokenProvider   authtoken.Provider
+	CredentialsProvider credentials.Provider
+
+	ProxyURL       string
+	RootCAFile     string
+	ClientCertFile string
+	ClientKeyFile  string
+	MinTLSVersion  string
+
+	EnableCompression bool
+
+	RecordFile string
+
+	PlayerSessionCache PlayerSessionCacheParameters
+
+	RequestTimeouts map[message.MessageAction]time.Duration
+	RetryPolicies   map[message.MessageAction]RetryPolicy
+
+	Config *Config
+}
+
+// RetryPolicy - how an idempotent request is retried if GameLift doesn't respond in time or responds with a 5xx error.
+// Every retry is sent with a new RequestId after a random delay between zero and
+// min(MaxDelay, BaseDelay * 2^(retry-1)). Zero fields are replaced with Config.RequestRetry,
+// which can be set with the REQUEST_RETRY_MAX_ATTEMPTS, REQUEST_RETRY_BASE_DELAY and REQUEST_RETRY_MAX_DELAY
+// environment variables.
+type RetryPolicy struct {
+	// MaxAttempts - max number of attempts, including the first one. Set to 1 to disable retries.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// PlayerSessionCacheParameters - settings of the read-through cache of DescribePlayerSessions lookups
+// by player session ID or player ID. Cached results are invalidated by AcceptPlayerSession,
+// RemovePlayerSession and new game sessions. Concurrent lookups of the same ID share one request to GameLift.
+type PlayerSessionCacheParameters struct {
+	// TTL - how long a result is cached. Zero disables the cache.
+	TTL time.Duration
+
+	// MaxEntries - max number of cached results, the least recently used ones are evicted first.
+	// Zero keeps Config.PlayerSessionCacheMaxEntries, common.PlayerSessionCacheMaxEntriesDefault by default.
+	MaxEntries int
 }
 
 // ProcessParameters - object that communicating the following information about the server process:
@@ -67,10 +144,22 @@ type ProcessParameters struct {
 	// and if none is received. records the server process as unhealthy.
 	OnHealthCheck func() bool
 
+	// OnUnknownMessage - optional callback function that the SDK invokes with the action and the raw JSON
+	// of every message from GameLift that this version of the SDK doesn't handle,
+	// so new GameLift service messages can be adopted before the SDK is updated.
+	OnUnknownMessage func(action string, raw []byte)
+
+	// OnCallbackPanic - optional callback function that the SDK invokes when one of the callbacks above panics,
+	// with the name of the callback, such as "OnStartGameSession", the recovered value and the stack trace.
+	// The panic is recovered and logged, so the process keeps running,
+	// and the process is reported unhealthy on the next health check.
+	OnCallbackPanic func(callback string, recovered any, stack []byte)
+
 	// Port - the server process listens on for new player connections.
 	// The value must fall into the port range configured for any fleet deploying this game server build.
 	// This port number is included in game session and player session objects,
 	// which game sessions use when connecting to a server process.
+	// It must be between 1 and 65535, otherwise ProcessReady returns an error.
 	Port int
 
 	// Object with a list of directory paths to game session log files.
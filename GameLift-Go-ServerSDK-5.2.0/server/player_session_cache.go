@@ -0,0 +1,209 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model"
+	"aws/amazon-gamelift-go-sdk/model/request"
+	"aws/amazon-gamelift-go-sdk/model/result"
+)
+
+// playerSessionCacheKey - identifies a cacheable DescribePlayerSessions lookup.
+// Only lookups by player session ID or by player ID (first page, without game session filter) are cached.
+type playerSessionCacheKey struct {
+	playerSessionID string
+	playerID        string
+	statusFilter    string
+	limit           int
+}
+
+// newPlayerSessionCacheKey - returns the cache key of the request and false if the request can't be cached.
+func newPlayerSessionCacheKey(req *request.DescribePlayerSessionsRequest) (playerSessionCacheKey, bool) {
+	if req.PlayerSessionID != "" {
+		// NextToken and Limit are ignored by GameLift when a player session ID is specified
+		return playerSessionCacheKey{
+			playerSessionID: req.PlayerSessionID,
+			statusFilter:    req.PlayerSessionStatusFilter,
+		}, true
+	}
+	if req.PlayerID != "" && req.GameSessionID == "" && req.NextToken == "" {
+		return playerSessionCacheKey{
+			playerID:     req.PlayerID,
+			statusFilter: req.PlayerSessionStatusFilter,
+			limit:        req.Limit,
+		}, true
+	}
+	return playerSessionCacheKey{}, false
+}
+
+type playerSessionCacheEntry struct {
+	key     playerSessionCacheKey
+	result  result.DescribePlayerSessionsResult
+	expires time.Time
+}
+
+// playerSessionCall - DescribePlayerSessions round trip shared by concurrent lookups of the same key.
+type playerSessionCall struct {
+	done        chan struct{}
+	result      result.DescribePlayerSessionsResult
+	err         error
+	invalidated bool
+}
+
+// playerSessionCache - read-through cache of DescribePlayerSessions results with TTL and LRU eviction.
+// Concurrent lookups of the same key share a single request to GameLift.
+type playerSessionCache struct {
+	ttl        time.Duration
+	maxEntries int
+	clock      common.Clock
+
+	mtx      sync.Mutex
+	entries  map[playerSessionCacheKey]*list.Element
+	lru      *list.List
+	inFlight map[playerSessionCacheKey]*playerSessionCall
+}
+
+func newPlayerSessionCache(ttl time.Duration, maxEntries int, clock common.Clock) *playerSessionCache {
+	return &playerSessionCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		clock:      clock,
+		entries:    make(map[playerSessionCacheKey]*list.Element),
+		lru:        list.New(),
+		inFlight:   make(map[playerSessionCacheKey]*playerSessionCall),
+	}
+}
+
+// describe - returns the cached result of the request, or calls fetch once for all concurrent lookups of the same key.
+// Errors are not cached.
+func (c *playerSessionCache) describe(
+	req *request.DescribePlayerSessionsRequest,
+	fetch func() (result.DescribePlayerSessionsResult, error),
+) (result.DescribePlayerSessionsResult, error) {
+	key, ok := newPlayerSessionCacheKey(req)
+	if !ok {
+		return fetch()
+	}
+
+	c.mtx.Lock()
+	if res, found := c.lookup(key); found {
+		c.mtx.Unlock()
+		return copyPlayerSessionsResult(res), nil
+	}
+	if call, found := c.inFlight[key]; found {
+		c.mtx.Unlock()
+		<-call.done
+		return copyPlayerSessionsResult(call.result), call.err
+	}
+	call := &playerSessionCall{done: make(chan struct{})}
+	c.inFlight[key] = call
+	c.mtx.Unlock()
+
+	completed := false
+	// Deferred, so the concurrent lookups are released and the key can be fetched again if fetch panics
+	defer func() {
+		c.mtx.Lock()
+		delete(c.inFlight, key)
+		if !completed {
+			call.err = common.NewGameLiftError(common.InternalServiceException, "", "player session lookup panicked")
+		} else if call.err == nil && !call.invalidated {
+			// The result may be outdated if the cache was invalidated during the round trip
+			c.store(key, call.result)
+		}
+		c.mtx.Unlock()
+		close(call.done)
+	}()
+
+	call.result, call.err = fetch()
+	completed = true
+
+	return copyPlayerSessionsResult(call.result), call.err
+}
+
+// invalidate - removes all cached results related to the player session.
+func (c *playerSessionCache) invalidate(playerSessionID string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for e := c.lru.Front(); e != nil; {
+		next := e.Next()
+		entry := e.Value.(*playerSessionCacheEntry)
+		if entry.key.playerSessionID == playerSessionID || containsPlayerSession(entry.result.PlayerSessions, playerSessionID) {
+			c.remove(e)
+		}
+		e = next
+	}
+	for key, call := range c.inFlight {
+		// The player sessions of a player are unknown until the round trip finishes
+		if key.playerSessionID == playerSessionID || key.playerID != "" {
+			call.invalidated = true
+		}
+	}
+}
+
+// clear - removes all cached results.
+func (c *playerSessionCache) clear() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.entries = make(map[playerSessionCacheKey]*list.Element)
+	c.lru.Init()
+	for _, call := range c.inFlight {
+		call.invalidated = true
+	}
+}
+
+func (c *playerSessionCache) lookup(key playerSessionCacheKey) (result.DescribePlayerSessionsResult, bool) {
+	e, ok := c.entries[key]
+	if !ok {
+		return result.DescribePlayerSessionsResult{}, false
+	}
+	entry := e.Value.(*playerSessionCacheEntry)
+	if !c.clock.Now().Before(entry.expires) {
+		c.remove(e)
+		return result.DescribePlayerSessionsResult{}, false
+	}
+	c.lru.MoveToFront(e)
+	return entry.result, true
+}
+
+func (c *playerSessionCache) store(key playerSessionCacheKey, res result.DescribePlayerSessionsResult) {
+	entry := &playerSessionCacheEntry{key: key, result: copyPlayerSessionsResult(res), expires: c.clock.Now().Add(c.ttl)}
+	if e, ok := c.entries[key]; ok {
+		e.Value = entry
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *playerSessionCache) remove(e *list.Element) {
+	delete(c.entries, e.Value.(*playerSessionCacheEntry).key)
+	c.lru.Remove(e)
+}
+
+func containsPlayerSession(playerSessions []model.PlayerSession, playerSessionID string) bool {
+	for i := range playerSessions {
+		if playerSessions[i].PlayerSessionID == playerSessionID {
+			return true
+		}
+	}
+	return false
+}
+
+// copyPlayerSessionsResult - copies the player sessions, so callers can't modify the cached ones.
+func copyPlayerSessionsResult(res result.DescribePlayerSessionsResult) result.DescribePlayerSessionsResult {
+	if res.PlayerSessions != nil {
+		res.PlayerSessions = append([]model.PlayerSession(nil), res.PlayerSessions...)
+	}
+	return res
+}
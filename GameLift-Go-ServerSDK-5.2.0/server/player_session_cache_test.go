@@ -0,0 +1,327 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model"
+	"aws/amazon-gamelift-go-sdk/model/request"
+	"aws/amazon-gamelift-go-sdk/model/result"
+	"aws/amazon-gamelift-go-sdk/server/internal"
+	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+)
+
+const testCacheTTL = time.Minute
+
+// countingFetch returns DescribePlayerSessions results with the specified player session IDs and counts the calls.
+type countingFetch struct {
+	mtx   sync.Mutex
+	calls int
+}
+
+func (f *countingFetch) fetch(ids ...string) func() (result.DescribePlayerSessionsResult, error) {
+	return func() (result.DescribePlayerSessionsResult, error) {
+		f.mtx.Lock()
+		defer f.mtx.Unlock()
+		f.calls++
+		return result.DescribePlayerSessionsResult{PlayerSessions: newPlayerSessions(ids...)}, nil
+	}
+}
+
+func (f *countingFetch) count() int {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.calls
+}
+
+func byPlayerSessionID(id string) *request.DescribePlayerSessionsRequest {
+	req := request.NewDescribePlayerSessions()
+	req.PlayerSessionID = id
+	return &req
+}
+
+func byPlayerID(id string) *request.DescribePlayerSessionsRequest {
+	req := request.NewDescribePlayerSessions()
+	req.PlayerID = id
+	return &req
+}
+
+func TestPlayerSessionCache_ReadThrough(t *testing.T) {
+	// GIVEN
+	cache := newPlayerSessionCache(testCacheTTL, 10, mock.NewFakeClock(time.Now()))
+	var f countingFetch
+
+	// WHEN
+	first, err := cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.PlayerSessions[0].PlayerData = "modified by the caller"
+	second, err := cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN
+	assertEqual(t, 1, f.count())
+	assertEqual(t, "", second.PlayerSessions[0].PlayerData)
+}
+
+func TestPlayerSessionCache_NotCacheable(t *testing.T) {
+	// GIVEN
+	cache := newPlayerSessionCache(testCacheTTL, 10, mock.NewFakeClock(time.Now()))
+	var f countingFetch
+	req := request.NewDescribePlayerSessions()
+	req.GameSessionID = "test-game-session-id"
+	nextPage := byPlayerID("player-1")
+	nextPage.NextToken = "token-1"
+
+	// WHEN
+	for _, r := range []*request.DescribePlayerSessionsRequest{&req, &req, nextPage, nextPage} {
+		if _, err := cache.describe(r, f.fetch()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// THEN
+	assertEqual(t, 4, f.count())
+}
+
+func TestPlayerSessionCache_TTL(t *testing.T) {
+	// GIVEN
+	clock := mock.NewFakeClock(time.Now())
+	cache := newPlayerSessionCache(testCacheTTL, 10, clock)
+	var f countingFetch
+
+	// WHEN
+	_, _ = cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+	clock.Advance(testCacheTTL - time.Second)
+	_, _ = cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+	clock.Advance(time.Second)
+	_, _ = cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+
+	// THEN
+	assertEqual(t, 2, f.count())
+}
+
+func TestPlayerSessionCache_MaxEntries(t *testing.T) {
+	// GIVEN
+	cache := newPlayerSessionCache(testCacheTTL, 2, mock.NewFakeClock(time.Now()))
+	var f countingFetch
+
+	// WHEN
+	_, _ = cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+	_, _ = cache.describe(byPlayerSessionID("psess-2"), f.fetch("psess-2"))
+	// psess-1 becomes the most recently used one
+	_, _ = cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+	_, _ = cache.describe(byPlayerSessionID("psess-3"), f.fetch("psess-3"))
+
+	// THEN
+	assertEqual(t, 3, f.count())
+	_, _ = cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+	assertEqual(t, 3, f.count())
+	_, _ = cache.describe(byPlayerSessionID("psess-2"), f.fetch("psess-2"))
+	assertEqual(t, 4, f.count())
+}
+
+func TestPlayerSessionCache_Invalidate(t *testing.T) {
+	// GIVEN
+	cache := newPlayerSessionCache(testCacheTTL, 10, mock.NewFakeClock(time.Now()))
+	var f countingFetch
+	_, _ = cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+	_, _ = cache.describe(byPlayerSessionID("psess-2"), f.fetch("psess-2"))
+	_, _ = cache.describe(byPlayerID("player-1"), f.fetch("psess-1", "psess-3"))
+
+	// WHEN
+	cache.invalidate("psess-1")
+
+	// THEN
+	_, _ = cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+	_, _ = cache.describe(byPlayerSessionID("psess-2"), f.fetch("psess-2"))
+	_, _ = cache.describe(byPlayerID("player-1"), f.fetch("psess-1", "psess-3"))
+	assertEqual(t, 5, f.count())
+}
+
+func TestPlayerSessionCache_Clear(t *testing.T) {
+	// GIVEN
+	cache := newPlayerSessionCache(testCacheTTL, 10, mock.NewFakeClock(time.Now()))
+	var f countingFetch
+	_, _ = cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+	_, _ = cache.describe(byPlayerID("player-1"), f.fetch("psess-1"))
+
+	// WHEN
+	cache.clear()
+
+	// THEN
+	_, _ = cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+	_, _ = cache.describe(byPlayerID("player-1"), f.fetch("psess-1"))
+	assertEqual(t, 4, f.count())
+}
+
+func TestPlayerSessionCache_ErrorsAreNotCached(t *testing.T) {
+	// GIVEN
+	cache := newPlayerSessionCache(testCacheTTL, 10, mock.NewFakeClock(time.Now()))
+	expectedErr := errors.New("test error")
+	calls := 0
+	failingFetch := func() (result.DescribePlayerSessionsResult, error) {
+		calls++
+		return result.DescribePlayerSessionsResult{}, expectedErr
+	}
+
+	// WHEN
+	_, firstErr := cache.describe(byPlayerSessionID("psess-1"), failingFetch)
+	_, secondErr := cache.describe(byPlayerSessionID("psess-1"), failingFetch)
+
+	// THEN
+	assertEqual(t, expectedErr, firstErr)
+	assertEqual(t, expectedErr, secondErr)
+	assertEqual(t, 2, calls)
+}
+
+func TestPlayerSessionCache_CoalescesConcurrentLookups(t *testing.T) {
+	// GIVEN
+	cache := newPlayerSessionCache(testCacheTTL, 10, mock.NewFakeClock(time.Now()))
+	var f countingFetch
+	started := make(chan struct{})
+	release := make(chan struct{})
+	blockingFetch := func() (result.DescribePlayerSessionsResult, error) {
+		close(started)
+		<-release
+		return f.fetch("psess-1")()
+	}
+
+	// WHEN
+	const lookups = 10
+	results := make(chan result.DescribePlayerSessionsResult, lookups)
+	var wg sync.WaitGroup
+	wg.Add(lookups)
+	go func() {
+		defer wg.Done()
+		res, _ := cache.describe(byPlayerSessionID("psess-1"), blockingFetch)
+		results <- res
+	}()
+	<-started
+	for i := 1; i < lookups; i++ {
+		go func() {
+			defer wg.Done()
+			res, _ := cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+			results <- res
+		}()
+	}
+	close(release)
+	wg.Wait()
+	close(results)
+
+	// THEN
+	assertEqual(t, 1, f.count())
+	for res := range results {
+		assertEqual(t, "psess-1", res.PlayerSessions[0].PlayerSessionID)
+	}
+}
+
+// GIVEN a lookup whose fetch panics WHEN the same key is looked up again
+// THEN it is fetched again instead of waiting for the panicked lookup forever
+func TestPlayerSessionCache_FetchPanic(t *testing.T) {
+	// GIVEN
+	cache := newPlayerSessionCache(testCacheTTL, 10, mock.NewFakeClock(time.Now()))
+	var f countingFetch
+	func() {
+		defer func() {
+			if r := recover(); r != "test panic" {
+				t.Fatalf("unexpected panic %v", r)
+			}
+		}()
+		_, _ = cache.describe(byPlayerSessionID("psess-1"), func() (result.DescribePlayerSessionsResult, error) {
+			panic("test panic")
+		})
+	}()
+
+	// WHEN
+	res, err := cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, 1, f.count())
+	assertEqual(t, "psess-1", res.PlayerSessions[0].PlayerSessionID)
+}
+
+func TestPlayerSessionCache_InvalidateDuringRoundTrip(t *testing.T) {
+	// GIVEN
+	cache := newPlayerSessionCache(testCacheTTL, 10, mock.NewFakeClock(time.Now()))
+	var f countingFetch
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = cache.describe(byPlayerSessionID("psess-1"), func() (result.DescribePlayerSessionsResult, error) {
+			close(started)
+			<-release
+			return f.fetch("psess-1")()
+		})
+	}()
+	<-started
+
+	// WHEN
+	cache.invalidate("psess-1")
+	close(release)
+	<-done
+
+	// THEN
+	// The outdated result is not cached
+	_, _ = cache.describe(byPlayerSessionID("psess-1"), f.fetch("psess-1"))
+	assertEqual(t, 2, f.count())
+}
+
+func TestAcceptPlayerSession_InvalidatesPlayerSessionCache(t *testing.T) {
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	manager := mock.NewMockIGameLiftManager(ctrl)
+	state := gameLiftServerState{
+		wsGameLift:         manager,
+		gameSessionID:      "test-game-session-id",
+		serviceCallTimeout: common.ServiceCallTimeoutDefault,
+		playerSessionCache: newPlayerSessionCache(testCacheTTL, 10, mock.NewFakeClock(time.Now())),
+	}
+	state.isReadyProcess.Store(true)
+
+	// EXPECT
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), gomock.Any(), common.ServiceCallTimeoutDefault).
+		DoAndReturn(func(_ internal.MessageGetter, resp any, _ time.Duration) error {
+			*resp.(*result.DescribePlayerSessionsResult) = result.DescribePlayerSessionsResult{
+				PlayerSessions: []model.PlayerSession{{PlayerSessionID: "psess-1"}},
+			}
+			return nil
+		}).
+		Times(2)
+	manager.EXPECT().SendMessage(gomock.Any()).Return(nil)
+
+	// WHEN
+	for i := 0; i < 2; i++ {
+		if _, err := state.describePlayerSessions(byPlayerSessionID("psess-1")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := state.acceptPlayerSession("psess-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN
+	if _, err := state.describePlayerSessions(byPlayerSessionID("psess-1")); err != nil {
+		t.Fatal(err)
+	}
+}
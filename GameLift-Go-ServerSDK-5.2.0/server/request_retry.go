@@ -0,0 +1,147 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model/message"
+	"aws/amazon-gamelift-go-sdk/server/internal"
+	"aws/amazon-gamelift-go-sdk/server/metrics"
+)
+
+// idempotentActions - the actions which can be sent again without side effects.
+// HeartbeatServerProcess is retried only while the retry can end before the next heartbeat, see handleRequestBefore.
+var idempotentActions = map[message.MessageAction]bool{
+	message.DescribePlayerSessions:  true,
+	message.GetComputeCertificate:   true,
+	message.GetFleetRoleCredentials: true,
+	message.HeartbeatServerProcess:  true,
+}
+
+// renewableRequest - a request whose RequestId can be replaced before it is sent again.
+type renewableRequest interface {
+	internal.MessageGetter
+	RenewRequestID()
+}
+
+// newRetryPolicies - returns the retry policies of all idempotent actions, the policies of params override
+// defaultPolicy. Returns an error if a policy is set for an action which can't be retried or is invalid.
+func newRetryPolicies(
+	params map[message.MessageAction]RetryPolicy,
+	defaultPolicy RetryPolicy,
+) (map[message.MessageAction]RetryPolicy, error) {
+	policies := make(map[message.MessageAction]RetryPolicy, len(idempotentActions))
+	for action := range idempotentActions {
+		policies[action] = defaultPolicy
+	}
+	for action, policy := range params {
+		if !idempotentActions[action] {
+			return nil, common.NewGameLiftError(common.BadRequestException, "",
+				fmt.Sprintf("%s can't be retried", action))
+		}
+		if policy.MaxAttempts < 0 || policy.BaseDelay < 0 || policy.MaxDelay < 0 {
+			return nil, common.NewGameLiftError(common.BadRequestException, "",
+				fmt.Sprintf("invalid retry policy of %s: %+v", action, policy))
+		}
+		if policy.MaxAttempts == 0 {
+			policy.MaxAttempts = defaultPolicy.MaxAttempts
+		}
+		if policy.BaseDelay == 0 {
+			policy.BaseDelay = defaultPolicy.BaseDelay
+		}
+		if policy.MaxDelay == 0 {
+			policy.MaxDelay = defaultPolicy.MaxDelay
+		}
+		if policy.MaxDelay < policy.BaseDelay {
+			return nil, common.NewGameLiftError(common.BadRequestException, "",
+				fmt.Sprintf("MaxDelay %s of the retry policy of %s is less than BaseDelay %s",
+					policy.MaxDelay, action, policy.BaseDelay))
+		}
+		policies[action] = policy
+	}
+	return policies, nil
+}
+
+// delay - returns a random delay before the retry, full jitter of the capped exponential backoff.
+//
+//nolint:gosec // weak math random generator is enough in this case
+func (p RetryPolicy) delay(retry int) time.Duration {
+	backoff := p.MaxDelay
+	// Compared by shifting MaxDelay, as shifting BaseDelay can overflow
+	if p.BaseDelay <= p.MaxDelay>>(retry-1) {
+		backoff = p.BaseDelay << (retry - 1)
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// isRetryableResponse - reports whether the request failed because GameLift didn't respond in time
+// or responded with a 5xx error.
+func isRetryableResponse(err error) bool {
+	if common.IsTimeout(err) {
+		return true
+	}
+	var gameLiftErr *common.GameLiftError
+	return errors.As(err, &gameLiftErr) && gameLiftErr.StatusCode >= http.StatusInternalServerError
+}
+
+// handleRequest - sends the request and waits for the response like IGameLiftManager.HandleRequest,
+// within the timeout of its action. Idempotent requests are sent again with a new RequestId according to
+// the retry policy of their action.
+func (state *gameLiftServerState) handleRequest(req renewableRequest, res any) error {
+	return state.handleRequestBefore(req, res, time.Time{})
+}
+
+// handleRequestBefore - handles the request like handleRequest, but a retry is not sent if its backoff
+// and its timeout can end after the deadline. A zero deadline doesn't limit the retries.
+func (state *gameLiftServerState) handleRequestBefore(req renewableRequest, res any, deadline time.Time) error {
+	action := req.GetMessage().Action
+	timeout, ok := state.requestTimeouts[action]
+	if !ok {
+		timeout = state.serviceCallTimeout
+	}
+	policy, ok := state.retryPolicies[action]
+	if !ok || policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		err := state.wsGameLift.HandleRequest(req, res, timeout)
+		if errors.Is(err, common.ErrResponseTimeout) {
+			lg.Warnf("%s request %s exceeded its timeout of %s on attempt %d of %d",
+				action, req.GetMessage().RequestID, timeout, attempt, policy.MaxAttempts)
+		}
+		retry := err != nil && attempt < policy.MaxAttempts && isRetryableResponse(err)
+		var delay time.Duration
+		if retry {
+			delay = policy.delay(attempt)
+			if !deadline.IsZero() && state.clock.Now().Add(delay+timeout).After(deadline) {
+				lg.Debugf("%s request %s is not retried, the retry could end after %s",
+					action, req.GetMessage().RequestID, deadline)
+				retry = false
+			}
+		}
+		if !retry {
+			state.getMetrics().Observe(metrics.RequestAttempts, float64(attempt))
+			if err != nil && attempt > 1 {
+				lg.Warnf("%s request failed after %d attempts: %s", action, attempt, err)
+			}
+			return err
+		}
+		lg.Debugf("%s request %s failed on attempt %d of %d, retrying in %s: %s",
+			action, req.GetMessage().RequestID, attempt, policy.MaxAttempts, delay, err)
+		state.getMetrics().IncrCounter(metrics.RequestRetries, 1)
+		state.clock.Sleep(delay)
+		req.RenewRequestID()
+	}
+}
@@ -0,0 +1,293 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model/message"
+	"aws/amazon-gamelift-go-sdk/model/request"
+	"aws/amazon-gamelift-go-sdk/model/result"
+	"aws/amazon-gamelift-go-sdk/server/internal"
+	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+	"aws/amazon-gamelift-go-sdk/server/metrics"
+)
+
+// newRetryTestState - returns an active server state with the retry policy of DescribePlayerSessions
+// and the metrics replaced with a mock.
+func newRetryTestState(t *testing.T, policy RetryPolicy) (*gameLiftServerState, *mock.MockIGameLiftManager, *mock.MockIMetrics, *mock.FakeClock) {
+	ctrl := gomock.NewController(t)
+	manager := mock.NewMockIGameLiftManager(ctrl)
+	metricsMock := mock.NewMockIMetrics(ctrl)
+
+	policies, err := newRetryPolicies(
+		map[message.MessageAction]RetryPolicy{message.DescribePlayerSessions: policy},
+		DefaultConfig().RequestRetry,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := mock.NewFakeClock(time.Now())
+	state := &gameLiftServerState{wsGameLift: manager, clock: clock, metrics: metricsMock, retryPolicies: policies}
+	state.isReadyProcess.Store(true)
+	return state, manager, metricsMock, clock
+}
+
+// GIVEN request timing out once WHEN DescribePlayerSessions THEN it is retried with a new RequestId after the backoff
+func TestHandleRequest_RetriesTimeoutWithNewRequestID(t *testing.T) {
+	// GIVEN
+	state, manager, metricsMock, clock := newRetryTestState(t, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Second})
+	req := request.NewDescribePlayerSessions()
+	req.GameSessionID = "test-game-session-id"
+	originalRequestID := req.RequestID
+
+	// EXPECT
+	var requestIDs []string
+	gomock.InOrder(
+		manager.EXPECT().
+			HandleRequest(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(r internal.MessageGetter, _ any, _ time.Duration) error {
+				requestIDs = append(requestIDs, r.GetMessage().RequestID)
+				return common.NewGameLiftErrorWithCause(common.ServiceCallFailed, "", "", common.ErrResponseTimeout)
+			}),
+		manager.EXPECT().
+			HandleRequest(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(r internal.MessageGetter, res any, _ time.Duration) error {
+				requestIDs = append(requestIDs, r.GetMessage().RequestID)
+				res.(*result.DescribePlayerSessionsResult).NextToken = "test-next-token"
+				return nil
+			}),
+	)
+	metricsMock.EXPECT().IncrCounter(metrics.RequestRetries, int64(1))
+	metricsMock.EXPECT().Observe(metrics.RequestAttempts, float64(2))
+
+	// WHEN
+	done := make(chan struct{})
+	var res result.DescribePlayerSessionsResult
+	var err error
+	go func() {
+		defer close(done)
+		res, err = state.describePlayerSessions(&req)
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	<-done
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "test-next-token", res.NextToken)
+	assertEqual(t, 2, len(requestIDs))
+	assertEqual(t, originalRequestID, requestIDs[0])
+	if requestIDs[1] == requestIDs[0] {
+		t.Fatalf("the retry was sent with the same RequestId %s", requestIDs[1])
+	}
+	assertEqual(t, originalRequestID, req.RequestID)
+}
+
+// GIVEN 5xx responses WHEN the attempts are exhausted THEN the last error is returned
+func TestHandleRequest_ReturnsLastErrorAfterMaxAttempts(t *testing.T) {
+	// GIVEN
+	state, manager, metricsMock, clock := newRetryTestState(t, RetryPolicy{MaxAttempts: 2, BaseDelay: time.Second, MaxDelay: time.Second})
+	req := request.NewDescribePlayerSessions()
+	serviceErr := common.NewGameLiftErrorFromStatusCode(http.StatusServiceUnavailable, "test service error")
+
+	// EXPECT
+	manager.EXPECT().HandleRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(serviceErr).Times(2)
+	metricsMock.EXPECT().IncrCounter(metrics.RequestRetries, int64(1))
+	metricsMock.EXPECT().Observe(metrics.RequestAttempts, float64(2))
+
+	// WHEN
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer close(done)
+		_, err = state.describePlayerSessions(&req)
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	<-done
+
+	// THEN
+	if !errors.Is(err, serviceErr) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+// GIVEN errors which are not timeouts or 5xx WHEN DescribePlayerSessions THEN the request is not retried
+func TestHandleRequest_DoesNotRetryPermanentErrors(t *testing.T) {
+	for name, requestErr := range map[string]error{
+		"4xx":          common.NewGameLiftErrorFromStatusCode(http.StatusBadRequest, "test service error"),
+		"send failure": common.NewGameLiftError(common.ServiceCallFailed, "Failed write data", ""),
+	} {
+		t.Run(name, func(t *testing.T) {
+			// GIVEN
+			state, manager, metricsMock, _ := newRetryTestState(t, RetryPolicy{MaxAttempts: 3})
+			req := request.NewDescribePlayerSessions()
+
+			// EXPECT
+			manager.EXPECT().HandleRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(requestErr)
+			metricsMock.EXPECT().Observe(metrics.RequestAttempts, float64(1))
+
+			// WHEN
+			_, err := state.describePlayerSessions(&req)
+
+			// THEN
+			if err != requestErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+// GIVEN a heartbeat timing out WHEN a retry can end before the next heartbeat THEN the heartbeat is retried
+func TestHandleRequestBefore_RetriesHeartbeatBeforeDeadline(t *testing.T) {
+	// GIVEN
+	state, manager, metricsMock, clock := newRetryTestState(t, RetryPolicy{})
+	state.serviceCallTimeout = time.Second
+	req := request.NewHeartbeatServerProcess(true)
+	// The first retry waits at most BaseDelay
+	deadline := clock.Now().Add(common.RequestRetryBaseDelayDefault + time.Second)
+	timeoutErr := common.NewGameLiftErrorWithCause(common.ServiceCallFailed, "", "", common.ErrResponseTimeout)
+
+	// EXPECT
+	gomock.InOrder(
+		manager.EXPECT().HandleRequest(gomock.Any(), gomock.Any(), time.Second).Return(timeoutErr),
+		manager.EXPECT().HandleRequest(gomock.Any(), gomock.Any(), time.Second).Return(nil),
+	)
+	metricsMock.EXPECT().IncrCounter(metrics.RequestRetries, int64(1))
+	metricsMock.EXPECT().Observe(metrics.RequestAttempts, float64(2))
+
+	// WHEN
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer close(done)
+		var response message.Message
+		err = state.handleRequestBefore(&req, &response, deadline)
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(common.RequestRetryBaseDelayDefault)
+	<-done
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// GIVEN a heartbeat timing out WHEN a retry could end after the next heartbeat THEN the heartbeat is not retried
+func TestHandleRequestBefore_DoesNotRetryHeartbeatAfterDeadline(t *testing.T) {
+	// GIVEN
+	state, manager, metricsMock, clock := newRetryTestState(t, RetryPolicy{})
+	state.serviceCallTimeout = time.Second
+	req := request.NewHeartbeatServerProcess(true)
+	deadline := clock.Now().Add(time.Second - time.Millisecond)
+	timeoutErr := common.NewGameLiftErrorWithCause(common.ServiceCallFailed, "", "", common.ErrResponseTimeout)
+
+	// EXPECT
+	manager.EXPECT().HandleRequest(gomock.Any(), gomock.Any(), time.Second).Return(timeoutErr)
+	metricsMock.EXPECT().Observe(metrics.RequestAttempts, float64(1))
+
+	// WHEN
+	var response message.Message
+	err := state.handleRequestBefore(&req, &response, deadline)
+
+	// THEN
+	if !errors.Is(err, common.ErrResponseTimeout) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestNewRetryPolicies(t *testing.T) {
+	// WHEN
+	policies, err := newRetryPolicies(map[message.MessageAction]RetryPolicy{
+		message.GetComputeCertificate: {MaxAttempts: 1},
+	}, DefaultConfig().RequestRetry)
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(idempotentActions), len(policies))
+	assertEqual(t, RetryPolicy{
+		MaxAttempts: 1,
+		BaseDelay:   common.RequestRetryBaseDelayDefault,
+		MaxDelay:    common.RequestRetryMaxDelayDefault,
+	}, policies[message.GetComputeCertificate])
+	assertEqual(t, RetryPolicy{
+		MaxAttempts: common.RequestRetryMaxAttemptsDefault,
+		BaseDelay:   common.RequestRetryBaseDelayDefault,
+		MaxDelay:    common.RequestRetryMaxDelayDefault,
+	}, policies[message.DescribePlayerSessions])
+}
+
+func TestNewRetryPolicies_Invalid(t *testing.T) {
+	for name, params := range map[string]map[message.MessageAction]RetryPolicy{
+		"not idempotent":   {message.StartMatchBackfill: {MaxAttempts: 3}},
+		"negative attempt": {message.DescribePlayerSessions: {MaxAttempts: -1}},
+		"negative delay":   {message.DescribePlayerSessions: {BaseDelay: -time.Second}},
+		"max delay less than base delay": {
+			message.DescribePlayerSessions: {BaseDelay: time.Second, MaxDelay: time.Millisecond},
+		},
+		"max delay less than default base delay": {
+			message.DescribePlayerSessions: {MaxDelay: common.RequestRetryBaseDelayDefault / 2},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// WHEN
+			_, err := newRetryPolicies(params, DefaultConfig().RequestRetry)
+
+			// THEN
+			if !errors.Is(err, common.ErrBadRequestException) {
+				t.Fatalf("expected BadRequestException, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_DelayIsCappedExponentialJitter(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for retry, maxDelay := range map[int]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		4:  800 * time.Millisecond,
+		5:  time.Second,
+		64: time.Second,
+	} {
+		for i := 0; i < 100; i++ {
+			if delay := policy.delay(retry); delay < 0 || delay > maxDelay {
+				t.Fatalf("delay of retry %d is %s, want [0, %s]", retry, delay, maxDelay)
+			}
+		}
+	}
+}
+
+// GIVEN a base delay which overflows when doubled for every retry WHEN the delay of a late retry is computed
+// THEN it is capped by MaxDelay instead of overflowing
+func TestRetryPolicy_DelayDoesNotOverflow(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Hour, MaxDelay: 1000 * time.Hour}
+	var maxDelay time.Duration
+	for i := 0; i < 100; i++ {
+		delay := policy.delay(30)
+		if delay < 0 || delay > policy.MaxDelay {
+			t.Fatalf("delay of retry 30 is %s, want [0, %s]", delay, policy.MaxDelay)
+		}
+		if delay > maxDelay {
+			maxDelay = delay
+		}
+	}
+	if maxDelay <= policy.BaseDelay {
+		t.Fatalf("delays of retry 30 are at most %s, want up to %s", maxDelay, policy.MaxDelay)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"aws/amazon-gamelift-go-sdk/model/message"
+)
+
+// requestResponseActions - the actions of the requests whose response is awaited.
+var requestResponseActions = map[message.MessageAction]bool{
+	message.ActivateServerProcess:   true,
+	message.DescribePlayerSessions:  true,
+	message.StartMatchBackfill:      true,
+	message.GetComputeCertificate:   true,
+	message.GetFleetRoleCredentials: true,
+	message.HeartbeatServerProcess:  true,
+}
+
+// newRequestTimeouts - returns the timeouts of all request/response actions: the timeouts of Config.RequestTimeouts,
+// ActivateServerProcessRequestTimeoutInSeconds for ActivateServerProcess and serviceCallTimeout for the others.
+// The timeouts are checked by Config.Validate.
+func newRequestTimeouts(
+	overrides map[message.MessageAction]time.Duration,
+	serviceCallTimeout time.Duration,
+) map[message.MessageAction]time.Duration {
+	timeouts := make(map[message.MessageAction]time.Duration, len(requestResponseActions))
+	for action := range requestResponseActions {
+		timeouts[action] = serviceCallTimeout
+	}
+	timeouts[message.ActivateServerProcess] = ActivateServerProcessRequestTimeoutInSeconds
+	for action, timeout := range overrides {
+		timeouts[action] = timeout
+	}
+	return timeouts
+}
+
+// parseRequestTimeouts - parses comma separated timeouts of actions, e.g. "StartMatchBackfill=1m,HeartbeatServerProcess=5s".
+func parseRequestTimeouts(value string) (map[message.MessageAction]time.Duration, error) {
+	timeouts := make(map[message.MessageAction]time.Duration)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		action, duration, found := strings.Cut(entry, "=")
+		timeout, err := time.ParseDuration(strings.TrimSpace(duration))
+		if !found || err != nil {
+			return nil, fmt.Errorf("invalid entry %q, expected Action=duration", entry)
+		}
+		timeouts[message.MessageAction(strings.TrimSpace(action))] = timeout
+	}
+	return timeouts, nil
+}
+
+// mergeRequestTimeouts - returns a new map with the timeouts of base overridden by overrides.
+func mergeRequestTimeouts(base, overrides map[message.MessageAction]time.Duration) map[message.MessageAction]time.Duration {
+	merged := make(map[message.MessageAction]time.Duration, len(base)+len(overrides))
+	for action, timeout := range base {
+		merged[action] = timeout
+	}
+	for action, timeout := range overrides {
+		merged[action] = timeout
+	}
+	return merged
+}
+
+// formatRequestTimeouts - formats the timeouts like parseRequestTimeouts parses them, sorted by action.
+func formatRequestTimeouts(timeouts map[message.MessageAction]time.Duration) string {
+	entries := make([]string, 0, len(timeouts))
+	for _, action := range sortedActions(timeouts) {
+		entries = append(entries, fmt.Sprintf("%s=%s", action, timeouts[action]))
+	}
+	return strings.Join(entries, ",")
+}
+
+func sortedActions(timeouts map[message.MessageAction]time.Duration) []message.MessageAction {
+	actions := make([]message.MessageAction, 0, len(timeouts))
+	for action := range timeouts {
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i] < actions[j] })
+	return actions
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"aws/amazon-gamelift-go-sdk/common"
+	"aws/amazon-gamelift-go-sdk/model/message"
+	"aws/amazon-gamelift-go-sdk/model/request"
+	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+)
+
+func TestNewRequestTimeouts(t *testing.T) {
+	// GIVEN
+	overrides := map[message.MessageAction]time.Duration{
+		message.StartMatchBackfill:    time.Minute,
+		message.GetComputeCertificate: 10 * time.Second,
+	}
+
+	// WHEN
+	timeouts := newRequestTimeouts(overrides, 20*time.Second)
+
+	// THEN
+	assertEqual(t, len(requestResponseActions), len(timeouts))
+	assertEqual(t, ActivateServerProcessRequestTimeoutInSeconds, timeouts[message.ActivateServerProcess])
+	assertEqual(t, 20*time.Second, timeouts[message.DescribePlayerSessions])
+	assertEqual(t, time.Minute, timeouts[message.StartMatchBackfill])
+	assertEqual(t, 10*time.Second, timeouts[message.GetComputeCertificate])
+}
+
+func TestParseRequestTimeouts(t *testing.T) {
+	// WHEN
+	timeouts, err := parseRequestTimeouts(" HeartbeatServerProcess=5s, GetComputeCertificate=30s,")
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[message.MessageAction]time.Duration{
+		message.HeartbeatServerProcess: 5 * time.Second,
+		message.GetComputeCertificate:  30 * time.Second,
+	}
+	if !reflect.DeepEqual(expected, timeouts) {
+		t.Fatalf("Expected %v but got %v", expected, timeouts)
+	}
+	assertEqual(t, "GetComputeCertificate=30s,HeartbeatServerProcess=5s", formatRequestTimeouts(timeouts))
+}
+
+func TestParseRequestTimeouts_Invalid(t *testing.T) {
+	for name, value := range map[string]string{
+		"missing duration": "StartMatchBackfill",
+		"invalid duration": "StartMatchBackfill=1 minute",
+	} {
+		t.Run(name, func(t *testing.T) {
+			// WHEN
+			_, err := parseRequestTimeouts(value)
+
+			// THEN
+			if err == nil {
+				t.Fatalf("expected an error parsing %q", value)
+			}
+		})
+	}
+}
+
+// GIVEN timeout of StartMatchBackfill WHEN the response isn't received in time THEN the timeout is used and logged
+func TestHandleRequest_UsesAndLogsActionTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	manager := mock.NewMockIGameLiftManager(ctrl)
+	logger := mock.NewMockILogger(ctrl)
+	previousLogger := lg
+	lg = logger
+	t.Cleanup(func() { lg = previousLogger })
+
+	// GIVEN
+	timeouts := newRequestTimeouts(map[message.MessageAction]time.Duration{message.StartMatchBackfill: time.Minute}, 20*time.Second)
+	state := gameLiftServerState{wsGameLift: manager, requestTimeouts: timeouts, serviceCallTimeout: 20 * time.Second}
+	state.isReadyProcess.Store(true)
+	req := request.NewStartMatchBackfill("test-game-session-arn", "test-configuration-arn", nil)
+	timeoutErr := common.NewGameLiftErrorWithCause(common.ServiceCallFailed, "", "", common.ErrResponseTimeout)
+
+	// EXPECT
+	manager.EXPECT().HandleRequest(&req, gomock.Any(), time.Minute).Return(timeoutErr)
+	logger.EXPECT().
+		Warnf("%s request %s exceeded its timeout of %s on attempt %d of %d",
+			message.StartMatchBackfill, req.RequestID, time.Minute, 1, 1)
+
+	// WHEN
+	_, err := state.startMatchBackfill(&req)
+
+	// THEN
+	if !errors.Is(err, common.ErrResponseTimeout) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+// GIVEN invalid timeout WHEN the server state is initialized THEN BadRequestException is returned before connecting
+func TestGameLiftServerState_InitInvalidRequestTimeout(t *testing.T) {
+	// GIVEN
+	params := testServerParams
+	params.RequestTimeouts = map[message.MessageAction]time.Duration{message.RemovePlayerSession: time.Second}
+
+	// WHEN
+	var state gameLiftServerState
+	err := state.init(&params, mock.NewMockIGameLiftManager(gomock.NewController(t)))
+
+	// THEN
+	if !errors.Is(err, common.ErrBadRequestException) {
+		t.Fatalf("expected BadRequestException, got %v", err)
+	}
+}
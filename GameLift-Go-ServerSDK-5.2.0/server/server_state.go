@@ -7,11 +7,12 @@ package server
 
 import (
 	"aws/amazon-gamelift-go-sdk/server/internal/security"
+	"context"
 	"fmt"
 	"github.com/google/uuid"
-	"log"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
@@ -20,13 +21,22 @@ import (
 	"aws/amazon-gamelift-go-sdk/model/message"
 	"aws/amazon-gamelift-go-sdk/model/request"
 	"aws/amazon-gamelift-go-sdk/model/result"
+	"aws/amazon-gamelift-go-sdk/server/authtoken"
+	"aws/amazon-gamelift-go-sdk/server/credentials"
 	"aws/amazon-gamelift-go-sdk/server/internal"
+	"aws/amazon-gamelift-go-sdk/server/internal/transport"
+	"aws/amazon-gamelift-go-sdk/server/metrics"
 )
 
 var localRnd *rand.Rand
 
+// ActivateServerProcessRequestTimeoutInSeconds - the default timeout of ActivateServerProcess,
+// see ServerParameters.RequestTimeouts.
 const ActivateServerProcessRequestTimeoutInSeconds = time.Duration(6) * time.Second
 
+// containerEndpointTimeout - the timeout of the requests to the container credentials and metadata endpoints.
+const containerEndpointTimeout = 5 * time.Second
+
 func init() {
 	//nolint:gosec // Use a weak random generator is enough in this case
 	localRnd = rand.New(rand.NewSource(time.Now().Unix()))
@@ -46,6 +56,7 @@ type iGameLiftServerState interface {
 	stopMatchBackfill(*request.StopMatchBackfillRequest) error
 	getComputeCertificate() (result.GetComputeCertificateResult, error)
 	getFleetRoleCredentials(*request.GetFleetRoleCredentialsRequest) (result.GetFleetRoleCredentialsResult, error)
+	updateAwsCredentials(accessKey, secretKey, sessionToken string) error
 	destroy() error
 }
 
@@ -63,22 +74,59 @@ type gameLiftServerState struct {
 	isReadyProcess common.AtomicBool
 	onManagedEC2   bool
 
+	// callbackPanicked - a user callback panicked since the last health check.
+	callbackPanicked common.AtomicBool
+
 	fleetRoleResultCache map[string]result.GetFleetRoleCredentialsResult
 	mtx                  sync.Mutex
 
+	playerSessionCache *playerSessionCache
+
 	defaultJitterIntervalMs int64
 	healthCheckInterval     time.Duration
 	healthCheckTimeout      time.Duration
 	serviceCallTimeout      time.Duration
+	// requestTimeouts - timeouts of the requests by action.
+	requestTimeouts map[message.MessageAction]time.Duration
+	// retryPolicies - retry policies of the idempotent requests by action.
+	retryPolicies map[message.MessageAction]RetryPolicy
+
+	clock common.Clock
+	// metrics - the metrics sink, see SetMetricsInterface.
+	metrics metrics.IMetrics
+	// httpClient - the client of the container credentials and metadata endpoints.
+	httpClient transport.HttpClient
+
+	// urlSigner - signs the connect URL with SigV4 if the SDK is not initialized with an auth token,
+	// or adds the token of the auth token provider.
+	urlSigner transport.URLSigner
+	// authTokenProvided - the auth token is retrieved from a provider on every connect.
+	authTokenProvided bool
+	// staticCredentials - AWS keys passed to InitSDK, nil if the credentials are retrieved from a provider.
+	staticCredentials *credentials.StaticProvider
 
 	shutdown chan bool
 }
 
+// getMetrics - returns the metrics sink set by InitSDK, or the one of SetMetricsInterface.
+func (state *gameLiftServerState) getMetrics() metrics.IMetrics {
+	if state.metrics == nil {
+		return mtr
+	}
+	return state.metrics
+}
+
 func (state *gameLiftServerState) init(params *ServerParameters, wsGameLift internal.IGameLiftManager) error {
 	if params == nil {
 		return common.NewGameLiftError(common.GameLiftServerNotInitialized, "", "")
 	}
 	state.fleetRoleResultCache = make(map[string]result.GetFleetRoleCredentialsResult)
+	if state.clock == nil {
+		state.clock = common.NewRealClock()
+	}
+	if state.httpClient == nil {
+		state.httpClient = &http.Client{Timeout: containerEndpointTimeout}
+	}
 	state.processID = common.GetEnvStringOrDefault(common.EnvironmentKeyProcessID, params.ProcessID)
 	state.hostID = common.GetEnvStringOrDefault(common.EnvironmentKeyHostID, params.HostID)
 	state.fleetID = common.GetEnvStringOrDefault(common.EnvironmentKeyFleetID, params.FleetID)
@@ -95,104 +143,184 @@ func (state *gameLiftServerState) init(params *ServerParameters, wsGameLift inte
 		state.processID = uuid.New().String()
 	}
 	isContainerComputeType := computeType == common.ComputeTypeContainer
-	authTokenPassed := authToken != ""
-	sigV4ParametersPassed := awsRegion != "" && accessKey != "" && secretKey != ""
+	var authTokenProvider authtoken.Provider
+	if authToken == "" {
+		authTokenProvider = params.AuthTokenProvider
+		if tokenFile := common.GetEnvStringOrDefault(common.EnvironmentKeyAuthTokenFile, ""); tokenFile != "" {
+			authTokenProvider = authtoken.NewFileProvider(tokenFile)
+		}
+	}
+	authTokenPassed := authToken != "" || authTokenProvider != nil
+	credentialsPassed := accessKey != "" && secretKey != "" || params.CredentialsProvider != nil
+	sigV4ParametersPassed := awsRegion != "" && credentialsPassed
 	if !authTokenPassed && !sigV4ParametersPassed && !isContainerComputeType {
 		return common.NewGameLiftError(common.BadRequestException, "", "Either AuthToken or AwsRegion and AwsCredentials are required")
 	}
 
 	state.onManagedEC2 = true
-	state.defaultJitterIntervalMs = common.GetEnvDurationOrDefault(
-		common.HealthcheckMaxJitter,
-		common.HealthcheckMaxJitterDefault,
-		lg,
-	).Milliseconds()
-	state.healthCheckInterval = common.GetEnvDurationOrDefault(
-		common.HealthcheckInterval,
-		common.HealthcheckIntervalDefault,
-		lg,
-	)
-	state.healthCheckTimeout = common.GetEnvDurationOrDefault(
-		common.HealthcheckTimeout,
-		common.HealthcheckTimeoutDefault,
-		lg,
-	)
-	state.serviceCallTimeout = common.GetEnvDurationOrDefault(
-		common.ServiceCallTimeout,
-		common.ServiceCallTimeoutDefault,
-		lg,
-	)
+	cfg := DefaultConfig()
+	if params.Config != nil {
+		// Resolved by InitSDK, including the settings of ServerParameters
+		cfg = *params.Config
+	} else {
+		cfg.applyParameters(params)
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	state.defaultJitterIntervalMs = cfg.HealthcheckMaxJitter.Milliseconds()
+	state.healthCheckInterval = cfg.HealthcheckInterval
+	state.healthCheckTimeout = cfg.HealthcheckTimeout
+	state.serviceCallTimeout = cfg.ServiceCallTimeout
+
+	state.requestTimeouts = newRequestTimeouts(cfg.RequestTimeouts, state.serviceCallTimeout)
+	retryPolicies, err := newRetryPolicies(params.RetryPolicies, cfg.RequestRetry)
+	if err != nil {
+		return err
+	}
+	state.retryPolicies = retryPolicies
 
-	var sigV4QueryParameters map[string]string
-	if !authTokenPassed {
-		if isContainerComputeType {
-			httpClient := &http.Client{}
+	state.playerSessionCache = nil
+	if cfg.PlayerSessionCacheTTL > 0 {
+		state.playerSessionCache = newPlayerSessionCache(cfg.PlayerSessionCacheTTL, cfg.PlayerSessionCacheMaxEntries, state.clock)
+	}
 
-			credentialFetcher, err := security.NewContainerCredentialsFetcher(httpClient)
-			if err != nil {
-				log.Fatalf("Failed to create Container Credentials Fetcher: %v", err)
-				return err
-			}
-			awsCredentials, err := credentialFetcher.FetchContainerCredentials()
-			if err != nil {
-				log.Fatalf("Failed to fetch container credentials: %v", err)
-				return err
+	state.urlSigner = nil
+	state.staticCredentials = nil
+	state.authTokenProvided = authTokenProvider != nil
+	if authTokenProvider != nil {
+		// The token is retrieved again on every connect and reconnect
+		state.urlSigner = security.NewAuthTokenURLSigner(
+			authTokenProviderAdapter{provider: authTokenProvider, timeout: state.serviceCallTimeout},
+		)
+	}
+	if !authTokenPassed {
+		var credentialsProvider credentials.Provider
+		switch {
+		case isContainerComputeType:
+			// As in the previous versions, the container credentials take precedence over AccessKey and SecretKey
+			credentialsProvider = params.CredentialsProvider
+			if credentialsProvider == nil {
+				credentialsProvider = credentials.NewContainerProviderWithClient(state.httpClient)
 			}
-			accessKey = awsCredentials.AccessKey
-			secretKey = awsCredentials.SecretKey
-			sessionToken = awsCredentials.SessionToken
+		case accessKey != "" && secretKey != "":
+			state.staticCredentials = credentials.NewStaticProvider(accessKey, secretKey, sessionToken)
+			credentialsProvider = state.staticCredentials
+		default:
+			credentialsProvider = params.CredentialsProvider
+		}
 
-			containerMetadataFetcher, err := security.NewContainerMetadataFetcher(httpClient)
+		if isContainerComputeType {
+			containerMetadataFetcher, err := security.NewContainerMetadataFetcher(state.httpClient)
 			if err != nil {
-				log.Fatalf("Failed to create Container Metadata Fetcher: %v", err)
-				return err
+				return common.NewGameLiftErrorWithCause(common.MetadataFetchFailed, "", "", err)
 			}
 			containerTaskMetadata, err := containerMetadataFetcher.FetchContainerTaskMetadata()
 			if err != nil {
-				log.Fatalf("Failed to fetch container task metadata: %v", err)
-				return err
+				return common.NewGameLiftErrorWithCause(common.MetadataFetchFailed, "", "", err)
 			}
 
 			state.hostID = containerTaskMetadata.TaskId
 		}
-		sigV4QueryParameters = getSigV4QueryParameters(awsRegion, accessKey, secretKey, sessionToken)
+		// The connect URL is signed again with fresh credentials on every connect and reconnect
+		state.urlSigner = security.NewSigV4URLSigner(
+			awsRegion,
+			credentialsProviderAdapter{provider: credentialsProvider, timeout: state.serviceCallTimeout},
+			state.clock,
+		)
+	}
+
+	signer := state.urlSigner
+	if signer != nil {
+		// Fail fast if the credentials can't be retrieved or the URL can't be signed,
+		// instead of retrying the connection until the retries are exhausted
+		if signer, err = state.presignURL(websocketUrl); err != nil {
+			return err
+		}
 	}
 
 	state.wsGameLift = wsGameLift
-	err := state.wsGameLift.Connect(
+	err = state.wsGameLift.Connect(
 		websocketUrl,
 		state.processID,
 		state.hostID,
 		state.fleetID,
 		authToken,
-		sigV4QueryParameters,
+		signer,
 	)
 	if err != nil {
-		return common.NewGameLiftError(common.LocalConnectionFailed, "", err.Error())
+		return common.NewGameLiftErrorWithCause(common.LocalConnectionFailed, "", "", err)
 	}
 	return nil
 }
 
-func getSigV4QueryParameters(awsRegion, accessKey, secretKey, sessionToken string) map[string]string {
-	awsCredentials := security.AwsCredentials{AccessKey: accessKey, SecretKey: secretKey, SessionToken: sessionToken}
-	queryParamsToSign := map[string]string{
-		common.ComputeIDKey: state.hostID,
-		common.FleetIDKey:   state.fleetID,
-		common.PidKey:       state.processID,
+// updateAwsCredentials - replaces the static AWS credentials used to sign the connect URL on the following reconnects.
+func (state *gameLiftServerState) updateAwsCredentials(accessKey, secretKey, sessionToken string) error {
+	if accessKey == "" || secretKey == "" {
+		return common.NewGameLiftError(common.BadRequestException, "", "AccessKey and SecretKey are required")
 	}
-
-	sigV4Parameters := security.SigV4Parameters{
-		AwsRegion:      awsRegion,
-		AwsCredentials: awsCredentials,
-		QueryParams:    queryParamsToSign,
-		RequestTime:    time.Now().UTC(),
+	if state.staticCredentials == nil {
+		return common.NewGameLiftError(common.BadRequestException, "", "The SDK was not initialized with static AWS credentials")
 	}
+	state.staticCredentials.Update(accessKey, secretKey, sessionToken)
+	return nil
+}
 
-	sigV4QueryParameters, err := security.GenerateSigV4QueryParameters(sigV4Parameters)
+// presignURL - signs the connect URL once, returns the common.CredentialsFetchFailed
+// or common.RequestSigningFailed error of the signer. Otherwise returns a signer which returns the signed URL
+// on the first connection attempt, so the credentials or the auth token are not retrieved twice,
+// and signs the URL again on the following attempts.
+func (state *gameLiftServerState) presignURL(websocketURL string) (transport.URLSigner, error) {
+	connectURL, err := internal.NewConnectURL(websocketURL, state.processID, state.hostID, state.fleetID, "")
 	if err != nil {
-		log.Fatalf("Error generating SigV4 query string: %v\n", err)
+		return nil, common.NewGameLiftErrorWithCause(common.BadRequestException, "", fmt.Sprintf("invalid websocket URL: %s", err), err)
 	}
-	return sigV4QueryParameters
+	signed, err := state.urlSigner(connectURL)
+	if err != nil {
+		return nil, err
+	}
+	signer := state.urlSigner
+	unsigned := connectURL.String()
+	var used common.AtomicBool
+	return func(u *url.URL) (*url.URL, error) {
+		if u.String() == unsigned && used.CompareAndSwap(false, true) {
+			return signed, nil
+		}
+		return signer(u)
+	}, nil
+}
+
+// credentialsProviderAdapter - adapts credentials.Provider to the provider used to sign the connect URL.
+type credentialsProviderAdapter struct {
+	provider credentials.Provider
+	timeout  time.Duration
+}
+
+func (a credentialsProviderAdapter) Retrieve() (security.AwsCredentials, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+	c, err := a.provider.Retrieve(ctx)
+	if err != nil {
+		return security.AwsCredentials{}, err
+	}
+	return security.AwsCredentials{
+		AccessKey:    c.AccessKeyID,
+		SecretKey:    c.SecretAccessKey,
+		SessionToken: c.SessionToken,
+		Expiration:   c.Expires,
+	}, nil
+}
+
+// authTokenProviderAdapter - adapts authtoken.Provider to the provider used to sign the connect URL.
+type authTokenProviderAdapter struct {
+	provider authtoken.Provider
+	timeout  time.Duration
+}
+
+func (a authTokenProviderAdapter) AuthToken() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+	return a.provider.AuthToken(ctx)
 }
 
 func (state *gameLiftServerState) processReady(params *ProcessParameters) error {
@@ -209,10 +337,10 @@ func (state *gameLiftServerState) processReady(params *ProcessParameters) error
 	req.LogPaths = params.LogParameters.LogPaths
 
 	// Wait for response from ActivateServerProcess() request
-	err := state.wsGameLift.HandleRequest(req, &res, ActivateServerProcessRequestTimeoutInSeconds)
+	err := state.handleRequest(&req, &res)
 
 	if err != nil {
-		return common.NewGameLiftError(common.ProcessNotReady, "", err.Error())
+		return common.NewGameLiftErrorWithCause(common.ProcessNotReady, "", "", err)
 	}
 	state.isReadyProcess.Store(true)
 	state.shutdown = make(chan bool)
@@ -223,7 +351,7 @@ func (state *gameLiftServerState) processReady(params *ProcessParameters) error
 func (state *gameLiftServerState) processEnding() error {
 	err := state.wsGameLift.SendMessage(request.NewTerminateServerProcess())
 	if err != nil {
-		return common.NewGameLiftError(common.ProcessEndingFailed, "", err.Error())
+		return common.NewGameLiftErrorWithCause(common.ProcessEndingFailed, "", "", err)
 	}
 	state.stopServerProcess()
 
@@ -278,6 +406,7 @@ func (state *gameLiftServerState) acceptPlayerSession(playerSessionID string) er
 	}
 	req := request.NewAcceptPlayerSession(state.gameSessionID, playerSessionID)
 	err := state.wsGameLift.SendMessage(req)
+	state.invalidatePlayerSession(playerSessionID)
 	return err
 }
 
@@ -290,9 +419,17 @@ func (state *gameLiftServerState) removePlayerSession(playerSessionID string) er
 	}
 	req := request.NewRemovePlayerSession(state.gameSessionID, playerSessionID)
 	err := state.wsGameLift.SendMessage(req)
+	state.invalidatePlayerSession(playerSessionID)
 	return err
 }
 
+// invalidatePlayerSession - drops cached lookups of the player session whose status is changed.
+func (state *gameLiftServerState) invalidatePlayerSession(playerSessionID string) {
+	if state.playerSessionCache != nil {
+		state.playerSessionCache.invalidate(playerSessionID)
+	}
+}
+
 func (state *gameLiftServerState) describePlayerSessions(req *request.DescribePlayerSessionsRequest) (result.DescribePlayerSessionsResult, error) {
 	var playerSessionResult result.DescribePlayerSessionsResult
 	if !state.isReadyProcess.Load() {
@@ -301,7 +438,15 @@ func (state *gameLiftServerState) describePlayerSessions(req *request.DescribePl
 	if req == nil {
 		return playerSessionResult, common.NewGameLiftError(common.BadRequestException, "", "")
 	}
-	err := state.wsGameLift.HandleRequest(req, &playerSessionResult, state.serviceCallTimeout)
+	// The RequestId of the copy is renewed if the request is retried
+	retryReq := *req
+	if state.playerSessionCache != nil {
+		return state.playerSessionCache.describe(req, func() (result.DescribePlayerSessionsResult, error) {
+			err := state.handleRequest(&retryReq, &playerSessionResult)
+			return playerSessionResult, err
+		})
+	}
+	err := state.handleRequest(&retryReq, &playerSessionResult)
 	return playerSessionResult, err
 }
 
@@ -313,7 +458,7 @@ func (state *gameLiftServerState) startMatchBackfill(req *request.StartMatchBack
 	if req == nil {
 		return startMatchBackfillResult, common.NewGameLiftError(common.BadRequestException, "", "")
 	}
-	err := state.wsGameLift.HandleRequest(req, &startMatchBackfillResult, state.serviceCallTimeout)
+	err := state.handleRequest(req, &startMatchBackfillResult)
 	return startMatchBackfillResult, err
 }
 
@@ -334,19 +479,20 @@ func (state *gameLiftServerState) getComputeCertificate() (result.GetComputeCert
 	if !state.isReadyProcess.Load() {
 		return res, common.NewGameLiftError(common.ProcessNotReady, "", "")
 	}
-	err := state.wsGameLift.HandleRequest(request.NewGetComputeCertificate(), &res, state.serviceCallTimeout)
+	req := request.NewGetComputeCertificate()
+	err := state.handleRequest(&req, &res)
 	return res, err
 }
 
-func (state *gameLiftServerState) getRoleCredentialsFromCache(roleArn string) (result.GetFleetRoleCredentialsResult, bool) {
+func (state *gameLiftServerState) getRoleCredentialsFromCache(cacheKey string) (result.GetFleetRoleCredentialsResult, bool) {
 	state.mtx.Lock()
 	defer state.mtx.Unlock()
-	if previousResult, ok := state.fleetRoleResultCache[roleArn]; ok {
-		timeToLive := time.Duration(previousResult.Expiration-time.Now().UnixMilli()) * time.Millisecond
+	if previousResult, ok := state.fleetRoleResultCache[cacheKey]; ok {
+		timeToLive := time.Duration(previousResult.Expiration-state.clock.Now().UnixMilli()) * time.Millisecond
 		if timeToLive > common.InstanceRoleCredentialTTL {
 			return previousResult, true
 		}
-		delete(state.fleetRoleResultCache, roleArn)
+		delete(state.fleetRoleResultCache, cacheKey)
 	}
 	return result.GetFleetRoleCredentialsResult{}, false
 }
@@ -359,10 +505,6 @@ func (state *gameLiftServerState) getFleetRoleCredentials(
 		return result.GetFleetRoleCredentialsResult{},
 			common.NewGameLiftError(common.BadRequestException, "", "")
 	}
-	res, ok := state.getRoleCredentialsFromCache(req.RoleArn)
-	if ok {
-		return res, nil
-	}
 	// If role session name was not provided, default to fleetId-hostId
 	if req.RoleSessionName == "" {
 		req.RoleSessionName = fmt.Sprintf("%s-%s", state.fleetID, state.hostID)
@@ -372,14 +514,21 @@ func (state *gameLiftServerState) getFleetRoleCredentials(
 	}
 	// Role session name cannot be over 64 chars (enforced by IAM's AssumeRole API)
 	if len(req.RoleSessionName) > common.RoleSessionNameMaxLength {
-		return res, common.NewGameLiftError(common.BadRequestException, "", "")
+		return result.GetFleetRoleCredentialsResult{}, common.NewGameLiftError(common.BadRequestException, "", "")
+	}
+	// Credentials of the same role with different session names are cached separately
+	cacheKey := req.RoleArn + "/" + req.RoleSessionName
+	res, ok := state.getRoleCredentialsFromCache(cacheKey)
+	if ok {
+		return res, nil
 	}
 
 	if !state.isReadyProcess.Load() {
 		return res, common.NewGameLiftError(common.ProcessNotReady, "", "")
 	}
 
-	err := state.wsGameLift.HandleRequest(req, &res, state.serviceCallTimeout)
+	retryReq := *req
+	err := state.handleRequest(&retryReq, &res)
 	if err != nil {
 		return res, err
 	}
@@ -390,7 +539,7 @@ func (state *gameLiftServerState) getFleetRoleCredentials(
 
 	state.mtx.Lock()
 	defer state.mtx.Unlock()
-	state.fleetRoleResultCache[req.RoleArn] = res
+	state.fleetRoleResultCache[cacheKey] = res
 
 	return res, err
 }
@@ -411,8 +560,9 @@ func (state *gameLiftServerState) stopServerProcess() {
 func (state *gameLiftServerState) startHealthCheck(done <-chan bool) {
 	lg.Debugf("HealthCheck thread started.")
 	for state.isReadyProcess.Load() {
-		timeout := time.After(state.getNextHealthCheckIntervalSeconds())
-		go state.heartbeatServerProcess(done)
+		interval := state.getNextHealthCheckIntervalSeconds()
+		timeout := state.clock.After(interval)
+		go state.heartbeatServerProcess(done, state.clock.Now().Add(interval))
 		select {
 		case <-timeout:
 			continue
@@ -422,17 +572,22 @@ func (state *gameLiftServerState) startHealthCheck(done <-chan bool) {
 	}
 }
 
-func (state *gameLiftServerState) heartbeatServerProcess(done <-chan bool) {
-	res := make(chan bool)
+// heartbeatServerProcess - reports the health of the process. A failed heartbeat is retried only until nextHeartbeat,
+// when the next heartbeat reports the health anyway. A zero nextHeartbeat doesn't limit the retries.
+func (state *gameLiftServerState) heartbeatServerProcess(done <-chan bool, nextHeartbeat time.Time) {
+	// Buffered, so a late OnHealthCheck response does not block the callback goroutine forever
+	res := make(chan bool, 1)
 	go func(res chan<- bool) {
 		if state.parameters != nil && state.parameters.OnHealthCheck != nil {
 			lg.Debugf("Reporting health using the OnHealthCheck callback.")
-			res <- state.parameters.OnHealthCheck()
+			healthy := false
+			state.invokeCallback("OnHealthCheck", func() { healthy = state.parameters.OnHealthCheck() })
+			res <- healthy
 		} else {
 			close(res)
 		}
 	}(res)
-	timeout := time.After(state.healthCheckTimeout)
+	timeout := state.clock.After(state.healthCheckTimeout)
 	status := false
 	select {
 	case <-timeout:
@@ -443,12 +598,13 @@ func (state *gameLiftServerState) heartbeatServerProcess(done <-chan bool) {
 	case <-done:
 		return
 	}
+	if state.callbackPanicked.Swap(false) {
+		lg.Warnf("A callback panicked since the last health check. Reporting as unhealthy.")
+		status = false
+	}
 	var response message.Message
-	err := state.wsGameLift.HandleRequest(
-		request.NewHeartbeatServerProcess(status),
-		&response,
-		state.serviceCallTimeout,
-	)
+	req := request.NewHeartbeatServerProcess(status)
+	err := state.handleRequestBefore(&req, &response, nextHeartbeat)
 	if err != nil {
 		lg.Warnf("Could not send health status: %s", err)
 	}
@@ -459,6 +615,9 @@ func (state *gameLiftServerState) heartbeatServerProcess(done <-chan bool) {
 //
 //nolint:gosec // weak math random generator is enough in this case
 func (state *gameLiftServerState) getNextHealthCheckIntervalSeconds() time.Duration {
+	if state.defaultJitterIntervalMs <= 0 {
+		return state.healthCheckInterval
+	}
 	jitterMs := 2*localRnd.Int63n(state.defaultJitterIntervalMs) - state.defaultJitterIntervalMs
 	return state.healthCheckInterval - time.Duration(jitterMs)*time.Millisecond
 }
@@ -477,8 +636,11 @@ func (state *gameLiftServerState) OnStartGameSession(session *model.GameSession)
 		return
 	}
 	state.gameSessionID = session.GameSessionID
+	if state.playerSessionCache != nil {
+		state.playerSessionCache.clear()
+	}
 	if state.parameters != nil && state.parameters.OnStartGameSession != nil {
-		state.parameters.OnStartGameSession(*session)
+		state.invokeCallback("OnStartGameSession", func() { state.parameters.OnStartGameSession(*session) })
 	}
 }
 
@@ -502,13 +664,15 @@ func (state *gameLiftServerState) OnUpdateGameSession(
 		return
 	}
 	if state.parameters != nil && state.parameters.OnUpdateGameSession != nil {
-		state.parameters.OnUpdateGameSession(
-			model.UpdateGameSession{
-				GameSession:      *gameSession,
-				UpdateReason:     updateReason,
-				BackfillTicketID: backfillTicketID,
-			},
-		)
+		state.invokeCallback("OnUpdateGameSession", func() {
+			state.parameters.OnUpdateGameSession(
+				model.UpdateGameSession{
+					GameSession:      *gameSession,
+					UpdateReason:     updateReason,
+					BackfillTicketID: backfillTicketID,
+				},
+			)
+		})
 	}
 }
 
@@ -518,20 +682,28 @@ func (state *gameLiftServerState) OnTerminateProcess(terminationTime int64) {
 	state.terminationTime = terminationTime / 1000
 	lg.Debugf("ServerState got the terminateProcess signal. termination time : %d", state.terminationTime)
 	if state.parameters != nil && state.parameters.OnProcessTerminate != nil {
-		state.parameters.OnProcessTerminate()
+		state.invokeCallback("OnProcessTerminate", state.parameters.OnProcessTerminate)
 	}
 }
 
 // OnRefreshConnection - callback function that the Gamelift service invokes when
 // the server process need to refresh current websocket connection.
 func (state *gameLiftServerState) OnRefreshConnection(refreshConnectionEndpoint, authToken string) {
+	// The auth token provider takes precedence over the token of the message, so later reconnects get fresh tokens too
+	if state.authTokenProvided {
+		authToken = ""
+	}
+	var signer transport.URLSigner
+	if authToken == "" {
+		signer = state.urlSigner
+	}
 	err := state.wsGameLift.Connect(
 		refreshConnectionEndpoint,
 		state.processID,
 		state.hostID,
 		state.fleetID,
 		authToken,
-		nil,
+		signer,
 	)
 	if err != nil {
 		lg.Errorf("Failed to refresh websocket connection. The GameLift SDK will try again each minute "+
@@ -539,6 +711,14 @@ func (state *gameLiftServerState) OnRefreshConnection(refreshConnectionEndpoint,
 	}
 }
 
+// OnUnknownMessage - handler of the messages with an action unknown to the SDK (already started in a separate goroutine).
+func (state *gameLiftServerState) OnUnknownMessage(action message.MessageAction, data []byte) {
+	state.getMetrics().IncrCounter(metrics.UnknownMessages, 1)
+	if state.parameters != nil && state.parameters.OnUnknownMessage != nil {
+		state.invokeCallback("OnUnknownMessage", func() { state.parameters.OnUnknownMessage(string(action), data) })
+	}
+}
+
 func isChannelOpen(ch <-chan bool) bool {
 	select {
 	case <-ch:
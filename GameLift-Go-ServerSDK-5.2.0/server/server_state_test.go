@@ -6,10 +6,17 @@
 package server
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,7 +28,13 @@ import (
 	"aws/amazon-gamelift-go-sdk/model/message"
 	"aws/amazon-gamelift-go-sdk/model/request"
 	"aws/amazon-gamelift-go-sdk/model/result"
+	"aws/amazon-gamelift-go-sdk/server/authtoken"
+	"aws/amazon-gamelift-go-sdk/server/credentials"
+	"aws/amazon-gamelift-go-sdk/server/internal"
 	"aws/amazon-gamelift-go-sdk/server/internal/mock"
+	"aws/amazon-gamelift-go-sdk/server/internal/security"
+	"aws/amazon-gamelift-go-sdk/server/internal/transport"
+	"aws/amazon-gamelift-go-sdk/server/metrics"
 )
 
 const TestRequestId = "00000000-1111-2222-3333-444444444444"
@@ -197,10 +210,12 @@ func TestGameLiftServerStateLifecycle_AuthTokenPassed(t *testing.T) {
 		}), gomock.Any(), ActivateSeverProcessRequestTimeoutInSeconds).
 		Times(1)
 
+	heartbeats := make(chan struct{}, 1)
 	manager.
 		EXPECT().
 		HandleRequest(ignoreRequestID(request.NewHeartbeatServerProcess(true)), gomock.Any(), 20*time.Second).
-		MinTimes(1)
+		Do(func(any, any, any) { notify(heartbeats) }).
+		MinTimes(2)
 
 	const (
 		newWebSocketURL = "wss://new-test.url"
@@ -222,7 +237,9 @@ func TestGameLiftServerStateLifecycle_AuthTokenPassed(t *testing.T) {
 		Disconnect().
 		Times(1)
 
+	clock := mock.NewFakeClock(time.Now())
 	var state gameLiftServerState
+	state.clock = clock
 	err := state.init(&params, manager)
 	if err != nil {
 		t.Fatal(err)
@@ -240,8 +257,10 @@ func TestGameLiftServerStateLifecycle_AuthTokenPassed(t *testing.T) {
 	}
 	state.OnStartGameSession(&gameSession)
 
-	t.Logf("Tests are running, please wait")
-	time.Sleep(state.healthCheckInterval)
+	// The first heartbeat is sent right after ProcessReady, the next one after the health check interval
+	<-heartbeats
+	clock.Advance(state.healthCheckInterval + common.HealthcheckMaxJitterDefault)
+	<-heartbeats
 
 	err = state.processEnding()
 	if err != nil {
@@ -409,10 +428,12 @@ func TestGameLiftServerStateLifecycle_AwsCredentialsAndRegionPassed(t *testing.T
 		}), gomock.Any(), ActivateSeverProcessRequestTimeoutInSeconds).
 		Times(1)
 
+	heartbeats := make(chan struct{}, 1)
 	manager.
 		EXPECT().
 		HandleRequest(ignoreRequestID(request.NewHeartbeatServerProcess(true)), gomock.Any(), 20*time.Second).
-		MinTimes(1)
+		Do(func(any, any, any) { notify(heartbeats) }).
+		MinTimes(2)
 
 	const (
 		newWebSocketURL = "wss://new-test.url"
@@ -434,7 +455,9 @@ func TestGameLiftServerStateLifecycle_AwsCredentialsAndRegionPassed(t *testing.T
 		Disconnect().
 		Times(1)
 
+	clock := mock.NewFakeClock(time.Now())
 	var state gameLiftServerState
+	state.clock = clock
 	err := state.init(&params, manager)
 	if err != nil {
 		t.Fatal(err)
@@ -452,8 +475,10 @@ func TestGameLiftServerStateLifecycle_AwsCredentialsAndRegionPassed(t *testing.T
 	}
 	state.OnStartGameSession(&gameSession)
 
-	t.Logf("Tests are running, please wait")
-	time.Sleep(state.healthCheckInterval)
+	// The first heartbeat is sent right after ProcessReady, the next one after the health check interval
+	<-heartbeats
+	clock.Advance(state.healthCheckInterval + common.HealthcheckMaxJitterDefault)
+	<-heartbeats
 
 	err = state.processEnding()
 	if err != nil {
@@ -545,10 +570,12 @@ func TestGameLiftServerStateLifecycle_AuthTokenAndAwsCredentialsAndRegionPassed(
 		}), gomock.Any(), ActivateSeverProcessRequestTimeoutInSeconds).
 		Times(1)
 
+	heartbeats := make(chan struct{}, 1)
 	manager.
 		EXPECT().
 		HandleRequest(ignoreRequestID(request.NewHeartbeatServerProcess(true)), gomock.Any(), 20*time.Second).
-		MinTimes(1)
+		Do(func(any, any, any) { notify(heartbeats) }).
+		MinTimes(2)
 
 	const (
 		newWebSocketURL = "wss://new-test.url"
@@ -570,7 +597,9 @@ func TestGameLiftServerStateLifecycle_AuthTokenAndAwsCredentialsAndRegionPassed(
 		Disconnect().
 		Times(1)
 
+	clock := mock.NewFakeClock(time.Now())
 	var state gameLiftServerState
+	state.clock = clock
 	err := state.init(&params, manager)
 	if err != nil {
 		t.Fatal(err)
@@ -588,8 +617,10 @@ func TestGameLiftServerStateLifecycle_AuthTokenAndAwsCredentialsAndRegionPassed(
 	}
 	state.OnStartGameSession(&gameSession)
 
-	t.Logf("Tests are running, please wait")
-	time.Sleep(state.healthCheckInterval)
+	// The first heartbeat is sent right after ProcessReady, the next one after the health check interval
+	<-heartbeats
+	clock.Advance(state.healthCheckInterval + common.HealthcheckMaxJitterDefault)
+	<-heartbeats
 
 	err = state.processEnding()
 	if err != nil {
@@ -648,7 +679,9 @@ func TestFleetRoleCredentialsCache(t *testing.T) {
 
 	roleArn := "TEST_ROLE_ARN"
 
+	clock := mock.NewFakeClock(time.Now())
 	var state gameLiftServerState
+	state.clock = clock
 	err := state.init(&params, manager)
 	if err != nil {
 		t.Fatal(err)
@@ -662,20 +695,27 @@ func TestFleetRoleCredentialsCache(t *testing.T) {
 
 	// When the cache has credentials that aren't yet close to expiration, return the credentials
 	state.fleetRoleResultCache[roleArn] = result.GetFleetRoleCredentialsResult{
-		Expiration: time.Now().Add(60 * time.Minute).UnixMilli(), // Expiration time is in milliseconds
+		Expiration: clock.Now().Add(60 * time.Minute).UnixMilli(), // Expiration time is in milliseconds
 	}
 	credentials, returnedPrevious = state.getRoleCredentialsFromCache(roleArn)
 	if !returnedPrevious {
 		t.Error("Second get call failed to return the credentials even though they should be fresh", state.fleetRoleResultCache[roleArn], returnedPrevious)
 	}
 
+	// When the cached credentials get close to expiration over time, return nothing so system can refresh them
+	clock.Advance(60*time.Minute - common.InstanceRoleCredentialTTL)
+	credentials, returnedPrevious = state.getRoleCredentialsFromCache(roleArn)
+	if returnedPrevious {
+		t.Error("Third get call incorrectly returned the credentials when they're close to expiring", credentials, returnedPrevious)
+	}
+
 	// When the cache has credentials that are old, return nothing so system can refresh them
 	state.fleetRoleResultCache[roleArn] = result.GetFleetRoleCredentialsResult{
-		Expiration: time.Now().Add(5 * time.Minute).UnixMilli(), // Expiration time is in milliseconds
+		Expiration: clock.Now().Add(5 * time.Minute).UnixMilli(), // Expiration time is in milliseconds
 	}
 	credentials, returnedPrevious = state.getRoleCredentialsFromCache(roleArn)
 	if returnedPrevious {
-		t.Error("Third get call incorrectly returned the credentials when they're close to expiring", credentials, returnedPrevious)
+		t.Error("Fourth get call incorrectly returned the credentials when they're close to expiring", credentials, returnedPrevious)
 	}
 
 	// The rest of the life cycle is already unit tested
@@ -683,6 +723,57 @@ func TestFleetRoleCredentialsCache(t *testing.T) {
 	state.destroy()
 }
 
+// GIVEN OnHealthCheck callback that does not respond WHEN health check timeout expires THEN report unhealthy status
+func TestHeartbeatServerProcess_HealthCheckTimeout_ReportUnhealthy(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctrl := gomock.NewController(t)
+	manager := mock.NewMockIGameLiftManager(ctrl)
+
+	// GIVEN
+	release := make(chan struct{})
+	clock := mock.NewFakeClock(time.Now())
+	state := gameLiftServerState{
+		wsGameLift: manager,
+		parameters: &ProcessParameters{
+			OnHealthCheck: func() bool {
+				<-release
+				return true
+			},
+		},
+		healthCheckTimeout: common.HealthcheckTimeoutDefault,
+		serviceCallTimeout: common.ServiceCallTimeoutDefault,
+		clock:              clock,
+	}
+
+	// EXPECT
+	manager.
+		EXPECT().
+		HandleRequest(ignoreRequestID(request.NewHeartbeatServerProcess(false)), gomock.Any(), common.ServiceCallTimeoutDefault).
+		Times(1)
+
+	// WHEN
+	done := make(chan bool)
+	heartbeatFinished := make(chan struct{})
+	go func() {
+		state.heartbeatServerProcess(done, time.Time{})
+		close(heartbeatFinished)
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(common.HealthcheckTimeoutDefault)
+
+	// THEN
+	<-heartbeatFinished
+	close(release)
+}
+
+func notify(ch chan<- struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
 func ignoreRequestID(expect any) gomock.Matcher {
 	return &ignoreRequestIDEqual{expect: expect}
 }
@@ -696,6 +787,10 @@ func (i *ignoreRequestIDEqual) Matches(x any) bool {
 }
 
 func toStr(x any) string {
+	// Requests may be passed by pointer, so their RequestId can be renewed on retries
+	if v := reflect.ValueOf(x); v.Kind() == reflect.Ptr && !v.IsNil() {
+		x = v.Elem().Interface()
+	}
 	return requestIDMatcher.ReplaceAllString(fmt.Sprintf("%#v", x), `RequestID:"any"`)
 }
 
@@ -704,3 +799,502 @@ var requestIDMatcher = regexp.MustCompile(`RequestID:"[0-9a-f]{8}-[0-9a-f]{4}-[0
 func (i *ignoreRequestIDEqual) String() string {
 	return fmt.Sprintf("%v", i.expect)
 }
+
+func TestGetFleetRoleCredentials_CachedPerRoleSessionName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	manager := mock.NewMockIGameLiftManager(ctrl)
+
+	// GIVEN
+	clock := mock.NewFakeClock(time.Now())
+	state := gameLiftServerState{
+		wsGameLift:           manager,
+		onManagedEC2:         true,
+		fleetID:              "test-fleet-id",
+		hostID:               "test-host-id",
+		serviceCallTimeout:   common.ServiceCallTimeoutDefault,
+		fleetRoleResultCache: make(map[string]result.GetFleetRoleCredentialsResult),
+		clock:                clock,
+	}
+	state.isReadyProcess.Store(true)
+
+	// EXPECT
+	var sessionNames []string
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), gomock.Any(), common.ServiceCallTimeoutDefault).
+		DoAndReturn(func(req internal.MessageGetter, resp any, _ time.Duration) error {
+			sessionNames = append(sessionNames, req.(*request.GetFleetRoleCredentialsRequest).RoleSessionName)
+			*resp.(*result.GetFleetRoleCredentialsResult) = result.GetFleetRoleCredentialsResult{
+				AccessKeyID: "test-access-key",
+				Expiration:  clock.Now().Add(time.Hour).UnixMilli(),
+			}
+			return nil
+		}).
+		Times(3)
+
+	// WHEN
+	for _, sessionName := range []string{"session-1", "session-2", "", "session-1", "session-2", ""} {
+		req := request.NewGetFleetRoleCredentials()
+		req.RoleArn = "test-role-arn"
+		req.RoleSessionName = sessionName
+		if _, err := state.getFleetRoleCredentials(&req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// THEN
+	assertEqual(t, "session-1,session-2,test-fleet-id-test-host-id", strings.Join(sessionNames, ","))
+}
+
+func TestGameLiftServerState_SigV4SignerRotatesCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	manager := mock.NewMockIGameLiftManager(ctrl)
+
+	// GIVEN
+	params := ServerParameters{
+		WebSocketURL: "wss://test.url",
+		ProcessID:    "test-process-id",
+		HostID:       "test-host-id",
+		FleetID:      "test-fleet-id",
+		AwsRegion:    "us-west-2",
+		AccessKey:    "test_access_key",
+		SecretKey:    "test_secret_key",
+	}
+	connectURL, err := url.Parse("wss://test.url?pID=test-process-id&ComputeId=test-host-id&FleetId=test-fleet-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var signers []transport.URLSigner
+	captureSigner := func(_, _, _, _, _ string, signer transport.URLSigner) error {
+		signers = append(signers, signer)
+		return nil
+	}
+
+	// EXPECT
+	manager.
+		EXPECT().
+		Connect(params.WebSocketURL, params.ProcessID, params.HostID, params.FleetID, "", gomock.Not(gomock.Nil())).
+		DoAndReturn(captureSigner)
+	manager.
+		EXPECT().
+		Connect("wss://new-test.url", params.ProcessID, params.HostID, params.FleetID, "", gomock.Not(gomock.Nil())).
+		DoAndReturn(captureSigner)
+
+	clock := mock.NewFakeClock(time.Now())
+	state := gameLiftServerState{clock: clock}
+	if err = state.init(&params, manager); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	state.OnRefreshConnection("wss://new-test.url", "")
+	first, err := signers[1](connectURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = state.updateAwsCredentials("rotated_access_key", "rotated_secret_key", ""); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Minute)
+	second, err := signers[1](connectURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN
+	if !strings.HasPrefix(first.Query().Get(security.AmzCredentialKey), "test_access_key/") {
+		t.Errorf("unexpected credential: %s", first.Query().Get(security.AmzCredentialKey))
+	}
+	if !strings.HasPrefix(second.Query().Get(security.AmzCredentialKey), "rotated_access_key/") {
+		t.Errorf("unexpected credential: %s", second.Query().Get(security.AmzCredentialKey))
+	}
+	if first.Query().Get(security.AmzDateKey) == second.Query().Get(security.AmzDateKey) {
+		t.Errorf("the URL was not signed with the current time: %s", second)
+	}
+}
+
+func TestUpdateAwsCredentials_AuthTokenPassed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	manager := mock.NewMockIGameLiftManager(ctrl)
+
+	// GIVEN
+	params := ServerParameters{
+		WebSocketURL: "wss://test.url",
+		ProcessID:    "test-process-id",
+		HostID:       "test-host-id",
+		FleetID:      "test-fleet-id",
+		AuthToken:    "test-auth-token",
+	}
+	manager.
+		EXPECT().
+		Connect(params.WebSocketURL, params.ProcessID, params.HostID, params.FleetID, params.AuthToken, nil)
+	state := gameLiftServerState{clock: mock.NewFakeClock(time.Now())}
+	if err := state.init(&params, manager); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	err := state.updateAwsCredentials("rotated_access_key", "rotated_secret_key", "")
+
+	// THEN
+	var gameLiftErr *common.GameLiftError
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.BadRequestException {
+		t.Fatalf("expected BadRequestException, got %v", err)
+	}
+}
+
+func TestGameLiftServerState_CredentialsProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	manager := mock.NewMockIGameLiftManager(ctrl)
+
+	// GIVEN
+	params := ServerParameters{
+		WebSocketURL: "wss://test.url",
+		ProcessID:    "test-process-id",
+		HostID:       "test-host-id",
+		FleetID:      "test-fleet-id",
+		AwsRegion:    "us-west-2",
+		CredentialsProvider: credentials.ProviderFunc(func(context.Context) (credentials.Credentials, error) {
+			return credentials.Credentials{AccessKeyID: "provider_access_key", SecretAccessKey: "provider_secret_key"}, nil
+		}),
+	}
+	connectURL, err := url.Parse("wss://test.url?pID=test-process-id&ComputeId=test-host-id&FleetId=test-fleet-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// EXPECT
+	var signer transport.URLSigner
+	manager.
+		EXPECT().
+		Connect(params.WebSocketURL, params.ProcessID, params.HostID, params.FleetID, "", gomock.Not(gomock.Nil())).
+		DoAndReturn(func(_, _, _, _, _ string, s transport.URLSigner) error {
+			signer = s
+			return nil
+		})
+
+	// WHEN
+	state := gameLiftServerState{clock: mock.NewFakeClock(time.Now())}
+	if err = state.init(&params, manager); err != nil {
+		t.Fatal(err)
+	}
+	signed, err := signer(connectURL)
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(signed.Query().Get(security.AmzCredentialKey), "provider_access_key/") {
+		t.Errorf("unexpected credential: %s", signed.Query().Get(security.AmzCredentialKey))
+	}
+}
+
+func TestGameLiftServerState_CredentialsProviderWithoutRegion(t *testing.T) {
+	// GIVEN
+	params := ServerParameters{
+		WebSocketURL:        "wss://test.url",
+		ProcessID:           "test-process-id",
+		HostID:              "test-host-id",
+		FleetID:             "test-fleet-id",
+		CredentialsProvider: credentials.NewStaticProvider("test_access_key", "test_secret_key", ""),
+	}
+
+	// WHEN
+	var state gameLiftServerState
+	err := state.init(&params, mock.NewMockIGameLiftManager(gomock.NewController(t)))
+
+	// THEN
+	var gameLiftErr *common.GameLiftError
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.BadRequestException {
+		t.Fatalf("expected BadRequestException, got %v", err)
+	}
+}
+
+func TestGameLiftServerState_AuthTokenProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	manager := mock.NewMockIGameLiftManager(ctrl)
+
+	// GIVEN
+	tokens := []string{"first-token", "second-token", "third-token"}
+	params := ServerParameters{
+		WebSocketURL: "wss://test.url",
+		ProcessID:    "test-process-id",
+		HostID:       "test-host-id",
+		FleetID:      "test-fleet-id",
+		AuthTokenProvider: authtoken.ProviderFunc(func(context.Context) (string, error) {
+			token := tokens[0]
+			tokens = tokens[1:]
+			return token, nil
+		}),
+	}
+	connectURL, err := internal.NewConnectURL(params.WebSocketURL, params.ProcessID, params.HostID, params.FleetID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// EXPECT
+	var signers []transport.URLSigner
+	saveSigner := func(_, _, _, _, _ string, s transport.URLSigner) error {
+		signers = append(signers, s)
+		return nil
+	}
+	manager.
+		EXPECT().
+		Connect(params.WebSocketURL, params.ProcessID, params.HostID, params.FleetID, "", gomock.Not(gomock.Nil())).
+		DoAndReturn(saveSigner)
+	manager.
+		EXPECT().
+		Connect("wss://new-test.url", params.ProcessID, params.HostID, params.FleetID, "", gomock.Not(gomock.Nil())).
+		DoAndReturn(saveSigner)
+
+	// WHEN
+	state := gameLiftServerState{clock: mock.NewFakeClock(time.Now())}
+	if err = state.init(&params, manager); err != nil {
+		t.Fatal(err)
+	}
+	state.OnRefreshConnection("wss://new-test.url", "message-token")
+	first, err := signers[0](connectURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := signers[1](connectURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	retried, err := signers[0](connectURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN
+	// The token retrieved to check the provider during init is used for the first connection attempt
+	assertEqual(t, first.Query().Get(common.AuthTokenKey), "first-token")
+	assertEqual(t, second.Query().Get(common.AuthTokenKey), "second-token")
+	assertEqual(t, retried.Query().Get(common.AuthTokenKey), "third-token")
+}
+
+func TestGameLiftServerState_AuthTokenFileFromEnvironment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	manager := mock.NewMockIGameLiftManager(ctrl)
+
+	// GIVEN
+	tokenFile := filepath.Join(t.TempDir(), "auth-token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(common.EnvironmentKeyAuthTokenFile, tokenFile)
+	params := ServerParameters{
+		WebSocketURL: "wss://test.url",
+		ProcessID:    "test-process-id",
+		HostID:       "test-host-id",
+		FleetID:      "test-fleet-id",
+	}
+	connectURL, err := url.Parse("wss://test.url?pID=test-process-id&ComputeId=test-host-id&FleetId=test-fleet-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// EXPECT
+	var signer transport.URLSigner
+	manager.
+		EXPECT().
+		Connect(params.WebSocketURL, params.ProcessID, params.HostID, params.FleetID, "", gomock.Not(gomock.Nil())).
+		DoAndReturn(func(_, _, _, _, _ string, s transport.URLSigner) error {
+			signer = s
+			return nil
+		})
+
+	// WHEN
+	state := gameLiftServerState{clock: mock.NewFakeClock(time.Now())}
+	if err = state.init(&params, manager); err != nil {
+		t.Fatal(err)
+	}
+	signed, err := signer(connectURL)
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, signed.Query().Get(common.AuthTokenKey), "file-token")
+}
+
+func newContainerEndpointResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestGameLiftServerState_InitContainerFailures(t *testing.T) {
+	const (
+		metadataURI    = "http://169.254.170.2/v4/metadata"
+		credentialsURI = "http://169.254.170.2/v2/credentials"
+		validMetadata  = `{"TaskARN": "arn:aws:ecs:us-west-2:123456789012:task/test-cluster/test-task-id"}`
+	)
+	tests := []struct {
+		name        string
+		metadata    func() (*http.Response, error)
+		credentials func() (*http.Response, error)
+		want        common.GameLiftErrorType
+	}{
+		{
+			name:     "metadata request failed",
+			metadata: func() (*http.Response, error) { return nil, errors.New("test error") },
+			want:     common.MetadataFetchFailed,
+		},
+		{
+			name:     "metadata unsuccessful response",
+			metadata: func() (*http.Response, error) { return newContainerEndpointResponse(http.StatusNotFound, ""), nil },
+			want:     common.MetadataFetchFailed,
+		},
+		{
+			name:     "credentials unsuccessful response",
+			metadata: func() (*http.Response, error) { return newContainerEndpointResponse(http.StatusOK, validMetadata), nil },
+			credentials: func() (*http.Response, error) {
+				return newContainerEndpointResponse(http.StatusInternalServerError, ""), nil
+			},
+			want: common.CredentialsFetchFailed,
+		},
+		{
+			name:     "credentials without secret key",
+			metadata: func() (*http.Response, error) { return newContainerEndpointResponse(http.StatusOK, validMetadata), nil },
+			credentials: func() (*http.Response, error) {
+				return newContainerEndpointResponse(http.StatusOK, `{"AccessKeyId": "containerAccessKey"}`), nil
+			},
+			want: common.RequestSigningFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			httpClient := mock.NewMockHttpClient(ctrl)
+
+			// GIVEN
+			t.Setenv(common.EnvironmentKeyComputeType, common.ComputeTypeContainer)
+			t.Setenv(common.EnvironmentKeyAwsRegion, "us-west-2")
+			t.Setenv("ECS_CONTAINER_METADATA_URI_V4", metadataURI)
+			t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "/v2/credentials")
+			params := ServerParameters{WebSocketURL: "wss://test.url", ProcessID: "test-process-id", FleetID: "test-fleet-id"}
+
+			// EXPECT
+			httpClient.EXPECT().Get(gomock.Any()).DoAndReturn(func(string) (*http.Response, error) {
+				return tt.metadata()
+			})
+			if tt.credentials != nil {
+				httpClient.EXPECT().Get(credentialsURI).DoAndReturn(func(string) (*http.Response, error) {
+					return tt.credentials()
+				})
+			}
+
+			// WHEN
+			state := gameLiftServerState{clock: mock.NewFakeClock(time.Now()), httpClient: httpClient}
+			err := state.init(&params, mock.NewMockIGameLiftManager(ctrl))
+
+			// THEN
+			var gameLiftErr *common.GameLiftError
+			if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != tt.want {
+				t.Fatalf("expected error type %d, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// GIVEN CONTAINER compute and AWS keys WHEN init THEN the connect URL is signed with the container credentials
+func TestGameLiftServerState_InitContainerIgnoresAccessKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	manager := mock.NewMockIGameLiftManager(ctrl)
+	httpClient := mock.NewMockHttpClient(ctrl)
+
+	// GIVEN
+	t.Setenv(common.EnvironmentKeyComputeType, common.ComputeTypeContainer)
+	t.Setenv("ECS_CONTAINER_METADATA_URI_V4", "http://169.254.170.2/v4/metadata")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "/v2/credentials")
+	params := ServerParameters{
+		WebSocketURL: "wss://test.url",
+		ProcessID:    "test-process-id",
+		FleetID:      "test-fleet-id",
+		AwsRegion:    "us-west-2",
+		AccessKey:    "test_access_key",
+		SecretKey:    "test_secret_key",
+	}
+
+	// EXPECT
+	httpClient.EXPECT().
+		Get("http://169.254.170.2/v4/metadata/task").
+		Return(newContainerEndpointResponse(
+			http.StatusOK, `{"TaskARN": "arn:aws:ecs:us-west-2:123456789012:task/test-cluster/test-task-id"}`,
+		), nil)
+	httpClient.EXPECT().
+		Get("http://169.254.170.2/v2/credentials").
+		Return(newContainerEndpointResponse(
+			http.StatusOK, `{"AccessKeyId": "containerAccessKey", "SecretAccessKey": "containerSecretKey"}`,
+		), nil)
+	var signer transport.URLSigner
+	manager.
+		EXPECT().
+		Connect(params.WebSocketURL, params.ProcessID, "test-task-id", params.FleetID, "", gomock.Not(gomock.Nil())).
+		DoAndReturn(func(_, _, _, _, _ string, s transport.URLSigner) error {
+			signer = s
+			return nil
+		})
+
+	// WHEN
+	state := gameLiftServerState{clock: mock.NewFakeClock(time.Now()), httpClient: httpClient}
+	if err := state.init(&params, manager); err != nil {
+		t.Fatal(err)
+	}
+	connectURL, err := internal.NewConnectURL(params.WebSocketURL, params.ProcessID, "test-task-id", params.FleetID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := signer(connectURL)
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(signed.Query().Get(security.AmzCredentialKey), "containerAccessKey/") {
+		t.Errorf("unexpected credential: %s", signed.Query().Get(security.AmzCredentialKey))
+	}
+
+	// The container credentials are rotated by the container credentials endpoint
+	var gameLiftErr *common.GameLiftError
+	err = state.updateAwsCredentials("rotated_access_key", "rotated_secret_key", "")
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.BadRequestException {
+		t.Fatalf("expected BadRequestException, got %v", err)
+	}
+}
+
+// GIVEN OnUnknownMessage callback WHEN a message with an unknown action is received THEN it is counted and passed to the callback
+func TestGameLiftServerState_OnUnknownMessage(t *testing.T) {
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	metricsMock := mock.NewMockIMetrics(ctrl)
+
+	var action string
+	var raw []byte
+	state := &gameLiftServerState{
+		parameters: &ProcessParameters{
+			OnUnknownMessage: func(a string, r []byte) {
+				action, raw = a, r
+			},
+		},
+		metrics: metricsMock,
+	}
+	data := []byte(`{"Action":"DrainGameSession"}`)
+
+	// EXPECT
+	metricsMock.EXPECT().IncrCounter(metrics.UnknownMessages, int64(1)).Times(2)
+
+	// WHEN
+	state.OnUnknownMessage("DrainGameSession", data)
+
+	// THEN
+	assertEqual(t, "DrainGameSession", action)
+	assertEqual(t, string(data), string(raw))
+
+	// WHEN the callback is not set THEN the message is only counted
+	state.parameters = &ProcessParameters{}
+	state.OnUnknownMessage("DrainGameSession", data)
+}
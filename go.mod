@@ -13,4 +13,5 @@ require (
 	github.com/gorilla/websocket v1.5.1 // indirect
 	github.com/sethvargo/go-retry v0.2.4 // indirect
 	golang.org/x/net v0.20.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -10,6 +10,7 @@ func main() {
 	hostIDArg := flag.String("hostID", "", "Compute name with RegisterCompute API")
 	fleetIDArg := flag.String("fleetID", "", "Fleet ID")
 	authTokenArg := flag.String("authToken", "", "Auth Token")
+	authTokenFileArg := flag.String("authTokenFile", "", "Path to the Auth Token file rewritten by an external agent")
 	portArg := flag.String("port", "8080", "Port")
 	fleetTypeArg := flag.String("fleetType", "MANAGED", "Fleet type")
 
@@ -20,6 +21,7 @@ func main() {
 		*hostIDArg,
 		*fleetIDArg,
 		*authTokenArg,
+		*authTokenFileArg,
 		*portArg,
 		*fleetTypeArg,
 	)
@@ -3,7 +3,10 @@ package modules
 import (
 	"aws/amazon-gamelift-go-sdk/model"
 	"aws/amazon-gamelift-go-sdk/model/request"
+	"aws/amazon-gamelift-go-sdk/model/result"
 	"aws/amazon-gamelift-go-sdk/server"
+	authtokenprovider "aws/amazon-gamelift-go-sdk/server/authtoken"
+	"aws/amazon-gamelift-go-sdk/server/credentials"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -71,17 +74,28 @@ func (g gameProcess) OnHealthCheck() bool {
 	return true
 }
 
+// SDK が未対応の GameLift メッセージを受信するコールバック
+func (g gameProcess) OnUnknownMessage(action string, raw []byte) {
+	fmt.Println("Callback: OnUnknownMessage " + action + " " + string(raw))
+}
+
 func describePlayerSessions() string {
-	describePlayerSessionsRequest := request.NewDescribePlayerSessions()
-	describePlayerSessionsRequest.GameSessionID, _ = server.GetGameSessionID() // get ID for the current game session
-	describePlayerSessionsRequest.Limit = 10
+	gameSessionID, _ := server.GetGameSessionID() // get ID for the current game session
+	// Limit is the page size, DescribeAllPlayerSessions follows NextToken to retrieve all pages
+	describePlayerSessionsRequest, err := request.NewDescribePlayerSessionsBuilder().
+		WithGameSessionID(gameSessionID).
+		WithLimit(10).
+		Build()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
 
-	res, err := server.DescribePlayerSessions(describePlayerSessionsRequest)
+	playerSessions, err := server.DescribeAllPlayerSessions(describePlayerSessionsRequest, 0)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	jsonout, err := json.Marshal(res)
+	jsonout, err := json.Marshal(result.DescribePlayerSessionsResult{PlayerSessions: playerSessions})
 
 	return string(jsonout)
 }
@@ -124,8 +138,11 @@ func backfill() {
 		return
 	}
 
-	players := matchMaker.Players
-	players = players[:0+copy(players[0:], players[0+1:])]
+	if len(matchMaker.Players) == 0 {
+		return
+	}
+	// 先頭のプレイヤーが退出したものとしてバックフィルする
+	players := matchMaker.BackfillPlayers(matchMaker.Players[0].PlayerID)
 
 	startBackfillRequest := request.NewStartMatchBackfill(
 		globalgamesession.GameSessionID,
@@ -147,7 +164,7 @@ func backfill() {
 
 var process = gameProcess{}
 
-func setup(fleettype string, websocketurl string, processid string, hostid string, fleetid string, authtoken string, port int, logpath string, shutdownChan chan struct{}) {
+func setup(fleettype string, websocketurl string, processid string, hostid string, fleetid string, authtoken string, authtokenfile string, port int, logpath string, shutdownChan chan struct{}) {
 
 	var param server.ServerParameters
 	if fleettype == "ANYWHERE" {
@@ -159,6 +176,14 @@ func setup(fleettype string, websocketurl string, processid string, hostid strin
 			FleetID:      fleetid,
 			AuthToken:    authtoken,
 		}
+		// authTokenFile が指定された場合は、接続・再接続のたびにファイルから最新の AuthToken を読み込む
+		// authToken も指定されていない場合は、インスタンスプロファイルや認証情報ファイルなどの AWS 認証情報で接続する
+		if authtoken == "" && authtokenfile != "" {
+			param.AuthTokenProvider = authtokenprovider.NewFileProvider(authtokenfile)
+		} else if authtoken == "" {
+			param.AwsRegion = os.Getenv("AWS_REGION")
+			param.CredentialsProvider = credentials.NewDefaultChain()
+		}
 	} else {
 		fmt.Println("FleetTYPE: MAANGED")
 		param = server.ServerParameters{}
@@ -181,6 +206,7 @@ func setup(fleettype string, websocketurl string, processid string, hostid strin
 		OnProcessTerminate:  func() { process.OnProcessTerminate(shutdownChan) },
 		OnUpdateGameSession: process.OnUpdateGameSession,
 		OnHealthCheck:       process.OnHealthCheck,
+		OnUnknownMessage:    process.OnUnknownMessage,
 		Port:                process.Port,
 		LogParameters: server.LogParameters{ // logging and error example
 			LogPaths: []string{logpath},
@@ -15,37 +15,39 @@ import (
 
 // GameLiftConfig 構造体は、GameLift サーバーの設定を保持
 type GameLiftConfig struct {
-	WebSocketURL string
-	ProcessID    string
-	HostID       string
-	FleetID      string
-	AuthToken    string
-	Port         int
-	FleetType    string
+	WebSocketURL  string
+	ProcessID     string
+	HostID        string
+	FleetID       string
+	AuthToken     string
+	AuthTokenFile string
+	Port          int
+	FleetType     string
 }
 
 // AddExampleHTTPServer は、HTTP サーバーを追加する関数
 
-func AddExampleHTTPServer(webSocketURLArg, hostIDArg, fleetIDArg, authTokenArg, portArg, fleetTypeArg string) {
+func AddExampleHTTPServer(webSocketURLArg, hostIDArg, fleetIDArg, authTokenArg, authTokenFileArg, portArg, fleetTypeArg string) {
 	// fleetTypeArg が "MANAGED" であれば、MANAGED フリートを作成する。多くの変数は環境変数で上書きされる
 	if fleetTypeArg == "MANAGED" {
-		addExampleHTTPServer("", "", "", "", portArg, "MANAGED")
+		addExampleHTTPServer("", "", "", "", "", portArg, "MANAGED")
 	} else {
-		addExampleHTTPServer(webSocketURLArg, hostIDArg, fleetIDArg, authTokenArg, portArg, "ANYWHERE")
+		addExampleHTTPServer(webSocketURLArg, hostIDArg, fleetIDArg, authTokenArg, authTokenFileArg, portArg, "ANYWHERE")
 	}
 }
 
 // addExampleHTTPServer は、HTTP サーバーを初期化して起動
-func addExampleHTTPServer(webSocketURLArg, hostIDArg, fleetIDArg, authTokenArg, portArg, fleetTypeArg string) {
+func addExampleHTTPServer(webSocketURLArg, hostIDArg, fleetIDArg, authTokenArg, authTokenFileArg, portArg, fleetTypeArg string) {
 	processUUID, _ := uuid.NewUUID()
 	config := GameLiftConfig{
-		WebSocketURL: webSocketURLArg,
-		ProcessID:    "processid" + processUUID.String(),
-		HostID:       hostIDArg,
-		FleetID:      fleetIDArg,
-		AuthToken:    authTokenArg,
-		Port:         func() int { port, _ := strconv.Atoi(portArg); return port }(),
-		FleetType:    fleetTypeArg,
+		WebSocketURL:  webSocketURLArg,
+		ProcessID:     "processid" + processUUID.String(),
+		HostID:        hostIDArg,
+		FleetID:       fleetIDArg,
+		AuthToken:     authTokenArg,
+		AuthTokenFile: authTokenFileArg,
+		Port:          func() int { port, _ := strconv.Atoi(portArg); return port }(),
+		FleetType:     fleetTypeArg,
 	}
 	flag.Parse()
 
@@ -57,7 +59,7 @@ func addExampleHTTPServer(webSocketURLArg, hostIDArg, fleetIDArg, authTokenArg,
 	}()
 
 	shutdownChan := make(chan struct{})
-	setup(config.FleetType, config.WebSocketURL, config.ProcessID, config.HostID, config.FleetID, config.AuthToken, config.Port, logpath, shutdownChan)
+	setup(config.FleetType, config.WebSocketURL, config.ProcessID, config.HostID, config.FleetID, config.AuthToken, config.AuthTokenFile, config.Port, logpath, shutdownChan)
 	log.Print("[CustomDebug]:end execScripts num goroutine in main: ", runtime.NumGoroutine())
 
 	http.HandleFunc("/", homePage)